@@ -0,0 +1,129 @@
+// Команда importer загружает в каталог артистов, песни и детали из CSV или JSON Lines.
+//
+//	go run ./cmd/importer -file catalogue.csv [-format csv] [-dry-run] [-batch-size 500] [-json]
+//
+// Команда завершается с кодом 1, если хотя бы одна запись не импортирована.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"music-lib/internal/config"
+	"music-lib/internal/lib/importer"
+	"music-lib/internal/storage"
+	"music-lib/internal/storage/audit"
+	"music-lib/internal/storage/pgsql"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+)
+
+func main() {
+	var (
+		path      string
+		format    string
+		dryRun    bool
+		batchSize int
+		asJSON    bool
+	)
+
+	flag.StringVar(&path, "file", "", "Path to a CSV or JSON Lines file, - for stdin")
+	flag.StringVar(&format, "format", "", "csv or jsonl, detected by file extension by default")
+	flag.BoolVar(&dryRun, "dry-run", false, "Validate the file without saving anything")
+	flag.IntVar(&batchSize, "batch-size", importer.DefaultBatchSize, "Records per transaction")
+	flag.BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	flag.Parse()
+
+	if path == "" {
+		log.Fatal("file is required")
+	}
+
+	f, err := importFormat(path, format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Невозможно открыть файл: %v", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	cfg := config.MustLoad()
+
+	store := pgsql.New(cfg)
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Импорт из командной строки тоже попадает в журнал аудита, без исполнителя
+	imports := audit.Wrap(store).Import()
+
+	report, err := importer.New(imports, batchSize).Run(ctx, in, f, dryRun)
+	if err != nil {
+		log.Fatalf("Импорт прерван: %v", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("Невозможно вывести отчёт: %v", err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// importFormat возвращает формат из флага или по расширению файла.
+func importFormat(path, format string) (importer.Format, error) {
+	if format != "" {
+		return importer.ParseFormat(format)
+	}
+	if f, ok := importer.FormatByPath(path); ok {
+		return f, nil
+	}
+
+	return "", fmt.Errorf("format is required for %q: pass -format csv or -format jsonl", path)
+}
+
+// printReport выводит таблицу записей, кроме пропущенных, и итоги.
+func printReport(report importer.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tSTATUS\tARTIST\tSONG\tERROR")
+	for _, row := range report.Rows {
+		if row.Status == storage.ImportSkipped {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", row.Line, row.Status, id(row.ArtistID), id(row.SongID), row.Error)
+	}
+	w.Flush()
+
+	if report.DryRun {
+		fmt.Println("Пробный импорт, ничего не сохранено.")
+	}
+	fmt.Printf("Создано: %d, обновлено: %d, пропущено: %d, с ошибками: %d\n",
+		report.Created, report.Updated, report.Skipped, report.Failed)
+}
+
+func id(v uint) string {
+	if v == 0 {
+		return "-"
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
@@ -2,14 +2,27 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 	"log/slog"
+	"music-lib/internal/clients/songinfo"
 	"music-lib/internal/config"
+	mwRateLimit "music-lib/internal/http/middleware/ratelimit"
 	"music-lib/internal/http/router"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/lib/blob"
+	"music-lib/internal/lib/cover"
+	"music-lib/internal/lib/ratelimit"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"music-lib/internal/storage/audit"
+	"music-lib/internal/storage/memory"
 	"music-lib/internal/storage/pgsql"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -28,11 +41,70 @@ func main() {
 	log := setupLogger(cfg.AppEnv)
 	log.Info("starting server", slog.Any("cfg", cfg.AppUrl))
 
-	// define postgres
-	storage := pgsql.New(cfg)
+	// define storage
+	var store storage.Storage
+	switch cfg.StorageDriver {
+	case config.StorageMemory:
+		log.Warn("using in-memory storage, data will be lost on restart")
+		store = memory.New()
+	default:
+		store = pgsql.New(cfg)
+	}
+
+	// define song info client
+	var songInfo songinfo.Provider
+	if cfg.SongInfoURL != "" {
+		songInfo = songinfo.New(songinfo.Config{
+			BaseURL: cfg.SongInfoURL,
+			Timeout: cfg.SongInfoTimeout,
+			Retries: cfg.SongInfoRetries,
+			Backoff: 200 * time.Millisecond,
+		})
+	} else {
+		log.Warn("SONG_INFO_URL is not set, song enrichment is disabled")
+	}
+
+	// define auth
+	tokens := setupTokens(cfg, log)
+	if cfg.AuthAdminEmail != "" {
+		if err := ensureAdmin(context.Background(), store.Users(), cfg.AuthAdminEmail, cfg.AuthAdminPassword); err != nil {
+			log.Error("failed to create admin", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+
+	// Фоновые задачи останавливаются вместе с сервером
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// define rate limiter
+	limits := mwRateLimit.Limits{
+		IP:            ratelimit.Limit{Rate: cfg.RateLimitIPRPS, Burst: cfg.RateLimitIPBurst},
+		Anonymous:     ratelimit.Limit{Rate: cfg.RateLimitAnonRPS, Burst: cfg.RateLimitAnonBurst},
+		Authenticated: ratelimit.Limit{Rate: cfg.RateLimitAuthRPS, Burst: cfg.RateLimitAuthBurst},
+		Credentials:   ratelimit.Limit{Rate: cfg.RateLimitLoginRPS, Burst: cfg.RateLimitLoginBurst},
+	}
+	rateLimit := setupRateLimit(jobsCtx, cfg, store, limits, log)
+
+	// define audit: изменения каталога через API и очистка корзины записываются в журнал
+	store = audit.Wrap(store)
+
+	// define covers
+	covers, media := setupCovers(cfg, log)
+
+	// define trash purge
+	go purgeTrash(jobsCtx, store.Trash(), covers, cfg.TrashRetention, cfg.TrashPurgeInterval, log)
 
 	// define router
-	routes := router.New(storage, log)
+	routes := router.New(store, tokens, router.Options{
+		SongInfo:        songInfo,
+		SearchThreshold: cfg.SearchThreshold,
+		RateLimit:       rateLimit,
+		RateLimits:      limits,
+		Covers:          covers,
+		Media:           media,
+		TrustedProxies:  cfg.TrustedProxies,
+	}, log)
 
 	// run server
 	server := http.Server{
@@ -67,6 +139,161 @@ func main() {
 	}
 }
 
+func setupTokens(cfg *config.Config, log *slog.Logger) *auth.Tokens {
+	tokenCfg := auth.TokenConfig{
+		Issuer:     cfg.AuthIssuer,
+		AccessTTL:  cfg.AuthAccessTTL,
+		RefreshTTL: cfg.AuthRefreshTTL,
+	}
+
+	if cfg.AuthKeyFile == "" {
+		log.Warn("AUTH_KEY_FILE is not set, using ephemeral key: tokens will not survive restart")
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		return auth.NewTokens(key, tokenCfg)
+	}
+
+	key, err := auth.LoadPrivateKey(cfg.AuthKeyFile)
+	if err != nil {
+		panic(err)
+	}
+
+	return auth.NewTokens(key, tokenCfg)
+}
+
+// setupCovers выбирает хранилище файлов обложек. Для локального хранилища возвращает и
+// хэндлер, который раздаёт файлы по /media/.
+func setupCovers(cfg *config.Config, log *slog.Logger) (*cover.Covers, http.Handler) {
+	coverCfg := cover.Config{
+		MaxBytes: cfg.CoverMaxBytes,
+		// 50 мегапикселей: 200 МБ памяти на распакованную картинку
+		MaxPixels: 50_000_000,
+		MinSide:   16,
+	}
+
+	if cfg.BlobStore == config.BlobS3 {
+		store, err := blob.NewS3(blob.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+			PublicURL:       cfg.BlobPublicURL,
+			Timeout:         30 * time.Second,
+		})
+		if err != nil {
+			panic(err)
+		}
+		return cover.New(store, coverCfg), nil
+	}
+
+	store, err := blob.NewFS(cfg.BlobLocalDir, cfg.BlobPublicURL)
+	if err != nil {
+		panic(err)
+	}
+	log.Info("covers are stored locally", slog.String("dir", cfg.BlobLocalDir))
+
+	return cover.New(store, coverCfg), store.Handler()
+}
+
+// setupRateLimit выбирает хранилище корзин. Для postgres запускается фоновая очистка
+// корзин, которые простояли дольше самого длинного окна и потому уже полны.
+func setupRateLimit(
+	ctx context.Context, cfg *config.Config, store storage.Storage, limits mwRateLimit.Limits, log *slog.Logger,
+) ratelimit.Store {
+	switch cfg.RateLimitStore {
+	case config.RateLimitOff:
+		log.Warn("rate limiting is disabled")
+		return nil
+	case config.RateLimitMemory:
+		return ratelimit.NewMemoryStore()
+	}
+
+	buckets := store.(*pgsql.Storage).RateLimits()
+	idle := max(
+		limits.IP.Window(), limits.Anonymous.Window(), limits.Authenticated.Window(), limits.Credentials.Window(),
+		time.Minute,
+	)
+
+	go every(ctx, idle, func() {
+		n, err := buckets.Purge(ctx, idle)
+		if err != nil {
+			log.Warn("failed to purge rate limit buckets", slog.Any("error", err))
+			return
+		}
+		log.Debug("rate limit buckets purged", slog.Int64("count", n))
+	})
+
+	return buckets
+}
+
+// purgeTrash раз в interval окончательно удаляет записи, пролежавшие в корзине дольше retention,
+// и файлы их обложек. Первая очистка выполняется сразу при старте.
+func purgeTrash(
+	ctx context.Context, trash storage.TrashRepository, covers *cover.Covers, retention, interval time.Duration, log *slog.Logger,
+) {
+	purge := func() {
+		res, err := trash.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Error("failed to purge trash", slog.Any("error", err))
+			return
+		}
+		for i := range res.Covers {
+			if err := covers.Delete(context.WithoutCancel(ctx), &res.Covers[i]); err != nil {
+				log.Warn("failed to delete cover files", slog.Any("error", err))
+			}
+		}
+		if res.Artists > 0 || res.Songs > 0 {
+			log.Info("trash purged", slog.Int64("artists", res.Artists), slog.Int64("songs", res.Songs))
+		}
+	}
+
+	purge()
+	every(ctx, interval, purge)
+}
+
+// every вызывает fn раз в interval, пока не отменён ctx.
+func every(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+// ensureAdmin создаёт администратора с указанным email или повышает до администратора
+// существующего пользователя. Пароль существующего пользователя не меняется.
+func ensureAdmin(ctx context.Context, users storage.UserRepository, email, password string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	user, err := users.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if user.Role == models.RoleAdmin {
+			return nil
+		}
+		return users.SetRole(ctx, user.ID, models.RoleAdmin)
+	case !errors.Is(err, storage.ErrUserNotFound):
+		return err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return users.Create(ctx, &models.User{Email: email, PasswordHash: hash, Role: models.RoleAdmin})
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
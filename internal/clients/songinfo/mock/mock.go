@@ -0,0 +1,82 @@
+// Package mock содержит HTTP-сервер, имитирующий внешнее API сведений о песнях.
+package mock
+
+import (
+	"music-lib/internal/clients/songinfo"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/go-chi/render"
+)
+
+// Server отвечает на GET /info?group=&song= заранее добавленными данными.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	songs    map[key]songinfo.Info
+	failures int
+	requests int
+}
+
+type key struct {
+	group string
+	song  string
+}
+
+// NewServer запускает мок-сервер. Его нужно закрыть вызовом Close.
+func NewServer() *Server {
+	s := &Server{songs: make(map[key]songinfo.Info)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Add регистрирует ответ для пары группа/песня.
+func (s *Server) Add(group, song string, info songinfo.Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.songs[key{group, song}] = info
+}
+
+// FailNext заставляет сервер ответить 500 на следующие n запросов.
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+// Requests возвращает количество полученных запросов.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	info, ok := s.songs[key{r.URL.Query().Get("group"), r.URL.Query().Get("song")}]
+	s.mu.Unlock()
+
+	switch {
+	case r.Method != http.MethodGet || r.URL.Path != "/info":
+		http.Error(w, "Not Found", http.StatusNotFound)
+	case fail:
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	case r.URL.Query().Get("group") == "" || r.URL.Query().Get("song") == "":
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+	case !ok:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	default:
+		render.JSON(w, r, info)
+	}
+}
@@ -0,0 +1,155 @@
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"music-lib/internal/lib/date"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResponseSize ограничивает тело ответа: текст песни занимает десятки килобайт, а
+// больший ответ не дочитывается и считается ошибочным.
+const maxResponseSize = 1 << 20
+
+var (
+	ErrNotFound    = errors.New("song info not found")
+	ErrBadResponse = errors.New("unexpected song info response")
+)
+
+// Info - сведения о песне, возвращаемые внешним API.
+type Info struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// ReleaseTime разбирает дату релиза в формате DD.MM.YYYY или YYYY-MM-DD.
+func (i Info) ReleaseTime() (time.Time, error) {
+	t, err := date.Parse(i.ReleaseDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid release date %q", ErrBadResponse, i.ReleaseDate)
+	}
+
+	return t, nil
+}
+
+// Provider - источник сведений о песне. Позволяет подменить HTTP-клиент в тестах.
+type Provider interface {
+	Info(ctx context.Context, group, song string) (Info, error)
+}
+
+// Config содержит параметры подключения к внешнему API.
+type Config struct {
+	BaseURL string
+	// Timeout ограничивает одну попытку запроса.
+	Timeout time.Duration
+	// Retries - количество повторов после первой неудачной попытки.
+	Retries int
+	// Backoff - начальная пауза между повторами, удваивается с каждой попыткой.
+	Backoff time.Duration
+}
+
+// Client обращается к внешнему API за сведениями о песне.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// New создаёт клиент внешнего API.
+func New(cfg Config) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		http:    &http.Client{Timeout: cfg.Timeout},
+		retries: max(cfg.Retries, 0),
+		backoff: cfg.Backoff,
+	}
+}
+
+// Info запрашивает GET /info?group=&song= с повторами при сетевых ошибках и ответах 5xx/429.
+func (c *Client) Info(ctx context.Context, group, song string) (Info, error) {
+	const op = "songinfo.Info"
+
+	q := url.Values{}
+	q.Set("group", group)
+	q.Set("song", song)
+	endpoint := c.baseURL + "/info?" + q.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return Info{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		info, retry, err := c.do(ctx, endpoint)
+		if err == nil {
+			return info, nil
+		}
+		if !retry {
+			return Info{}, fmt.Errorf("%s: %w", op, err)
+		}
+		lastErr = err
+	}
+
+	return Info{}, fmt.Errorf("%s: %d attempts failed: %w", op, c.retries+1, lastErr)
+}
+
+// do выполняет одну попытку запроса и сообщает, имеет ли смысл повторить её.
+func (c *Client) do(ctx context.Context, endpoint string) (info Info, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Info{}, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// Отмена контекста вызывающей стороной повторять бессмысленно
+		return Info{}, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return Info{}, false, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return Info{}, true, fmt.Errorf("%w: status %d", ErrBadResponse, resp.StatusCode)
+	default:
+		return Info{}, false, fmt.Errorf("%w: status %d", ErrBadResponse, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&info); err != nil {
+		return Info{}, false, fmt.Errorf("%w: %v", ErrBadResponse, err)
+	}
+
+	return info, false, nil
+}
+
+// wait выдерживает экспоненциальную паузу с небольшим джиттером перед повтором.
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoff << (attempt - 1)
+	if delay > 0 {
+		delay += time.Duration(rand.Int64N(int64(delay)/4 + 1))
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package songinfo_test
+
+import (
+	"context"
+	"errors"
+	"music-lib/internal/clients/songinfo"
+	"music-lib/internal/clients/songinfo/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newClient(url string, retries int) *songinfo.Client {
+	return songinfo.New(songinfo.Config{
+		BaseURL: url,
+		Timeout: time.Second,
+		Retries: retries,
+		Backoff: time.Millisecond,
+	})
+}
+
+func TestClientInfo(t *testing.T) {
+	want := songinfo.Info{
+		ReleaseDate: "16.07.2006",
+		Text:        "Ooh baby, don't you know I suffer?",
+		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	}
+
+	tests := []struct {
+		name     string
+		failures int
+		retries  int
+		song     string
+		wantErr  error
+		wantReqs int
+	}{
+		{name: "ok", song: "Supermassive Black Hole", wantReqs: 1},
+		{name: "recovers after retries", song: "Supermassive Black Hole", failures: 2, retries: 2, wantReqs: 3},
+		{name: "gives up after retries", song: "Supermassive Black Hole", failures: 3, retries: 2, wantErr: songinfo.ErrBadResponse, wantReqs: 3},
+		{name: "not found is not retried", song: "Unknown", retries: 2, wantErr: songinfo.ErrNotFound, wantReqs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := mock.NewServer()
+			defer srv.Close()
+
+			srv.Add("Muse", "Supermassive Black Hole", want)
+			srv.FailNext(tt.failures)
+
+			got, err := newClient(srv.URL, tt.retries).Info(context.Background(), "Muse", tt.song)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Info() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != want {
+				t.Errorf("Info() = %+v, want %+v", got, want)
+			}
+			if srv.Requests() != tt.wantReqs {
+				t.Errorf("requests = %d, want %d", srv.Requests(), tt.wantReqs)
+			}
+		})
+	}
+}
+
+func TestClientInfoTooLarge(t *testing.T) {
+	srv := mock.NewServer()
+	defer srv.Close()
+
+	srv.Add("Muse", "Uprising", songinfo.Info{ReleaseDate: "07.09.2009", Text: strings.Repeat("la ", 1<<20)})
+
+	_, err := newClient(srv.URL, 0).Info(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, songinfo.ErrBadResponse) {
+		t.Errorf("Info() error = %v, want %v", err, songinfo.ErrBadResponse)
+	}
+}
+
+func TestInfoReleaseTime(t *testing.T) {
+	want := time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC)
+
+	for _, date := range []string{"16.07.2006", "2006-07-16"} {
+		got, err := songinfo.Info{ReleaseDate: date}.ReleaseTime()
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ReleaseTime(%q) = %v, %v; want %v", date, got, err, want)
+		}
+	}
+
+	if _, err := (songinfo.Info{ReleaseDate: "July 2006"}).ReleaseTime(); err == nil {
+		t.Error("ReleaseTime() expected error for unknown format")
+	}
+}
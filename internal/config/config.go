@@ -1,13 +1,37 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Хранилища файлов (обложек)
+const (
+	BlobLocal = "local"
+	BlobS3    = "s3"
+)
+
+// Хранилища корзин ограничителя частоты запросов
+const (
+	RateLimitOff      = "off"
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+)
+
 type Config struct {
 	AppEnv     string
 	AppUrl     string
@@ -20,7 +44,69 @@ type Config struct {
 
 	DBSSLMode string
 
+	// StorageDriver - postgres или memory (данные живут только до перезапуска)
+	StorageDriver string
+
 	LogLevel string
+
+	// Внешнее API сведений о песнях. Пустой SongInfoURL отключает обогащение.
+	SongInfoURL     string
+	SongInfoTimeout time.Duration
+	SongInfoRetries int
+
+	// SearchThreshold - порог сходства pg_trgm от 0 до 1 для нечёткого поиска и подсказок
+	SearchThreshold float64
+
+	// AuthKeyFile - ключ Ed25519 в PEM для подписи JWT. Без него в local-окружении
+	// генерируется временный ключ, и токены перестают действовать после перезапуска.
+	AuthKeyFile    string
+	AuthIssuer     string
+	AuthAccessTTL  time.Duration
+	AuthRefreshTTL time.Duration
+
+	// AuthAdminEmail и AuthAdminPassword создают администратора при старте, если его ещё нет
+	AuthAdminEmail    string
+	AuthAdminPassword string
+
+	// RateLimitStore - off, memory (лимит на каждую реплику) или postgres (общий лимит)
+	RateLimitStore string
+	// Лимиты в запросах в секунду и ёмкость корзины для анонимных и аутентифицированных клиентов
+	RateLimitAnonRPS   float64
+	RateLimitAnonBurst int
+	RateLimitAuthRPS   float64
+	RateLimitAuthBurst int
+	// Общий лимит одного IP, который проверяется до аутентификации
+	RateLimitIPRPS   float64
+	RateLimitIPBurst int
+	// Лимит одного IP на вход и регистрацию
+	RateLimitLoginRPS   float64
+	RateLimitLoginBurst int
+
+	// TrustedProxies - адреса и подсети прокси, которым доверяются X-Forwarded-For и X-Real-IP.
+	// Пусто - адрес клиента берётся только из соединения.
+	TrustedProxies []netip.Prefix
+
+	// TrashRetention - сколько удалённые артисты и песни хранятся в корзине до окончательного удаления
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// BlobStore - local (каталог BlobLocalDir, файлы раздаёт приложение по /media/) или s3
+	BlobStore    string
+	BlobLocalDir string
+	// BlobPublicURL - адрес, по которому клиенты получают файлы. Для local по умолчанию
+	// http://APP_URL:APP_PORT/media, для s3 - адрес объекта в бакете.
+	BlobPublicURL string
+
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// S3PathStyle - адресовать бакет путём, а не поддоменом; нужно для MinIO
+	S3PathStyle bool
+
+	// CoverMaxBytes - наибольший размер загружаемой обложки
+	CoverMaxBytes int64
 }
 
 func MustLoad() *Config {
@@ -46,19 +132,204 @@ func MustLoad() *Config {
 	config.DBPassword = getEnv("DB_PASSWORD", "")
 	config.DBName = getEnv("DB_NAME", "")
 	config.DBSSLMode = getEnv("DB_SSLMODE", "disable")
+	config.StorageDriver = getEnv("STORAGE_DRIVER", StoragePostgres)
 
 	config.LogLevel = getEnv("LOG_LEVEL", "info")
 
-	if config.DBHost == "" || config.DBUser == "" || config.DBPassword == "" || config.DBName == "" {
+	config.SongInfoURL = getEnv("SONG_INFO_URL", "")
+	config.SongInfoTimeout, err = time.ParseDuration(getEnv("SONG_INFO_TIMEOUT", "3s"))
+	if err != nil {
+		panic(err)
+	}
+	config.SongInfoRetries, err = strconv.Atoi(getEnv("SONG_INFO_RETRIES", "2"))
+	if err != nil {
+		panic(err)
+	}
+
+	config.SearchThreshold, err = strconv.ParseFloat(getEnv("SEARCH_SIMILARITY_THRESHOLD", "0.3"), 64)
+	if err != nil {
+		panic(err)
+	}
+	if config.SearchThreshold <= 0 || config.SearchThreshold > 1 {
+		panic("SEARCH_SIMILARITY_THRESHOLD должен быть в диапазоне (0, 1]")
+	}
+
+	config.AuthKeyFile = getEnv("AUTH_KEY_FILE", "")
+	config.AuthIssuer = getEnv("AUTH_ISSUER", "music-lib")
+	config.AuthAccessTTL, err = time.ParseDuration(getEnv("AUTH_ACCESS_TTL", "15m"))
+	if err != nil {
+		panic(err)
+	}
+	config.AuthRefreshTTL, err = time.ParseDuration(getEnv("AUTH_REFRESH_TTL", "720h"))
+	if err != nil {
+		panic(err)
+	}
+	config.AuthAdminEmail = getEnv("AUTH_ADMIN_EMAIL", "")
+	config.AuthAdminPassword = getEnv("AUTH_ADMIN_PASSWORD", "")
+
+	config.RateLimitStore = getEnv("RATE_LIMIT_STORE", RateLimitMemory)
+	config.RateLimitAnonRPS, err = strconv.ParseFloat(getEnv("RATE_LIMIT_ANON_RPS", "5"), 64)
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitAnonBurst, err = strconv.Atoi(getEnv("RATE_LIMIT_ANON_BURST", "20"))
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitAuthRPS, err = strconv.ParseFloat(getEnv("RATE_LIMIT_AUTH_RPS", "20"), 64)
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitAuthBurst, err = strconv.Atoi(getEnv("RATE_LIMIT_AUTH_BURST", "100"))
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitIPRPS, err = strconv.ParseFloat(getEnv("RATE_LIMIT_IP_RPS", "50"), 64)
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitIPBurst, err = strconv.Atoi(getEnv("RATE_LIMIT_IP_BURST", "200"))
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitLoginRPS, err = strconv.ParseFloat(getEnv("RATE_LIMIT_LOGIN_RPS", "0.1"), 64)
+	if err != nil {
+		panic(err)
+	}
+	config.RateLimitLoginBurst, err = strconv.Atoi(getEnv("RATE_LIMIT_LOGIN_BURST", "10"))
+	if err != nil {
+		panic(err)
+	}
+	config.TrustedProxies, err = parsePrefixes(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		panic(err)
+	}
+
+	config.TrashRetention, err = time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil {
+		panic(err)
+	}
+	config.TrashPurgeInterval, err = time.ParseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h"))
+	if err != nil {
+		panic(err)
+	}
+	if config.TrashRetention <= 0 || config.TrashPurgeInterval <= 0 {
+		panic("TRASH_RETENTION и TRASH_PURGE_INTERVAL должны быть положительными")
+	}
+
+	config.BlobStore = getEnv("BLOB_STORE", BlobLocal)
+	config.BlobLocalDir = getEnv("BLOB_LOCAL_DIR", "./data/blobs")
+	config.BlobPublicURL = getEnv("BLOB_PUBLIC_URL", "")
+	if config.BlobPublicURL == "" && config.BlobStore == BlobLocal {
+		config.BlobPublicURL = "http://" + net.JoinHostPort(config.AppUrl, config.AppPort) + "/media"
+	}
+	config.S3Endpoint = getEnv("S3_ENDPOINT", "")
+	config.S3Region = getEnv("S3_REGION", "us-east-1")
+	config.S3Bucket = getEnv("S3_BUCKET", "")
+	config.S3AccessKeyID = getEnv("S3_ACCESS_KEY_ID", "")
+	config.S3SecretAccessKey = getEnv("S3_SECRET_ACCESS_KEY", "")
+	config.S3PathStyle, err = strconv.ParseBool(getEnv("S3_PATH_STYLE", "false"))
+	if err != nil {
+		panic(err)
+	}
+	config.CoverMaxBytes, err = strconv.ParseInt(getEnv("COVER_MAX_BYTES", "10485760"), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	if config.CoverMaxBytes <= 0 {
+		panic("COVER_MAX_BYTES должен быть положительным")
+	}
+
+	if config.AuthKeyFile == "" && config.AppEnv != "local" {
+		panic("AUTH_KEY_FILE обязателен вне local-окружения")
+	}
+	if (config.AuthAdminEmail == "") != (config.AuthAdminPassword == "") {
+		panic("AUTH_ADMIN_EMAIL и AUTH_ADMIN_PASSWORD задаются вместе")
+	}
+
+	if config.StorageDriver != StoragePostgres && config.StorageDriver != StorageMemory {
+		panic("неизвестный STORAGE_DRIVER: " + config.StorageDriver)
+	}
+
+	switch config.RateLimitStore {
+	case RateLimitOff, RateLimitMemory:
+	case RateLimitPostgres:
+		if config.StorageDriver != StoragePostgres {
+			panic("RATE_LIMIT_STORE=postgres требует STORAGE_DRIVER=postgres")
+		}
+	default:
+		panic("неизвестный RATE_LIMIT_STORE: " + config.RateLimitStore)
+	}
+	switch config.BlobStore {
+	case BlobLocal:
+	case BlobS3:
+		if config.S3Endpoint == "" || config.S3Bucket == "" || config.S3AccessKeyID == "" || config.S3SecretAccessKey == "" {
+			panic("BLOB_STORE=s3 требует S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID и S3_SECRET_ACCESS_KEY")
+		}
+	default:
+		panic("неизвестный BLOB_STORE: " + config.BlobStore)
+	}
+
+	if config.RateLimitAnonRPS <= 0 || config.RateLimitAuthRPS <= 0 ||
+		config.RateLimitIPRPS <= 0 || config.RateLimitLoginRPS <= 0 ||
+		config.RateLimitAnonBurst < 1 || config.RateLimitAuthBurst < 1 ||
+		config.RateLimitIPBurst < 1 || config.RateLimitLoginBurst < 1 {
+		panic("лимиты RATE_LIMIT_* должны быть положительными")
+	}
+
+	if config.StorageDriver == StoragePostgres &&
+		(config.DBHost == "" || config.DBUser == "" || config.DBPassword == "" || config.DBName == "") {
 		panic("необходимые параметры базы данных отсутствуют")
 	}
 
 	return config
 }
 
+// DatabaseURL возвращает адрес базы в формате postgres://. Его понимают и GORM, и golang-migrate;
+// соединение работает в часовом поясе UTC.
+func (c *Config) DatabaseURL() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.DBUser, c.DBPassword),
+		Host:     net.JoinHostPort(c.DBHost, strconv.Itoa(c.DBPort)),
+		Path:     "/" + c.DBName,
+		RawQuery: url.Values{"sslmode": {c.DBSSLMode}, "TimeZone": {"UTC"}}.Encode(),
+	}
+
+	return u.String()
+}
+
 func getEnv(key, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultVal
 }
+
+// parsePrefixes разбирает список подсетей CIDR и отдельных адресов через запятую.
+func parsePrefixes(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
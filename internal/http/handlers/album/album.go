@@ -0,0 +1,264 @@
+package album
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/date"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	includeTracks = "tracks"
+)
+
+type AlbumHandlers struct {
+	albums  storage.AlbumRepository
+	artists storage.ArtistRepository
+	logger  *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	Albums []models.Album `json:"albums"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type ResponseSingle struct {
+	response.Response
+	Album models.Album `json:"album"`
+}
+
+func NewAlbumHandlers(albums storage.AlbumRepository, artists storage.ArtistRepository, logger *slog.Logger) *AlbumHandlers {
+	return &AlbumHandlers{albums: albums, artists: artists, logger: logger}
+}
+
+// List возвращает страницу альбомов, новые релизы первыми. Фильтры: artist_id и release_type
+func (h *AlbumHandlers) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter storage.AlbumFilter
+		err    error
+	)
+
+	filter.Limit, filter.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if filter.ArtistID, err = request.QueryID(r, "artist_id"); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if v := r.URL.Query().Get("release_type"); v != "" {
+		if !validReleaseType(v) {
+			response.RenderError(w, r, h.logger, request.BadRequest("invalid release_type %q, allowed: lp, ep, single, compilation", v))
+			return
+		}
+		filter.ReleaseType = models.ReleaseType(v)
+	}
+
+	page, err := h.albums.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response: response.OK(),
+		Albums:   page.Albums,
+		Total:    page.Total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+}
+
+// RequestCreate - новый альбом. Дата принимается в формате DD.MM.YYYY или YYYY-MM-DD.
+// artist_id не обязателен только для сборников.
+type RequestCreate struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	ArtistID    uint   `json:"artist_id" validate:"required_unless=ReleaseType compilation"`
+	ReleaseType string `json:"release_type" validate:"required,oneof=lp ep single compilation"`
+	ReleaseDate string `json:"release_date" validate:"omitempty,date"`
+	CoverURL    string `json:"cover_url" validate:"omitempty,url,max=1024"`
+}
+
+// Create создаёт альбом
+func (h *AlbumHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req RequestCreate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	album := models.Album{
+		Title:       req.Title,
+		ReleaseType: models.ReleaseType(req.ReleaseType),
+		ReleaseDate: parseDate(req.ReleaseDate),
+		CoverURL:    req.CoverURL,
+	}
+	if req.ArtistID != 0 {
+		artist, err := h.artists.Get(r.Context(), req.ArtistID)
+		if err != nil {
+			response.RenderError(w, r, h.logger, err)
+			return
+		}
+		album.ArtistID = &artist.ID
+	}
+
+	if err := h.albums.Create(r.Context(), &album); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Album:    album,
+	})
+}
+
+// Get возвращает альбом по ID. include=tracks добавляет артиста и треклист
+func (h *AlbumHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	include, err := request.Include(r, includeTracks)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	get := h.albums.Get
+	if include[includeTracks] {
+		get = h.albums.GetWithTracks
+	}
+
+	album, err := get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Album:    album,
+	})
+}
+
+// RequestUpdate содержит изменяемые поля. Незаданные поля остаются без изменений,
+// пустые release_date и cover_url очищают значение, artist_id 0 убирает артиста у сборника.
+type RequestUpdate struct {
+	Title       string  `json:"title" validate:"omitempty,max=255"`
+	ArtistID    *uint   `json:"artist_id"`
+	ReleaseType string  `json:"release_type" validate:"omitempty,oneof=lp ep single compilation"`
+	ReleaseDate *string `json:"release_date" validate:"omitempty,date"`
+	CoverURL    *string `json:"cover_url" validate:"omitempty,url,max=1024"`
+}
+
+// Update изменяет альбом по ID
+func (h *AlbumHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestUpdate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	album, err := h.albums.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if req.Title != "" {
+		album.Title = req.Title
+	}
+	if req.ReleaseType != "" {
+		album.ReleaseType = models.ReleaseType(req.ReleaseType)
+	}
+	if req.ReleaseDate != nil {
+		album.ReleaseDate = parseDate(*req.ReleaseDate)
+	}
+	if req.CoverURL != nil {
+		album.CoverURL = *req.CoverURL
+	}
+	if req.ArtistID != nil {
+		album.ArtistID = nil
+		if *req.ArtistID != 0 {
+			artist, err := h.artists.Get(r.Context(), *req.ArtistID)
+			if err != nil {
+				response.RenderError(w, r, h.logger, err)
+				return
+			}
+			album.ArtistID = &artist.ID
+		}
+	}
+	if album.ArtistID == nil && album.ReleaseType != models.ReleaseCompilation {
+		response.RenderError(w, r, h.logger, request.BadRequest("artist_id is required unless release_type is compilation"))
+		return
+	}
+
+	if err := h.albums.Update(r.Context(), &album); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Album:    album,
+	})
+}
+
+// Delete удаляет альбом по ID. Песни альбома остаются в каталоге
+func (h *AlbumHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.albums.Delete(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+func validReleaseType(v string) bool {
+	switch models.ReleaseType(v) {
+	case models.ReleaseLP, models.ReleaseEP, models.ReleaseSingle, models.ReleaseCompilation:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseDate разбирает уже проверенную тегом date дату; пустая строка - отсутствие даты.
+func parseDate(s string) *time.Time {
+	t, err := date.Parse(s)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
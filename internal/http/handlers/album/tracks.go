@@ -0,0 +1,73 @@
+package album
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type RequestTrack struct {
+	SongID uint `json:"song_id" validate:"required"`
+	// DiscNumber по умолчанию 1.
+	DiscNumber  int `json:"disc_number" validate:"omitempty,min=1,max=99"`
+	TrackNumber int `json:"track_number" validate:"required,min=1,max=999"`
+}
+
+// RequestTracks - треклист альбома целиком.
+type RequestTracks struct {
+	Tracks []RequestTrack `json:"tracks" validate:"max=500,dive"`
+}
+
+// SetTracks заменяет треклист альбома. Песни, которых нет в запросе, отвязываются от альбома
+func (h *AlbumHandlers) SetTracks(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestTracks
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	tracks := make([]storage.Track, len(req.Tracks))
+	songs := make(map[uint]bool, len(req.Tracks))
+	positions := make(map[storage.Track]bool, len(req.Tracks))
+	for i, t := range req.Tracks {
+		tracks[i] = storage.Track{SongID: t.SongID, DiscNumber: max(t.DiscNumber, 1), TrackNumber: t.TrackNumber}
+
+		if songs[t.SongID] {
+			response.RenderError(w, r, h.logger, request.BadRequest("song %d is listed twice", t.SongID))
+			return
+		}
+		songs[t.SongID] = true
+
+		pos := storage.Track{DiscNumber: tracks[i].DiscNumber, TrackNumber: tracks[i].TrackNumber}
+		if positions[pos] {
+			response.RenderError(w, r, h.logger, request.BadRequest("disc %d track %d is listed twice", pos.DiscNumber, pos.TrackNumber))
+			return
+		}
+		positions[pos] = true
+	}
+
+	if err := h.albums.SetTracks(r.Context(), id, tracks); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	album, err := h.albums.GetWithTracks(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Album:    album,
+	})
+}
@@ -0,0 +1,203 @@
+package apikey
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// maxGrace - наибольший срок, в течение которого старый ключ работает после ротации.
+const maxGrace = 7 * 24 * time.Hour
+
+type APIKeyHandlers struct {
+	keys   storage.APIKeyRepository
+	logger *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	APIKeys []models.APIKey `json:"api_keys"`
+}
+
+type ResponseSingle struct {
+	response.Response
+	APIKey models.APIKey `json:"api_key"`
+}
+
+// ResponseIssued содержит сам ключ. Он показывается только один раз.
+type ResponseIssued struct {
+	response.Response
+	APIKey models.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+func NewAPIKeyHandlers(keys storage.APIKeyRepository, logger *slog.Logger) *APIKeyHandlers {
+	return &APIKeyHandlers{keys: keys, logger: logger}
+}
+
+// List возвращает все ключи без секретов
+func (h *APIKeyHandlers) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response: response.OK(),
+		APIKeys:  keys,
+	})
+}
+
+// RequestCreate - новый ключ. Без expires_at ключ бессрочный.
+type RequestCreate struct {
+	Name      string         `json:"name" validate:"required,max=255"`
+	Scopes    []models.Scope `json:"scopes" validate:"required,min=1,max=3,dive,oneof=songs:read songs:write admin"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
+
+// Create выпускает ключ. Ответ содержит сам ключ - позже его получить нельзя
+func (h *APIKeyHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req RequestCreate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		response.RenderError(w, r, h.logger, request.BadRequest("expires_at must be in the future"))
+		return
+	}
+
+	key := models.APIKey{Name: req.Name, Scopes: req.Scopes, ExpiresAt: req.ExpiresAt}
+	if id, ok := request.UserID(r); ok {
+		key.CreatedBy = &id
+	}
+
+	raw, err := h.issue(&key)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := h.keys.Create(r.Context(), &key); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	renderIssued(w, r, http.StatusCreated, key, raw)
+}
+
+// Get возвращает ключ по ID без секрета
+func (h *APIKeyHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	key, err := h.keys.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		APIKey:   key,
+	})
+}
+
+// Rotate выпускает замену ключа с теми же именем, scope и сроком действия. Старый ключ
+// продолжает работать ещё grace (по умолчанию 0, не больше 168h), чтобы клиенты успели
+// перейти на новый. Истёкший ключ не ротируется (409): вместо него выпускается новый
+func (h *APIKeyHandlers) Rotate(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var grace time.Duration
+	if v := r.URL.Query().Get("grace"); v != "" {
+		grace, err = time.ParseDuration(v)
+		if err != nil || grace < 0 || grace > maxGrace {
+			response.RenderError(w, r, h.logger, request.BadRequest("grace must be a duration between 0 and 168h"))
+			return
+		}
+	}
+
+	old, err := h.keys.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if old.Expired(time.Now()) {
+		response.RenderError(w, r, h.logger, storage.ErrAPIKeyExpired)
+		return
+	}
+
+	next := models.APIKey{Name: old.Name, Scopes: old.Scopes, ExpiresAt: old.ExpiresAt}
+	if userID, ok := request.UserID(r); ok {
+		next.CreatedBy = &userID
+	}
+	raw, err := h.issue(&next)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	// Ротация не продлевает старый ключ, если он истекает раньше
+	oldExpiresAt := time.Now().UTC().Add(grace)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *old.ExpiresAt
+	}
+	if err := h.keys.Rotate(r.Context(), id, &next, oldExpiresAt); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	renderIssued(w, r, http.StatusCreated, next, raw)
+}
+
+// Delete отзывает ключ сразу
+func (h *APIKeyHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.keys.Delete(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// issue генерирует секрет ключа и заполняет Prefix и Hash.
+func (h *APIKeyHandlers) issue(key *models.APIKey) (string, error) {
+	raw, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return "", err
+	}
+	key.Prefix, key.Hash = prefix, hash
+
+	return raw, nil
+}
+
+func renderIssued(w http.ResponseWriter, r *http.Request, status int, key models.APIKey, raw string) {
+	w.Header().Set("Cache-Control", "no-store")
+	render.Status(r, status)
+	render.JSON(w, r, ResponseIssued{
+		Response: response.OK(),
+		APIKey:   key,
+		Key:      raw,
+	})
+}
@@ -1,21 +1,24 @@
 package artist
 
 import (
-	"errors"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"log/slog"
+	"music-lib/internal/lib/api/request"
 	"music-lib/internal/lib/api/response"
 	"music-lib/internal/models"
-	"music-lib/internal/storage/pgsql"
+	"music-lib/internal/storage"
 	"net/http"
-	"strconv"
+)
 
-	"github.com/go-chi/chi/v5"
+// Значения параметра include. songs.details подразумевает songs.
+const (
+	includeSongs       = "songs"
+	includeSongDetails = "songs.details"
 )
 
 type ArtistHandlers struct {
-	storage *pgsql.Storage
+	artists storage.ArtistRepository
+	exports storage.ExportRepository
 	logger  *slog.Logger
 }
 
@@ -29,16 +32,22 @@ type ResponseSingle struct {
 	Artist models.Artist `json:"artist,omitempty"`
 }
 
-func NewArtistHandlers(storage *pgsql.Storage, logger *slog.Logger) *ArtistHandlers {
-	return &ArtistHandlers{storage: storage, logger: logger}
+func NewArtistHandlers(artists storage.ArtistRepository, exports storage.ExportRepository, logger *slog.Logger) *ArtistHandlers {
+	return &ArtistHandlers{artists: artists, exports: exports, logger: logger}
 }
 
-// List возвращает список всех артистов
+// List возвращает список всех артистов. Песни всех артистов разом не отдаются: include
+// отклоняется, песни артиста постранично возвращает /artists/{id}/songs
 func (h *ArtistHandlers) List(w http.ResponseWriter, r *http.Request) {
-	var artists []models.Artist
-	if err := h.storage.DB.Find(&artists).Error; err != nil {
-		h.logger.Error("failed to list artists", slog.Any("error", err))
-		render.JSON(w, r, response.Error("fail to load artists"))
+	if r.URL.Query().Has("include") {
+		response.RenderError(w, r, h.logger,
+			request.BadRequest("include is not supported on the artist list, use /artists/{id}/songs"))
+		return
+	}
+
+	artists, err := h.artists.List(r.Context())
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
@@ -49,50 +58,53 @@ func (h *ArtistHandlers) List(w http.ResponseWriter, r *http.Request) {
 }
 
 type RequestCreate struct {
-	Name    string `json:"name" validate:"required,name"`
-	IsGroup bool   `json:"is_group" validate:"omitempty"`
+	Name    string `json:"name" validate:"required,max=255"`
+	IsGroup bool   `json:"is_group"`
 }
 
 // Create создает нового артиста
 func (h *ArtistHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	var req RequestCreate
-
-	if err := validator.New().Struct(req); err != nil {
-		var validateErr validator.ValidationErrors
-		errors.As(err, &validateErr)
-
-		h.logger.Error("failed to validate request", err)
-
-		render.JSON(w, r, response.ValidationError(validateErr))
-
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	artist := models.Artist{Name: req.Name, IsGroup: req.IsGroup}
-	if err := h.storage.DB.Create(&artist).Error; err != nil {
-		h.logger.Error("failed to create artist", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if err := h.artists.Create(r.Context(), &artist); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
+	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, ResponseSingle{
 		Response: response.OK(),
 		Artist:   artist,
 	})
 }
 
-// Get возвращает артиста по ID
+// Get возвращает артиста по ID. include=songs и include=songs.details добавляют песни
 func (h *ArtistHandlers) Get(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	include, err := request.Include(r, includeSongs, includeSongDetails)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	var artist models.Artist
-	if err := h.storage.DB.First(&artist, id).Error; err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
+	if include[includeSongs] || include[includeSongDetails] {
+		artist, err = h.artists.GetWithSongs(r.Context(), id, include[includeSongDetails])
+	} else {
+		artist, err = h.artists.Get(r.Context(), id)
+	}
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
@@ -102,44 +114,40 @@ func (h *ArtistHandlers) Get(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// RequestUpdate содержит изменяемые поля. Незаданные поля остаются без изменений.
 type RequestUpdate struct {
-	Name    string `json:"name" validate:"omitempty,name"`
-	IsGroup bool   `json:"is_group" validate:"omitempty,is_group"`
+	Name    string `json:"name" validate:"omitempty,max=255"`
+	IsGroup *bool  `json:"is_group"`
 }
 
 // Update обновляет данные об артисте по ID
 func (h *ArtistHandlers) Update(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	var req RequestUpdate
-
-	if err := validator.New().Struct(req); err != nil {
-		var validateErr validator.ValidationErrors
-		errors.As(err, &validateErr)
-
-		h.logger.Error("failed to validate request", err)
-
-		render.JSON(w, r, response.ValidationError(validateErr))
-
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	var artist models.Artist
-	if err := h.storage.DB.First(&artist, id).Error; err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
+	artist, err := h.artists.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	artist.Name = req.Name
-	artist.IsGroup = req.IsGroup
-	if err := h.storage.DB.Save(&artist).Error; err != nil {
-		h.logger.Error("failed to update artist", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if req.Name != "" {
+		artist.Name = req.Name
+	}
+	if req.IsGroup != nil {
+		artist.IsGroup = *req.IsGroup
+	}
+	if err := h.artists.Update(r.Context(), &artist); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
@@ -149,22 +157,43 @@ func (h *ArtistHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete удаляет артиста по ID
+// Delete переносит артиста вместе с его песнями в корзину
 func (h *ArtistHandlers) Delete(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.artists.Delete(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// Restore возвращает артиста из корзины вместе с песнями, удалёнными вместе с ним
+func (h *ArtistHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	if err := h.storage.DB.Delete(&models.Artist{}, id).Error; err != nil {
-		h.logger.Error("failed to delete artist", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if err := h.artists.Restore(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	artist, err := h.artists.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	render.JSON(w, r, ResponseSingle{
 		Response: response.OK(),
+		Artist:   artist,
 	})
 }
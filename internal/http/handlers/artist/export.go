@@ -0,0 +1,27 @@
+package artist
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/storage"
+	"net/http"
+)
+
+// exportColumns - колонки выгрузки артистов.
+var exportColumns = []string{"id", "name", "is_group", "songs"}
+
+// Export выгружает всех артистов с числом песен в CSV, JSON Lines или XLSX.
+// Формат задаётся параметром format или заголовком Accept
+func (h *ArtistHandlers) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := request.ExportFormat(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	out := response.NewExport(w, format, "artists", exportColumns)
+	err = h.exports.Artists(r.Context(), func(a storage.ArtistRow) error {
+		return out.WriteRow(a.ID, a.Name, a.IsGroup, a.Songs)
+	})
+	out.Finish(r, h.logger, err)
+}
@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type AuditHandlers struct {
+	entries storage.AuditRepository
+	logger  *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	Entries []models.AuditEntry `json:"entries"`
+	Total   int64               `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+}
+
+func NewAuditHandlers(entries storage.AuditRepository, logger *slog.Logger) *AuditHandlers {
+	return &AuditHandlers{entries: entries, logger: logger}
+}
+
+// List возвращает страницу журнала аудита, последние записи первыми. Фильтры: entity, id (только с entity)
+func (h *AuditHandlers) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter storage.AuditFilter
+		err    error
+	)
+
+	filter.Limit, filter.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if v := models.AuditEntity(r.URL.Query().Get("entity")); v != "" {
+		if !slices.Contains(models.AuditEntities, v) {
+			response.RenderError(w, r, h.logger, request.BadRequest("invalid entity %q, allowed: artist, song, song_detail", v))
+			return
+		}
+		filter.Entity = v
+	}
+
+	if filter.EntityID, err = request.QueryID(r, "id"); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if filter.EntityID != 0 && filter.Entity == "" {
+		response.RenderError(w, r, h.logger, request.BadRequest("id requires entity"))
+		return
+	}
+
+	page, err := h.entries.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response: response.OK(),
+		Entries:  page.Entries,
+		Total:    page.Total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	libauth "music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+type AuthHandlers struct {
+	users  storage.UserRepository
+	tokens *libauth.Tokens
+	logger *slog.Logger
+}
+
+type ResponseTokens struct {
+	response.Response
+	libauth.Pair
+}
+
+type ResponseUser struct {
+	response.Response
+	User models.User `json:"user"`
+}
+
+func NewAuthHandlers(users storage.UserRepository, tokens *libauth.Tokens, logger *slog.Logger) *AuthHandlers {
+	return &AuthHandlers{users: users, tokens: tokens, logger: logger}
+}
+
+// RequestRegister - данные регистрации. Длина пароля от 8 символов и не больше
+// libauth.MaxPasswordLength байт: кириллический символ занимает два байта.
+type RequestRegister struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// Register создаёт пользователя с ролью viewer и сразу выдаёт ему токены
+func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
+	var req RequestRegister
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if len(req.Password) > libauth.MaxPasswordLength {
+		response.RenderError(w, r, h.logger, request.BadRequest("password must be at most %d bytes", libauth.MaxPasswordLength))
+		return
+	}
+
+	hash, err := libauth.HashPassword(req.Password)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	user := models.User{Email: normalizeEmail(req.Email), PasswordHash: hash, Role: models.RoleViewer}
+	if err := h.users.Create(r.Context(), &user); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderTokens(w, r, http.StatusCreated, user)
+}
+
+type RequestLogin struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Login выдаёт пару токенов по email и паролю
+func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
+	var req RequestLogin
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	user, err := h.authenticate(r.Context(), normalizeEmail(req.Email), req.Password)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderTokens(w, r, http.StatusOK, user)
+}
+
+// authenticate проверяет пароль. Неизвестный email и неверный пароль неразличимы для клиента.
+func (h *AuthHandlers) authenticate(ctx context.Context, email, password string) (models.User, error) {
+	user, err := h.users.GetByEmail(ctx, email)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return models.User{}, libauth.RejectPassword(password)
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := libauth.CheckPassword(user.PasswordHash, password); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+type RequestRefresh struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Refresh обменивает refresh-токен на новую пару. Роль берётся из хранилища, поэтому
+// новые токены учитывают её изменение
+func (h *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RequestRefresh
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	claims, err := h.tokens.Parse(req.RefreshToken, libauth.TokenRefresh)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), claims.UserID())
+	if errors.Is(err, storage.ErrUserNotFound) {
+		// Пользователь удалён после выдачи токена
+		err = libauth.ErrInvalidToken
+	}
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderTokens(w, r, http.StatusOK, user)
+}
+
+// Me возвращает текущего пользователя
+func (h *AuthHandlers) Me(w http.ResponseWriter, r *http.Request) {
+	id, err := request.RequireUserID(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseUser{
+		Response: response.OK(),
+		User:     user,
+	})
+}
+
+func (h *AuthHandlers) renderTokens(w http.ResponseWriter, r *http.Request, status int, user models.User) {
+	pair, err := h.tokens.Issue(user)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	// Токены не должны оседать в кэшах прокси
+	w.Header().Set("Cache-Control", "no-store")
+	render.Status(r, status)
+	render.JSON(w, r, ResponseTokens{
+		Response: response.OK(),
+		Pair:     pair,
+	})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
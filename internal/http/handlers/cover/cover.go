@@ -0,0 +1,217 @@
+package cover
+
+import (
+	"context"
+	"log/slog"
+	"music-lib/internal/http/handlers/artist"
+	"music-lib/internal/http/handlers/song"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	libcover "music-lib/internal/lib/cover"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// FileField - поле формы multipart/form-data с картинкой.
+const FileField = "file"
+
+// CoverHandlers загружают и удаляют обложки артистов и песен. Ответ - запись с новой обложкой
+// в том же виде, что у GET /artists/{id} и GET /songs/{id}.
+type CoverHandlers struct {
+	artists storage.ArtistRepository
+	songs   storage.SongRepository
+	covers  *libcover.Covers
+	logger  *slog.Logger
+}
+
+// NewCoverHandlers создаёт хэндлеры. При covers == nil загрузка отвечает 503.
+func NewCoverHandlers(
+	artists storage.ArtistRepository, songs storage.SongRepository, covers *libcover.Covers, logger *slog.Logger,
+) *CoverHandlers {
+	return &CoverHandlers{artists: artists, songs: songs, covers: covers, logger: logger}
+}
+
+// PutArtist заменяет обложку артиста
+func (h *CoverHandlers) PutArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	a, err := h.artists.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	c, err := h.replace(w, r, func(c *models.Cover) (*models.Cover, error) {
+		return h.artists.SetCover(r.Context(), id, c)
+	})
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	a.Cover = c
+
+	render.JSON(w, r, artist.ResponseSingle{
+		Response: response.OK(),
+		Artist:   a,
+	})
+}
+
+// DeleteArtist убирает обложку артиста
+func (h *CoverHandlers) DeleteArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	a, err := h.artists.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	err = h.remove(r.Context(), a.Cover, func() (*models.Cover, error) {
+		return h.artists.SetCover(r.Context(), id, nil)
+	})
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	a.Cover = nil
+
+	render.JSON(w, r, artist.ResponseSingle{
+		Response: response.OK(),
+		Artist:   a,
+	})
+}
+
+// PutSong заменяет обложку песни
+func (h *CoverHandlers) PutSong(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	_, err = h.replace(w, r, func(c *models.Cover) (*models.Cover, error) {
+		return h.songs.SetCover(r.Context(), id, c)
+	})
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderSong(w, r, id)
+}
+
+// DeleteSong убирает обложку песни
+func (h *CoverHandlers) DeleteSong(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	s, err := h.songs.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	err = h.remove(r.Context(), s.Cover, func() (*models.Cover, error) {
+		return h.songs.SetCover(r.Context(), id, nil)
+	})
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderSong(w, r, id)
+}
+
+// renderSong отвечает песней после изменения: SetCover меняет и её updated_at.
+func (h *CoverHandlers) renderSong(w http.ResponseWriter, r *http.Request, id uint) {
+	s, err := h.songs.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, song.ResponseSingle{
+		Response: response.OK(),
+		Song:     s,
+	})
+}
+
+// replace загружает обложку из запроса и сохраняет её через set. Если записать обложку
+// не удалось, её файлы удаляются; после успешной записи удаляются файлы обложки, которую
+// вернул set: при одновременных заменах каждая удаляет только то, что заменила сама.
+func (h *CoverHandlers) replace(
+	w http.ResponseWriter, r *http.Request, set func(*models.Cover) (*models.Cover, error),
+) (*models.Cover, error) {
+	if h.covers == nil {
+		return nil, libcover.ErrDisabled
+	}
+
+	data, err := request.FormFile(w, r, FileField, h.covers.MaxBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := h.covers.Upload(r.Context(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := set(c)
+	if err != nil {
+		h.discard(r.Context(), c)
+		return nil, err
+	}
+	h.discard(r.Context(), previous)
+
+	return c, nil
+}
+
+// remove убирает обложку через unset и удаляет файлы той, что была убрана. Без обложки
+// возвращает libcover.ErrNotFound; current - обложка по уже прочитанной записи, она
+// позволяет не делать лишнюю запись, если обложки нет.
+func (h *CoverHandlers) remove(ctx context.Context, current *models.Cover, unset func() (*models.Cover, error)) error {
+	if current == nil {
+		return libcover.ErrNotFound
+	}
+	previous, err := unset()
+	if err != nil {
+		return err
+	}
+	// Обложку успел убрать одновременный запрос, и её файлы удалит он
+	if previous == nil {
+		return libcover.ErrNotFound
+	}
+	h.discard(ctx, previous)
+
+	return nil
+}
+
+// discard удаляет файлы обложки, на которую больше ничто не ссылается. Ошибка только
+// логируется: запись уже сохранена, а лишний файл в хранилище ничего не ломает.
+func (h *CoverHandlers) discard(ctx context.Context, c *models.Cover) {
+	if h.covers == nil {
+		return
+	}
+	if err := h.covers.Delete(context.WithoutCancel(ctx), c); err != nil {
+		h.logger.Warn("failed to delete cover files", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"errors"
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	libimporter "music-lib/internal/lib/importer"
+	"music-lib/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+)
+
+// maxImportBytes и maxImportRows ограничивают файл импорта: отчёт о каждой записи
+// собирается в памяти и отдаётся одним ответом.
+const (
+	maxImportBytes = 32 << 20
+	maxImportRows  = 50_000
+)
+
+type ImportHandlers struct {
+	importer *libimporter.Importer
+	logger   *slog.Logger
+}
+
+type ResponseImport struct {
+	response.Response
+	libimporter.Report
+}
+
+func NewImportHandlers(imports storage.ImportRepository, logger *slog.Logger) *ImportHandlers {
+	return &ImportHandlers{
+		importer: libimporter.New(imports, libimporter.DefaultBatchSize).WithMaxRows(maxImportRows),
+		logger:   logger,
+	}
+}
+
+// Import загружает артистов, песни и детали из тела запроса. Формат задаётся параметром format
+// или Content-Type; dry_run=true проверяет файл, ничего не сохраняя. Файл больше
+// maxImportBytes или с числом записей больше maxImportRows отклоняется с 413
+func (h *ImportHandlers) Import(w http.ResponseWriter, r *http.Request) {
+	if r.ContentLength > maxImportBytes {
+		response.RenderError(w, r, h.logger, request.ErrTooLarge)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	format, err := h.format(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var dryRun bool
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			response.RenderError(w, r, h.logger, request.BadRequest("invalid dry_run"))
+			return
+		}
+	}
+
+	report, err := h.importer.Run(r.Context(), r.Body, format, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			err = request.ErrTooLarge
+		case errors.Is(err, libimporter.ErrMalformed):
+			err = request.BadRequest("%s", err.Error())
+		}
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseImport{
+		Response: response.OK(),
+		Report:   report,
+	})
+}
+
+// format определяет формат тела: параметр format важнее Content-Type.
+func (h *ImportHandlers) format(r *http.Request) (libimporter.Format, error) {
+	if v := r.URL.Query().Get("format"); v != "" {
+		format, err := libimporter.ParseFormat(v)
+		if err != nil {
+			return "", request.BadRequest("invalid format %q, allowed: csv, jsonl", v)
+		}
+		return format, nil
+	}
+
+	if format, ok := libimporter.FormatByContentType(r.Header.Get("Content-Type")); ok {
+		return format, nil
+	}
+
+	return "", request.BadRequest("format is required: pass format=csv or format=jsonl, or Content-Type text/csv or application/x-ndjson")
+}
@@ -0,0 +1,66 @@
+package playlist
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// AddCollaborator разрешает пользователю редактировать треки плейлиста. Доступно только владельцу
+func (h *PlaylistHandlers) AddCollaborator(w http.ResponseWriter, r *http.Request) {
+	id, collaboratorID, err := h.collaboratorParams(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.playlists.AddCollaborator(r.Context(), id, collaboratorID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// RemoveCollaborator отзывает у пользователя право редактировать плейлист. Доступно только владельцу
+func (h *PlaylistHandlers) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	id, collaboratorID, err := h.collaboratorParams(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.playlists.RemoveCollaborator(r.Context(), id, collaboratorID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// collaboratorParams разбирает ID плейлиста и соавтора и проверяет, что запрос сделал владелец.
+func (h *PlaylistHandlers) collaboratorParams(r *http.Request) (id, collaboratorID uint, err error) {
+	if id, err = request.ID(r, "id"); err != nil {
+		return 0, 0, err
+	}
+	if collaboratorID, err = request.ID(r, "user_id"); err != nil {
+		return 0, 0, err
+	}
+
+	userID, err := request.RequireUserID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	playlist, err := h.owner(r.Context(), id, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if playlist.OwnerID == collaboratorID {
+		return 0, 0, request.BadRequest("owner cannot be a collaborator")
+	}
+
+	return id, collaboratorID, nil
+}
@@ -0,0 +1,129 @@
+package playlist
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type ResponseItem struct {
+	response.Response
+	Item models.PlaylistItem `json:"item"`
+}
+
+// RequestAddItem - трек для добавления. Без before_item_id и after_item_id трек попадает в конец.
+type RequestAddItem struct {
+	SongID       uint `json:"song_id" validate:"required"`
+	BeforeItemID uint `json:"before_item_id" validate:"excluded_with=AfterItemID"`
+	AfterItemID  uint `json:"after_item_id"`
+}
+
+// AddItem добавляет трек в плейлист. Доступно владельцу и соавторам, требует If-Match
+func (h *PlaylistHandlers) AddItem(w http.ResponseWriter, r *http.Request) {
+	id, userID, version, err := mutation(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestAddItem
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.member(r.Context(), id, userID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	item := models.PlaylistItem{SongID: req.SongID, AddedBy: userID}
+	at := storage.Placement{Before: req.BeforeItemID, After: req.AfterItemID}
+	if err := h.playlists.AddItem(r.Context(), id, version, &item, at); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, version+1)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, ResponseItem{
+		Response: response.OK(),
+		Item:     item,
+	})
+}
+
+// RequestMoveItem - новое место трека: ровно одно из полей.
+type RequestMoveItem struct {
+	BeforeItemID uint `json:"before_item_id" validate:"required_without=AfterItemID,excluded_with=AfterItemID"`
+	AfterItemID  uint `json:"after_item_id"`
+}
+
+// MoveItem переносит трек перед или после другого трека. Доступно владельцу и соавторам, требует If-Match
+func (h *PlaylistHandlers) MoveItem(w http.ResponseWriter, r *http.Request) {
+	id, userID, version, err := mutation(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	itemID, err := request.ID(r, "item_id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestMoveItem
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if req.BeforeItemID == itemID || req.AfterItemID == itemID {
+		response.RenderError(w, r, h.logger, request.BadRequest("item cannot be placed relative to itself"))
+		return
+	}
+
+	if _, err := h.member(r.Context(), id, userID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	at := storage.Placement{Before: req.BeforeItemID, After: req.AfterItemID}
+	if err := h.playlists.MoveItem(r.Context(), id, version, itemID, at); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, version+1)
+	render.JSON(w, r, response.OK())
+}
+
+// RemoveItem удаляет трек из плейлиста. Доступно владельцу и соавторам, требует If-Match
+func (h *PlaylistHandlers) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	id, userID, version, err := mutation(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	itemID, err := request.ID(r, "item_id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.member(r.Context(), id, userID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := h.playlists.RemoveItem(r.Context(), id, version, itemID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, version+1)
+	render.JSON(w, r, response.OK())
+}
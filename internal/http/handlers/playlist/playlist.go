@@ -0,0 +1,292 @@
+package playlist
+
+import (
+	"context"
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	scopeMine   = "mine"
+	scopePublic = "public"
+)
+
+type PlaylistHandlers struct {
+	playlists storage.PlaylistRepository
+	logger    *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	Playlists []models.Playlist `json:"playlists"`
+	Total     int64             `json:"total"`
+	Limit     int               `json:"limit"`
+	Offset    int               `json:"offset"`
+}
+
+type ResponseSingle struct {
+	response.Response
+	Playlist models.Playlist `json:"playlist"`
+}
+
+func NewPlaylistHandlers(playlists storage.PlaylistRepository, logger *slog.Logger) *PlaylistHandlers {
+	return &PlaylistHandlers{playlists: playlists, logger: logger}
+}
+
+// List возвращает страницу плейлистов, недавно изменённые первыми. scope=mine (по умолчанию) -
+// плейлисты, где пользователь владелец или соавтор, scope=public - публичные плейлисты всех пользователей
+func (h *PlaylistHandlers) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter storage.PlaylistFilter
+		err    error
+	)
+
+	filter.Limit, filter.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	switch scope := r.URL.Query().Get("scope"); scope {
+	case "", scopeMine:
+		if filter.MemberID, err = request.RequireUserID(r); err != nil {
+			response.RenderError(w, r, h.logger, err)
+			return
+		}
+	case scopePublic:
+		filter.Visibility = models.VisibilityPublic
+	default:
+		response.RenderError(w, r, h.logger, request.BadRequest("invalid scope %q, allowed: mine, public", scope))
+		return
+	}
+
+	page, err := h.playlists.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response:  response.OK(),
+		Playlists: page.Playlists,
+		Total:     page.Total,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	})
+}
+
+type RequestCreate struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=2000"`
+	// Visibility по умолчанию private.
+	Visibility models.Visibility `json:"visibility" validate:"omitempty,oneof=private unlisted public"`
+}
+
+// Create создаёт плейлист текущего пользователя
+func (h *PlaylistHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.RequireUserID(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestCreate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	playlist := models.Playlist{
+		OwnerID:     userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	}
+	if playlist.Visibility == "" {
+		playlist.Visibility = models.VisibilityPrivate
+	}
+	if err := h.playlists.Create(r.Context(), &playlist); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, playlist.Version)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Playlist: playlist,
+	})
+}
+
+// Get возвращает плейлист с треками. Приватный плейлист видят только владелец и соавторы
+func (h *PlaylistHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	userID, _ := request.UserID(r)
+	playlist, err := h.visible(r.Context(), h.playlists.GetWithItems, id, userID)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, playlist.Version)
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Playlist: playlist,
+	})
+}
+
+// RequestUpdate содержит изменяемые поля. Незаданные поля остаются без изменений.
+type RequestUpdate struct {
+	Name        string            `json:"name" validate:"omitempty,max=255"`
+	Description *string           `json:"description" validate:"omitempty,max=2000"`
+	Visibility  models.Visibility `json:"visibility" validate:"omitempty,oneof=private unlisted public"`
+}
+
+// Update меняет название и описание плейлиста (владелец и соавторы) или его видимость (только владелец).
+// Требует If-Match с текущей версией
+func (h *PlaylistHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	id, userID, version, err := mutation(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestUpdate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	playlist, err := h.member(r.Context(), id, userID)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if req.Visibility != "" && req.Visibility != playlist.Visibility && playlist.OwnerID != userID {
+		response.RenderError(w, r, h.logger, request.ErrForbidden)
+		return
+	}
+
+	if req.Name != "" {
+		playlist.Name = req.Name
+	}
+	if req.Description != nil {
+		playlist.Description = *req.Description
+	}
+	if req.Visibility != "" {
+		playlist.Visibility = req.Visibility
+	}
+	if err := h.playlists.Update(r.Context(), &playlist, version); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	response.ETag(w, playlist.Version)
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		Playlist: playlist,
+	})
+}
+
+// Delete удаляет плейлист. Доступно только владельцу, требует If-Match с текущей версией
+func (h *PlaylistHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, userID, version, err := mutation(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.owner(r.Context(), id, userID); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := h.playlists.Delete(r.Context(), id, version); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// mutation разбирает общие параметры изменяющих запросов: ID плейлиста,
+// пользователя и версию из If-Match.
+func mutation(r *http.Request) (id, userID uint, version int, err error) {
+	if id, err = request.ID(r, "id"); err != nil {
+		return 0, 0, 0, err
+	}
+	if userID, err = request.RequireUserID(r); err != nil {
+		return 0, 0, 0, err
+	}
+	if version, err = request.IfMatch(r); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return id, userID, version, nil
+}
+
+// visible загружает плейлист через get. Чужой приватный плейлист выглядит несуществующим,
+// чтобы не раскрывать его наличие.
+func (h *PlaylistHandlers) visible(
+	ctx context.Context, get func(context.Context, uint) (models.Playlist, error), id, userID uint,
+) (models.Playlist, error) {
+	playlist, err := get(ctx, id)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+	if playlist.Visibility == models.VisibilityPrivate && !isMember(playlist, userID) {
+		return models.Playlist{}, storage.ErrPlaylistNotFound
+	}
+
+	return playlist, nil
+}
+
+// member загружает плейлист, который пользователь может редактировать как владелец или соавтор.
+func (h *PlaylistHandlers) member(ctx context.Context, id, userID uint) (models.Playlist, error) {
+	playlist, err := h.visible(ctx, h.playlists.Get, id, userID)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+	if !isMember(playlist, userID) {
+		return models.Playlist{}, request.ErrForbidden
+	}
+
+	return playlist, nil
+}
+
+// owner загружает плейлист, владельцем которого является пользователь.
+func (h *PlaylistHandlers) owner(ctx context.Context, id, userID uint) (models.Playlist, error) {
+	playlist, err := h.visible(ctx, h.playlists.Get, id, userID)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+	if playlist.OwnerID != userID {
+		return models.Playlist{}, request.ErrForbidden
+	}
+
+	return playlist, nil
+}
+
+func isMember(playlist models.Playlist, userID uint) bool {
+	if userID == 0 {
+		return false
+	}
+
+	return playlist.OwnerID == userID || slices.ContainsFunc(playlist.Collaborators, func(c models.PlaylistCollaborator) bool {
+		return c.UserID == userID
+	})
+}
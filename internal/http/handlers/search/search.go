@@ -0,0 +1,205 @@
+package search
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	maxQueryLen  = 255
+
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+)
+
+type SearchHandlers struct {
+	search    storage.SearchRepository
+	threshold float64
+	logger    *slog.Logger
+}
+
+type ResponseSearch struct {
+	response.Response
+	Query string `json:"query"`
+	Mode  string `json:"mode"`
+	Lang  string `json:"lang,omitempty"`
+	// DidYouMean - похожее имя артиста или название песни, если полнотекстовый поиск ничего не нашёл.
+	DidYouMean string              `json:"did_you_mean,omitempty"`
+	Results    []storage.SearchHit `json:"results"`
+	Total      int64               `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+type ResponseSuggest struct {
+	response.Response
+	Query   string               `json:"query"`
+	Artists []storage.Suggestion `json:"artists"`
+	Songs   []storage.Suggestion `json:"songs"`
+}
+
+// NewSearchHandlers создаёт хэндлеры поиска. threshold - порог сходства по умолчанию для
+// нечёткого поиска и подсказок; если он не задан, используется storage.DefaultSimilarityThreshold.
+func NewSearchHandlers(search storage.SearchRepository, threshold float64, logger *slog.Logger) *SearchHandlers {
+	if threshold <= 0 {
+		threshold = storage.DefaultSimilarityThreshold
+	}
+
+	return &SearchHandlers{search: search, threshold: threshold, logger: logger}
+}
+
+// Search ищет песни по названию, имени артиста и тексту, лучшие совпадения идут первыми.
+// Если полнотекстовый поиск ничего не нашёл, в ответ добавляется подсказка did_you_mean
+func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
+	q, err := h.parseSearch(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	page, err := h.search.Search(r.Context(), q)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	resp := ResponseSearch{
+		Response: response.OK(),
+		Query:    q.Query,
+		Mode:     string(q.Mode),
+		Lang:     string(q.Lang),
+		Results:  page.Hits,
+		Total:    page.Total,
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+	}
+
+	if page.Total == 0 && q.Mode == storage.SearchModeFullText {
+		suggestions, err := h.search.Suggest(r.Context(), storage.SuggestQuery{
+			Query:     q.Query,
+			Threshold: q.Threshold,
+			Limit:     1,
+		})
+		if err != nil {
+			// Подсказка необязательна, пустой результат поиска важнее ошибки
+			h.logger.Warn("failed to build search suggestion", slog.Any("error", err))
+		} else if best, ok := suggestions.Best(); ok {
+			resp.DidYouMean = best.Name
+		}
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Suggest возвращает артистов и песни с названием, похожим на начало или часть запроса, для автодополнения
+func (h *SearchHandlers) Suggest(w http.ResponseWriter, r *http.Request) {
+	query, err := parseText(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	threshold, err := h.parseThreshold(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxSuggestLimit {
+			response.RenderError(w, r, h.logger, request.BadRequest("limit must be between 1 and %d", maxSuggestLimit))
+			return
+		}
+	}
+
+	suggestions, err := h.search.Suggest(r.Context(), storage.SuggestQuery{
+		Query:     query,
+		Threshold: threshold,
+		Limit:     limit,
+	})
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSuggest{
+		Response: response.OK(),
+		Query:    query,
+		Artists:  suggestions.Artists,
+		Songs:    suggestions.Songs,
+	})
+}
+
+func (h *SearchHandlers) parseSearch(r *http.Request) (storage.SearchQuery, error) {
+	var (
+		q   storage.SearchQuery
+		err error
+	)
+
+	if q.Query, err = parseText(r); err != nil {
+		return q, err
+	}
+
+	switch mode := storage.SearchMode(r.URL.Query().Get("mode")); mode {
+	case "", storage.SearchModeFullText:
+		q.Mode = storage.SearchModeFullText
+	case storage.SearchModeFuzzy:
+		q.Mode = storage.SearchModeFuzzy
+	default:
+		return q, request.BadRequest("invalid mode %q, allowed: fulltext, fuzzy", mode)
+	}
+
+	switch lang := storage.SearchLang(r.URL.Query().Get("lang")); lang {
+	case storage.SearchLangAny, storage.SearchLangRussian, storage.SearchLangEnglish:
+		q.Lang = lang
+	default:
+		return q, request.BadRequest("invalid lang %q, allowed: ru, en", lang)
+	}
+
+	if q.Threshold, err = h.parseThreshold(r); err != nil {
+		return q, err
+	}
+
+	q.Limit, q.Offset, err = request.Page(r, defaultLimit, maxLimit)
+
+	return q, err
+}
+
+// parseText возвращает обязательный параметр q.
+func parseText(r *http.Request) (string, error) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return "", request.BadRequest("q is required")
+	}
+	if len([]rune(q)) > maxQueryLen {
+		return "", request.BadRequest("q exceeds maximum of %d characters", maxQueryLen)
+	}
+
+	return q, nil
+}
+
+// parseThreshold разбирает параметр threshold от 0 до 1 или возвращает порог по умолчанию.
+func (h *SearchHandlers) parseThreshold(r *http.Request) (float64, error) {
+	v := r.URL.Query().Get("threshold")
+	if v == "" {
+		return h.threshold, nil
+	}
+
+	threshold, err := strconv.ParseFloat(v, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return 0, request.BadRequest("threshold must be between 0 and 1")
+	}
+
+	return threshold, nil
+}
@@ -0,0 +1,102 @@
+package song
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type ResponseCredits struct {
+	response.Response
+	SongID  uint                `json:"song_id"`
+	Credits []models.SongCredit `json:"credits"`
+}
+
+type RequestCredit struct {
+	ArtistID uint   `json:"artist_id" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=primary featured composer lyricist producer"`
+}
+
+// RequestCredits - титры песни целиком в нужном порядке. Нужен хотя бы один артист с ролью primary.
+type RequestCredits struct {
+	Credits []RequestCredit `json:"credits" validate:"required,min=1,max=100,dive"`
+}
+
+// Credits возвращает титры песни
+func (h *SongHandlers) Credits(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	credits, err := h.songs.Credits(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseCredits{
+		Response: response.OK(),
+		SongID:   id,
+		Credits:  credits,
+	})
+}
+
+// SetCredits заменяет титры песни. Первый артист с ролью primary становится основным артистом песни
+func (h *SongHandlers) SetCredits(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestCredits
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	credits := make([]models.SongCredit, len(req.Credits))
+	seen := make(map[RequestCredit]bool, len(req.Credits))
+	hasPrimary := false
+	for i, c := range req.Credits {
+		if seen[c] {
+			response.RenderError(w, r, h.logger, request.BadRequest("artist %d is listed twice as %s", c.ArtistID, c.Role))
+			return
+		}
+		seen[c] = true
+
+		credits[i] = models.SongCredit{ArtistID: c.ArtistID, Role: models.CreditRole(c.Role)}
+		hasPrimary = hasPrimary || credits[i].Role == models.CreditPrimary
+	}
+	if !hasPrimary {
+		response.RenderError(w, r, h.logger, request.BadRequest("credits must include a primary artist"))
+		return
+	}
+
+	if err := h.songs.SetCredits(r.Context(), id, credits); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	credits, err = h.songs.Credits(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseCredits{
+		Response: response.OK(),
+		SongID:   id,
+		Credits:  credits,
+	})
+}
@@ -0,0 +1,160 @@
+package song
+
+import (
+	"github.com/go-chi/render"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/date"
+	"music-lib/internal/models"
+	"net/http"
+)
+
+type ResponseDetail struct {
+	response.Response
+	Detail models.SongDetail `json:"song_detail"`
+}
+
+// RequestDetailPut - детали песни целиком. Дата принимается в формате DD.MM.YYYY или YYYY-MM-DD.
+type RequestDetailPut struct {
+	Text        string `json:"text" validate:"required,max=20000"`
+	ReleaseDate string `json:"release_date" validate:"required,date"`
+	Link        string `json:"link" validate:"omitempty,url,max=255"`
+}
+
+// RequestDetailPatch содержит изменяемые поля. Незаданные поля остаются без изменений.
+type RequestDetailPatch struct {
+	Text        *string `json:"text" validate:"omitempty,max=20000"`
+	ReleaseDate *string `json:"release_date" validate:"omitempty,date"`
+	Link        *string `json:"link" validate:"omitempty,url,max=255"`
+}
+
+// GetDetails возвращает детали песни
+func (h *SongHandlers) GetDetails(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	detail, err := h.details.GetBySongID(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseDetail{
+		Response: response.OK(),
+		Detail:   detail,
+	})
+}
+
+// PutDetails создаёт или полностью заменяет детали песни
+func (h *SongHandlers) PutDetails(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestDetailPut
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	// Формат уже проверен тегом date
+	releaseDate, _ := date.Parse(req.ReleaseDate)
+
+	detail := models.SongDetail{
+		SongID:      id,
+		Text:        req.Text,
+		ReleaseDate: releaseDate,
+		Link:        req.Link,
+	}
+	if err := h.details.Save(r.Context(), &detail); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseDetail{
+		Response: response.OK(),
+		Detail:   detail,
+	})
+}
+
+// PatchDetails частично изменяет существующие детали песни
+func (h *SongHandlers) PatchDetails(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestDetailPatch
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	detail, err := h.details.GetBySongID(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if req.Text != nil {
+		detail.Text = *req.Text
+	}
+	if req.ReleaseDate != nil {
+		detail.ReleaseDate, _ = date.Parse(*req.ReleaseDate)
+	}
+	if req.Link != nil {
+		detail.Link = *req.Link
+	}
+
+	if err := h.details.Save(r.Context(), &detail); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseDetail{
+		Response: response.OK(),
+		Detail:   detail,
+	})
+}
+
+// DeleteDetails удаляет детали песни, сама песня остаётся
+func (h *SongHandlers) DeleteDetails(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.details.DeleteBySongID(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
@@ -0,0 +1,69 @@
+package song
+
+import (
+	"github.com/go-chi/render"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+)
+
+// roleAny в дискографии выбирает песни с любой ролью артиста в титрах.
+const roleAny = "any"
+
+type ResponseDiscography struct {
+	response.Response
+	Artist models.Artist `json:"artist"`
+	Songs  []models.Song `json:"songs"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// ListByArtist возвращает страницу песен артиста с сортировкой по названию или дате релиза.
+// По умолчанию это песни, где артист основной; с role - песни, где у него эта роль в титрах,
+// а с role=any - все песни, где он есть в титрах
+func (h *SongHandlers) ListByArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	filter := storage.SongFilter{ArtistID: id}
+	if role := r.URL.Query().Get("role"); role != "" {
+		filter = storage.SongFilter{CreditArtistID: id}
+		if role != roleAny {
+			if filter.CreditRole, err = parseRole(role); err != nil {
+				response.RenderError(w, r, h.logger, err)
+				return
+			}
+		}
+	}
+	if err := parseListOptions(r, &filter); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	artist, err := h.artists.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	page, err := h.songs.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseDiscography{
+		Response: response.OK(),
+		Artist:   artist,
+		Songs:    page.Songs,
+		Total:    page.Total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+}
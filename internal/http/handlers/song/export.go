@@ -0,0 +1,43 @@
+package song
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/storage"
+	"net/http"
+)
+
+// exportColumns - колонки выгрузки песен: артист, альбом и детали разворачиваются в плоские поля.
+var exportColumns = []string{
+	"id", "name", "artist_id", "artist_name", "album_id", "album_title",
+	"disc_number", "track_number", "release_date", "link", "text",
+}
+
+// Export выгружает песни в CSV, JSON Lines или XLSX с теми же фильтрами и сортировкой,
+// что и List, но без пагинации. Формат задаётся параметром format или заголовком Accept
+func (h *SongHandlers) Export(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSongConditions(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := parseSort(r, &filter); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	format, err := request.ExportFormat(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	out := response.NewExport(w, format, "songs", exportColumns)
+	err = h.exports.Songs(r.Context(), filter, func(s storage.SongRow) error {
+		return out.WriteRow(
+			s.ID, s.Name, s.ArtistID, s.ArtistName, s.AlbumID, s.AlbumTitle,
+			s.DiscNumber, s.TrackNumber, s.ReleaseDate, s.Link, s.Text,
+		)
+	})
+	out.Finish(r, h.logger, err)
+}
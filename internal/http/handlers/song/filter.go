@@ -0,0 +1,148 @@
+package song
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/date"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Значения параметра include для песен.
+const (
+	includeDetails = "details"
+	includeCredits = "credits"
+)
+
+// parseSongFilter собирает storage.SongFilter из query-параметров запроса.
+func parseSongFilter(r *http.Request) (storage.SongFilter, error) {
+	f, err := parseSongConditions(r)
+	if err != nil {
+		return f, err
+	}
+
+	if err := parseListOptions(r, &f); err != nil {
+		return f, err
+	}
+
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if f.Sort != storage.SongSortID || f.Desc {
+			return f, request.BadRequest("cursor can only be used with sort=id")
+		}
+		cursor, err := storage.DecodeCursor(v)
+		if err != nil {
+			return f, err
+		}
+		f.Cursor = cursor
+		f.Offset = 0
+	}
+
+	return f, nil
+}
+
+// parseSongConditions разбирает условия отбора песен: name, artist, artist_id, text,
+// credit_artist_id, role, release_from, release_to и has_link.
+func parseSongConditions(r *http.Request) (storage.SongFilter, error) {
+	q := r.URL.Query()
+	f := storage.SongFilter{
+		Name:   q.Get("name"),
+		Artist: q.Get("artist"),
+		Text:   q.Get("text"),
+	}
+
+	var err error
+	if f.ArtistID, err = request.QueryID(r, "artist_id"); err != nil {
+		return f, err
+	}
+	if f.CreditArtistID, err = request.QueryID(r, "credit_artist_id"); err != nil {
+		return f, err
+	}
+	if f.CreditRole, err = parseRole(q.Get("role")); err != nil {
+		return f, err
+	}
+
+	if v := q.Get("release_from"); v != "" {
+		t, err := date.Parse(v)
+		if err != nil {
+			return f, request.BadRequest("release_from: %v", err)
+		}
+		f.ReleasedFrom = &t
+	}
+
+	if v := q.Get("release_to"); v != "" {
+		t, err := date.Parse(v)
+		if err != nil {
+			return f, request.BadRequest("release_to: %v", err)
+		}
+		f.ReleasedTo = &t
+	}
+
+	if v := q.Get("has_link"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, request.BadRequest("invalid has_link")
+		}
+		f.HasLink = &b
+	}
+
+	return f, nil
+}
+
+// parseListOptions разбирает общие для списков песен параметры: limit, offset,
+// sort (name, release_date, id; минус в начале - по убыванию) и include=details,credits.
+func parseListOptions(r *http.Request, f *storage.SongFilter) error {
+	var err error
+	f.Limit, f.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		return err
+	}
+
+	include, err := request.Include(r, includeDetails, includeCredits)
+	if err != nil {
+		return err
+	}
+	f.WithDetails = include[includeDetails]
+	f.WithCredits = include[includeCredits]
+
+	return parseSort(r, f)
+}
+
+// parseSort разбирает параметр sort: name, release_date или id; минус в начале - по убыванию.
+func parseSort(r *http.Request, f *storage.SongFilter) error {
+	sort := r.URL.Query().Get("sort")
+	f.Desc = strings.HasPrefix(sort, "-")
+	switch storage.SongSort(strings.TrimPrefix(sort, "-")) {
+	case "", storage.SongSortID:
+		f.Sort = storage.SongSortID
+	case storage.SongSortName:
+		f.Sort = storage.SongSortName
+	case storage.SongSortReleaseDate:
+		f.Sort = storage.SongSortReleaseDate
+	default:
+		return request.BadRequest("invalid sort %q, allowed: id, name, release_date", sort)
+	}
+
+	return nil
+}
+
+// parseRole проверяет роль в титрах; пустая строка - роль не задана.
+func parseRole(v string) (models.CreditRole, error) {
+	if v == "" {
+		return "", nil
+	}
+
+	role := models.CreditRole(v)
+	if !slices.Contains(models.CreditRoles, role) {
+		return "", request.BadRequest("invalid role %q, allowed: primary, featured, composer, lyricist, producer", v)
+	}
+
+	return role, nil
+}
@@ -0,0 +1,119 @@
+package song
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/lyrics"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultVersesPerPage = 10
+	maxVersesPerPage     = 50
+)
+
+type Verse struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+type ResponseLyrics struct {
+	response.Response
+	SongID      uint    `json:"song_id"`
+	Song        string  `json:"song"`
+	ArtistID    uint    `json:"artist_id"`
+	Artist      string  `json:"artist"`
+	Page        int     `json:"page"`
+	PerPage     int     `json:"per_page"`
+	TotalVerses int     `json:"total_verses"`
+	TotalPages  int     `json:"total_pages"`
+	Verses      []Verse `json:"verses"`
+}
+
+// Lyrics возвращает текст песни, разбитый на куплеты, с постраничной навигацией
+func (h *SongHandlers) Lyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	page, perPage, err := parseVersePage(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	song, err := h.songs.GetWithDetails(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var text, artistName string
+	if song.SongDetail != nil {
+		text = song.SongDetail.Text
+	}
+	if song.Artist != nil {
+		artistName = song.Artist.Name
+	}
+
+	verses := splitVerses(text)
+	total := len(verses)
+
+	totalPages := (total + perPage - 1) / perPage
+
+	// Номер страницы сравнивается до умножения: огромный page переполнил бы (page-1)*perPage
+	from := total
+	if page <= totalPages {
+		from = (page - 1) * perPage
+	}
+	to := min(from+perPage, total)
+
+	render.JSON(w, r, ResponseLyrics{
+		Response:    response.OK(),
+		SongID:      song.ID,
+		Song:        song.Name,
+		ArtistID:    song.ArtistID,
+		Artist:      artistName,
+		Page:        page,
+		PerPage:     perPage,
+		TotalVerses: total,
+		TotalPages:  totalPages,
+		Verses:      verses[from:to],
+	})
+}
+
+// splitVerses делит текст на куплеты по пустым строкам и нумерует их с единицы.
+func splitVerses(text string) []Verse {
+	parts := lyrics.Verses(text)
+	verses := make([]Verse, len(parts))
+	for i, part := range parts {
+		verses[i] = Verse{Number: i + 1, Text: part}
+	}
+
+	return verses
+}
+
+func parseVersePage(r *http.Request) (page, perPage int, err error) {
+	page, perPage = 1, defaultVersesPerPage
+
+	if v := r.URL.Query().Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return 0, 0, request.BadRequest("invalid page")
+		}
+	}
+
+	if v := r.URL.Query().Get("per_page"); v != "" {
+		perPage, err = strconv.Atoi(v)
+		if err != nil || perPage < 1 || perPage > maxVersesPerPage {
+			return 0, 0, request.BadRequest("per_page must be between 1 and %d", maxVersesPerPage)
+		}
+	}
+
+	return page, perPage, nil
+}
@@ -0,0 +1,189 @@
+package song
+
+import (
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/lyrics"
+	"music-lib/internal/models"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultRevisionsLimit = 20
+	maxRevisionsLimit     = 100
+)
+
+type ResponseRevisions struct {
+	response.Response
+	Revisions []models.LyricsRevision `json:"revisions"`
+	Total     int64                   `json:"total"`
+	Limit     int                     `json:"limit"`
+	Offset    int                     `json:"offset"`
+}
+
+type ResponseRevision struct {
+	response.Response
+	Revision models.LyricsRevision `json:"revision"`
+}
+
+type ResponseRevisionDiff struct {
+	response.Response
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Added   int               `json:"added"`
+	Removed int               `json:"removed"`
+	Lines   []lyrics.DiffLine `json:"lines"`
+}
+
+// Revisions возвращает версии текста песни без самих текстов, последние первыми
+func (h *SongHandlers) Revisions(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	limit, offset, err := request.Page(r, defaultRevisionsLimit, maxRevisionsLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	page, err := h.details.Revisions(r.Context(), id, limit, offset)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseRevisions{
+		Response:  response.OK(),
+		Revisions: page.Revisions,
+		Total:     page.Total,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+// Revision возвращает версию текста песни по номеру
+func (h *SongHandlers) Revision(w http.ResponseWriter, r *http.Request) {
+	number, err := request.ID(r, "rev")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	rev, ok := h.revision(w, r, number)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, ResponseRevision{
+		Response: response.OK(),
+		Revision: rev,
+	})
+}
+
+// RevisionDiff построчно сравнивает две версии текста: from - старая, to - новая
+func (h *SongHandlers) RevisionDiff(w http.ResponseWriter, r *http.Request) {
+	fromNumber, err := request.QueryID(r, "from")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	toNumber, err := request.QueryID(r, "to")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if fromNumber == 0 || toNumber == 0 {
+		response.RenderError(w, r, h.logger, request.BadRequest("from and to are required"))
+		return
+	}
+
+	from, ok := h.revision(w, r, fromNumber)
+	if !ok {
+		return
+	}
+	to, ok := h.revision(w, r, toNumber)
+	if !ok {
+		return
+	}
+
+	resp := ResponseRevisionDiff{
+		Response: response.OK(),
+		From:     from.Number,
+		To:       to.Number,
+		Lines:    lyrics.Diff(from.Text, to.Text),
+	}
+	for _, l := range resp.Lines {
+		switch l.Op {
+		case lyrics.DiffInsert:
+			resp.Added++
+		case lyrics.DiffDelete:
+			resp.Removed++
+		}
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// RestoreRevision делает текст версии текущим текстом песни. Восстановление само
+// становится новой версией, поэтому его можно отменить так же.
+func (h *SongHandlers) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	number, err := request.ID(r, "rev")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	rev, ok := h.revision(w, r, number)
+	if !ok {
+		return
+	}
+
+	detail, err := h.details.GetBySongID(r.Context(), rev.SongID)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	detail.Text = rev.Text
+	if err := h.details.Save(r.Context(), &detail); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseDetail{
+		Response: response.OK(),
+		Detail:   detail,
+	})
+}
+
+// revision загружает версию текста песни из пути запроса. При ошибке ответ уже отправлен.
+func (h *SongHandlers) revision(w http.ResponseWriter, r *http.Request, number uint) (models.LyricsRevision, bool) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return models.LyricsRevision{}, false
+	}
+
+	if _, err := h.songs.Get(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return models.LyricsRevision{}, false
+	}
+
+	rev, err := h.details.Revision(r.Context(), id, int(number))
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return models.LyricsRevision{}, false
+	}
+
+	return rev, true
+}
@@ -1,27 +1,35 @@
 package song
 
 import (
-	"errors"
+	"context"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"log/slog"
+	"music-lib/internal/clients/songinfo"
+	"music-lib/internal/lib/api/request"
 	"music-lib/internal/lib/api/response"
 	"music-lib/internal/models"
-	"music-lib/internal/storage/pgsql"
+	"music-lib/internal/storage"
 	"net/http"
-	"strconv"
-
-	"github.com/go-chi/chi/v5"
+	"time"
 )
 
 type SongHandlers struct {
-	storage *pgsql.Storage
-	logger  *slog.Logger
+	songs    storage.SongRepository
+	artists  storage.ArtistRepository
+	details  storage.SongDetailRepository
+	albums   storage.AlbumRepository
+	exports  storage.ExportRepository
+	songInfo songinfo.Provider
+	logger   *slog.Logger
 }
 
 type ResponseList struct {
 	response.Response
-	Songs []models.Song `json:"songs,omitempty"`
+	Songs      []models.Song `json:"songs"`
+	Total      int64         `json:"total"`
+	Limit      int           `json:"limit"`
+	Offset     int           `json:"offset"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 type ResponseSingle struct {
@@ -29,115 +37,215 @@ type ResponseSingle struct {
 	Song models.Song `json:"song,omitempty"`
 }
 
-func NewSongHandlers(storage *pgsql.Storage, logger *slog.Logger) *SongHandlers {
-	return &SongHandlers{storage: storage, logger: logger}
+// NewSongHandlers создаёт хэндлеры песен. songInfo может быть nil - тогда новые песни не обогащаются.
+func NewSongHandlers(
+	songs storage.SongRepository,
+	artists storage.ArtistRepository,
+	details storage.SongDetailRepository,
+	albums storage.AlbumRepository,
+	exports storage.ExportRepository,
+	songInfo songinfo.Provider,
+	logger *slog.Logger,
+) *SongHandlers {
+	return &SongHandlers{
+		songs:    songs,
+		artists:  artists,
+		details:  details,
+		albums:   albums,
+		exports:  exports,
+		songInfo: songInfo,
+		logger:   logger,
+	}
 }
 
-// List возвращает список всех песен
+// List возвращает страницу песен с учётом фильтров из query-параметров
 func (h *SongHandlers) List(w http.ResponseWriter, r *http.Request) {
-	var songs []models.Song
-	if err := h.storage.DB.Find(&songs).Error; err != nil {
-		h.logger.Error("failed to list songs", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	filter, err := parseSongFilter(r)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	page, err := h.songs.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	render.JSON(w, r, ResponseList{
-		Response: response.OK(),
-		Songs:    songs,
+		Response:   response.OK(),
+		Songs:      page.Songs,
+		Total:      page.Total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		NextCursor: page.NextCursor,
 	})
 }
 
+// RequestCreate - новая песня. С album_id обязателен track_number, disc_number по умолчанию 1.
 type RequestCreate struct {
-	Name     string `json:"name" validate:"required,name"`
-	ArtistID uint   `json:"artist_id"`
+	Name     string `json:"name" validate:"required,max=255"`
+	ArtistID uint   `json:"artist_id" validate:"required"`
+	RequestTrack
 }
 
-// Create создает новую песню
+// Create создает новую песню и дополняет её сведениями из внешнего API
 func (h *SongHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	var req RequestCreate
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
 
-	if err := validator.New().Struct(req); err != nil {
-		var validateErr validator.ValidationErrors
-		errors.As(err, &validateErr)
-
-		h.logger.Error("failed to validate request", err)
-
-		render.JSON(w, r, response.ValidationError(validateErr))
-
+	artist, err := h.artists.Get(r.Context(), req.ArtistID)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	song := models.Song{Name: req.Name, ArtistID: req.ArtistID}
-	if err := h.storage.DB.Create(&song).Error; err != nil {
-		h.logger.Error("failed to create song", slog.Any("error", err))
-		render.JSON(w, r, response.Error("no able to create song"))
+	if err := h.applyTrack(r.Context(), &song, req.RequestTrack); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := h.songs.Create(r.Context(), &song); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
+	song.Artist = &artist
 
+	if h.songInfo != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), enrichTimeout)
+		h.enrich(ctx, &song)
+		cancel()
+	}
+
+	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, ResponseSingle{
 		Response: response.OK(),
 		Song:     song,
 	})
 }
 
-// Get возвращает песню по ID
+// enrichTimeout ограничивает обогащение новой песни целиком: все попытки запроса к внешнему
+// API с паузами между ними и сохранение деталей.
+const enrichTimeout = 10 * time.Second
+
+// enrich запрашивает сведения о песне во внешнем API и сохраняет их в song_details.
+// Ошибки только логируются: песня к этому моменту уже сохранена.
+func (h *SongHandlers) enrich(ctx context.Context, song *models.Song) {
+	log := h.logger.With(slog.Uint64("song_id", uint64(song.ID)))
+
+	info, err := h.songInfo.Info(ctx, song.Artist.Name, song.Name)
+	if err != nil {
+		log.Warn("failed to fetch song info", slog.Any("error", err))
+		return
+	}
+
+	releaseDate, err := info.ReleaseTime()
+	if err != nil {
+		log.Warn("failed to parse song info", slog.Any("error", err))
+		return
+	}
+
+	detail := models.SongDetail{
+		SongID:      song.ID,
+		Text:        info.Text,
+		ReleaseDate: releaseDate,
+		Link:        info.Link,
+	}
+	if err := h.details.Save(ctx, &detail); err != nil {
+		log.Error("failed to save song detail", slog.Any("error", err))
+		return
+	}
+
+	song.SongDetail = &detail
+}
+
+// Get возвращает песню по ID. С include=details в ответ добавляются артист и детали песни,
+// с include=credits - титры
 func (h *SongHandlers) Get(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	var song models.Song
-	if err := h.storage.DB.First(&song, id).Error; err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
+	include, err := request.Include(r, includeDetails, includeCredits)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
+	get := h.songs.Get
+	if include[includeDetails] {
+		get = h.songs.GetWithDetails
+	}
+
+	song, err := get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if include[includeCredits] {
+		if song.Credits, err = h.songs.Credits(r.Context(), id); err != nil {
+			response.RenderError(w, r, h.logger, err)
+			return
+		}
+	}
+
 	render.JSON(w, r, ResponseSingle{
 		Response: response.OK(),
 		Song:     song,
 	})
 }
 
+// RequestUpdate содержит изменяемые поля. Незаданные поля остаются без изменений,
+// album_id 0 отвязывает песню от альбома.
 type RequestUpdate struct {
-	Name string `json:"name" validate:"omitempty,name"`
+	Name     string `json:"name" validate:"omitempty,max=255"`
+	ArtistID uint   `json:"artist_id"`
+	RequestTrack
 }
 
 // Update обновляет данные о песне
 func (h *SongHandlers) Update(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	var req RequestUpdate
-
-	if err := validator.New().Struct(req); err != nil {
-		var validateErr validator.ValidationErrors
-		errors.As(err, &validateErr)
-
-		h.logger.Error("failed to validate request", err)
-
-		render.JSON(w, r, response.ValidationError(validateErr))
-
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	var song models.Song
-	if err := h.storage.DB.First(&song, id).Error; err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
+	song, err := h.songs.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	song.Name = req.Name
-	if err := h.storage.DB.Save(&song).Error; err != nil {
-		h.logger.Error("failed to update song", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if req.Name != "" {
+		song.Name = req.Name
+	}
+	if req.ArtistID != 0 && req.ArtistID != song.ArtistID {
+		// Внешний ключ не отличает артиста в корзине от живого
+		if _, err := h.artists.Get(r.Context(), req.ArtistID); err != nil {
+			response.RenderError(w, r, h.logger, err)
+			return
+		}
+		song.ArtistID = req.ArtistID
+	}
+	if err := h.applyTrack(r.Context(), &song, req.RequestTrack); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+	if err := h.songs.Update(r.Context(), &song); err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
@@ -147,22 +255,43 @@ func (h *SongHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete удаляет песню по ID
+// Delete переносит песню в корзину
 func (h *SongHandlers) Delete(w http.ResponseWriter, r *http.Request) {
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	id, err := request.ID(r, "id")
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
-	if err := h.storage.DB.Delete(&models.Song{}, id).Error; err != nil {
-		h.logger.Error("failed to delete song", slog.Any("error", err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	if err := h.songs.Delete(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, response.OK())
+}
+
+// Restore возвращает песню из корзины. Песню удалённого артиста можно вернуть только вместе с ним
+func (h *SongHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if err := h.songs.Restore(r.Context(), id); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	song, err := h.songs.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
 		return
 	}
 
 	render.JSON(w, r, ResponseSingle{
 		Response: response.OK(),
+		Song:     song,
 	})
 }
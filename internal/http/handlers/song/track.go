@@ -0,0 +1,53 @@
+package song
+
+import (
+	"context"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/models"
+)
+
+// RequestTrack - позиция песни в альбоме. Незаданные поля остаются без изменений.
+type RequestTrack struct {
+	AlbumID     *uint `json:"album_id"`
+	DiscNumber  *int  `json:"disc_number" validate:"omitempty,min=1,max=99"`
+	TrackNumber *int  `json:"track_number" validate:"omitempty,min=1,max=999"`
+}
+
+// applyTrack переносит позицию из запроса в песню и проверяет, что альбом существует,
+// а номер трека задан. Песня без альбома не может иметь номеров диска и трека.
+func (h *SongHandlers) applyTrack(ctx context.Context, song *models.Song, req RequestTrack) error {
+	if req.AlbumID != nil {
+		song.AlbumID = nil
+		if *req.AlbumID != 0 {
+			album, err := h.albums.Get(ctx, *req.AlbumID)
+			if err != nil {
+				return err
+			}
+			song.AlbumID = &album.ID
+		}
+	}
+	if req.DiscNumber != nil {
+		song.DiscNumber = req.DiscNumber
+	}
+	if req.TrackNumber != nil {
+		song.TrackNumber = req.TrackNumber
+	}
+
+	if song.AlbumID == nil {
+		if req.AlbumID == nil && (req.DiscNumber != nil || req.TrackNumber != nil) {
+			return request.BadRequest("album_id is required with disc_number and track_number")
+		}
+		song.DiscNumber, song.TrackNumber = nil, nil
+		return nil
+	}
+
+	if song.TrackNumber == nil {
+		return request.BadRequest("track_number is required with album_id")
+	}
+	if song.DiscNumber == nil {
+		disc := 1
+		song.DiscNumber = &disc
+	}
+
+	return nil
+}
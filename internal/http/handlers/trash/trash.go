@@ -0,0 +1,69 @@
+package trash
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type TrashHandlers struct {
+	trash  storage.TrashRepository
+	logger *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	Items  []storage.TrashItem `json:"items"`
+	Total  int64               `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+func NewTrashHandlers(trash storage.TrashRepository, logger *slog.Logger) *TrashHandlers {
+	return &TrashHandlers{trash: trash, logger: logger}
+}
+
+// List возвращает страницу удалённых артистов и песен, последние удалённые первыми. Фильтр: type
+func (h *TrashHandlers) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter storage.TrashFilter
+		err    error
+	)
+
+	filter.Limit, filter.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	switch v := storage.TrashType(r.URL.Query().Get("type")); v {
+	case "", storage.TrashArtist, storage.TrashSong:
+		filter.Type = v
+	default:
+		response.RenderError(w, r, h.logger, request.BadRequest("invalid type %q, allowed: artist, song", v))
+		return
+	}
+
+	page, err := h.trash.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response: response.OK(),
+		Items:    page.Items,
+		Total:    page.Total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type UserHandlers struct {
+	users  storage.UserRepository
+	logger *slog.Logger
+}
+
+type ResponseList struct {
+	response.Response
+	Users  []models.User `json:"users"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+type ResponseSingle struct {
+	response.Response
+	User models.User `json:"user"`
+}
+
+func NewUserHandlers(users storage.UserRepository, logger *slog.Logger) *UserHandlers {
+	return &UserHandlers{users: users, logger: logger}
+}
+
+// List возвращает страницу пользователей по возрастанию id. Фильтр: role
+func (h *UserHandlers) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter storage.UserFilter
+		err    error
+	)
+
+	filter.Limit, filter.Offset, err = request.Page(r, defaultLimit, maxLimit)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	if v := r.URL.Query().Get("role"); v != "" {
+		if filter.Role, err = parseRole(v); err != nil {
+			response.RenderError(w, r, h.logger, err)
+			return
+		}
+	}
+
+	page, err := h.users.List(r.Context(), filter)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseList{
+		Response: response.OK(),
+		Users:    page.Users,
+		Total:    page.Total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+}
+
+type RequestSetRole struct {
+	Role models.Role `json:"role" validate:"required,oneof=viewer editor admin"`
+}
+
+// SetRole меняет роль пользователя. Действует после обновления его токенов
+func (h *UserHandlers) SetRole(w http.ResponseWriter, r *http.Request) {
+	id, err := request.ID(r, "id")
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	var req RequestSetRole
+	if err := request.DecodeJSON(w, r, &req); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	// Иначе единственный администратор может случайно лишить сервис администраторов
+	if self, _ := request.UserID(r); self == id && req.Role != models.RoleAdmin {
+		response.RenderError(w, r, h.logger, request.BadRequest("cannot demote yourself"))
+		return
+	}
+
+	if err := h.users.SetRole(r.Context(), id, req.Role); err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		response.RenderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ResponseSingle{
+		Response: response.OK(),
+		User:     user,
+	})
+}
+
+func parseRole(v string) (models.Role, error) {
+	for _, role := range models.Roles {
+		if string(role) == v {
+			return role, nil
+		}
+	}
+
+	return "", request.BadRequest("invalid role %q, allowed: viewer, editor, admin", v)
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-lib/internal/lib/actor"
+	libauth "music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"time"
+)
+
+// touchInterval ограничивает запись last_used_at: ключ, которым пользуются часто,
+// не должен порождать запись в хранилище на каждый запрос.
+const touchInterval = time.Minute
+
+type apiKeyAuth struct {
+	keys storage.APIKeyRepository
+	log  *slog.Logger
+}
+
+func (a *apiKeyAuth) serve(w http.ResponseWriter, r *http.Request, next http.Handler, raw string) {
+	key, err := a.verify(r.Context(), raw)
+	if err != nil {
+		unauthorized(w, r, a.log, err)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(actor.WithAPIKey(r.Context(), key.ID, key.Scopes.Role())))
+}
+
+// verify находит ключ и проверяет хеш и срок действия.
+func (a *apiKeyAuth) verify(ctx context.Context, raw string) (models.APIKey, error) {
+	prefix, ok := libauth.APIKeyPrefixOf(raw)
+	if !ok {
+		return models.APIKey{}, libauth.ErrInvalidAPIKey
+	}
+
+	key, err := a.keys.GetByPrefix(ctx, prefix)
+	if errors.Is(err, storage.ErrAPIKeyNotFound) {
+		return models.APIKey{}, libauth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	if err := libauth.CheckAPIKey(key.Hash, raw); err != nil {
+		return models.APIKey{}, err
+	}
+
+	now := time.Now().UTC()
+	if key.Expired(now) {
+		return models.APIKey{}, libauth.ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		// Ошибка учёта не должна мешать самому запросу
+		if err := a.keys.Touch(ctx, key.ID, now); err != nil {
+			a.log.Warn("failed to record API key usage", slog.Uint64("api_key_id", uint64(key.ID)), slog.Any("error", err))
+		}
+	}
+
+	return key, nil
+}
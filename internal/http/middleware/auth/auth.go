@@ -0,0 +1,92 @@
+// Package auth аутентифицирует запросы по JWT или API-ключу и проверяет роли.
+package auth
+
+import (
+	"log/slog"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	libauth "music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader - альтернатива Authorization: Bearer для API-ключей.
+const APIKeyHeader = "X-API-Key"
+
+// New сохраняет в контексте пользователя из access-токена или API-ключа. Принимаются
+// заголовки Authorization: Bearer <access token | API key> и X-API-Key: <API key>.
+// Запрос без них считается анонимным, с недействительными данными - отклоняется.
+//
+// Роль пользователя берётся из токена, поэтому её изменение вступает в силу после
+// обновления токенов. Роль ключа определяется его scope.
+func New(tokens *libauth.Tokens, keys storage.APIKeyRepository, log *slog.Logger) func(http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middleware/auth"))
+	apiKeys := &apiKeyAuth{keys: keys, log: log}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				apiKeys.serve(w, r, next, key)
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") {
+				unauthorized(w, r, log, libauth.ErrInvalidToken)
+				return
+			}
+			token = strings.TrimSpace(token)
+
+			if libauth.IsAPIKey(token) {
+				apiKeys.serve(w, r, next, token)
+				return
+			}
+
+			claims, err := tokens.Parse(token, libauth.TokenAccess)
+			if err != nil {
+				unauthorized(w, r, log, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(actor.WithUser(r.Context(), claims.UserID(), claims.Role)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Require пропускает только пользователей и ключи с ролью не ниже role. Анонимный
+// запрос получает 401, недостаточная роль - 403.
+func Require(role models.Role, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			have := request.Role(r)
+			if have == "" {
+				unauthorized(w, r, log, request.ErrUnauthorized)
+				return
+			}
+			if !have.Allows(role) {
+				response.RenderError(w, r, log, request.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="music-lib"`)
+	response.RenderError(w, r, log, err)
+}
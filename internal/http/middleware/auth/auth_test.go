@@ -0,0 +1,140 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	mwAuth "music-lib/internal/http/middleware/auth"
+	libauth "music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"music-lib/internal/storage/memory"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRequire(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tokens := libauth.NewTokens(key, libauth.TokenConfig{Issuer: "test", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	r := chi.NewRouter()
+	keys := memory.New().APIKeys()
+	r.Use(mwAuth.New(tokens, keys, log))
+	r.Get("/public", func(w http.ResponseWriter, r *http.Request) {})
+	r.With(mwAuth.Require(models.RoleEditor, log)).Get("/editor", func(w http.ResponseWriter, r *http.Request) {})
+
+	bearer := func(role models.Role, typ libauth.TokenType) string {
+		pair, err := tokens.Issue(models.User{ID: 1, Role: role})
+		if err != nil {
+			t.Fatalf("Issue: %v", err)
+		}
+		if typ == libauth.TokenRefresh {
+			return "Bearer " + pair.RefreshToken
+		}
+		return "Bearer " + pair.AccessToken
+	}
+
+	writeKey := newAPIKey(t, keys, models.Scopes{models.ScopeSongsWrite}, nil)
+	readKey := newAPIKey(t, keys, models.Scopes{models.ScopeSongsRead}, nil)
+	expiredKey := newAPIKey(t, keys, models.Scopes{models.ScopeAdmin}, ptr(time.Now().Add(-time.Second)))
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		apiKey string
+		want   int
+	}{
+		{name: "anonymous public", path: "/public", want: http.StatusOK},
+		{name: "anonymous protected", path: "/editor", want: http.StatusUnauthorized},
+		{name: "viewer", path: "/editor", header: bearer(models.RoleViewer, libauth.TokenAccess), want: http.StatusForbidden},
+		{name: "editor", path: "/editor", header: bearer(models.RoleEditor, libauth.TokenAccess), want: http.StatusOK},
+		{name: "admin", path: "/editor", header: bearer(models.RoleAdmin, libauth.TokenAccess), want: http.StatusOK},
+		{name: "refresh token", path: "/editor", header: bearer(models.RoleAdmin, libauth.TokenRefresh), want: http.StatusUnauthorized},
+		{name: "invalid token on public route", path: "/public", header: "Bearer garbage", want: http.StatusUnauthorized},
+		{name: "basic scheme", path: "/editor", header: "Basic dXNlcjpwYXNz", want: http.StatusUnauthorized},
+
+		{name: "api key header", path: "/editor", apiKey: writeKey, want: http.StatusOK},
+		{name: "api key as bearer", path: "/editor", header: "Bearer " + writeKey, want: http.StatusOK},
+		{name: "api key without scope", path: "/editor", apiKey: readKey, want: http.StatusForbidden},
+		{name: "expired api key", path: "/editor", apiKey: expiredKey, want: http.StatusUnauthorized},
+		{name: "wrong api key secret", path: "/editor", apiKey: writeKey[:len(writeKey)-4] + "AAAA", want: http.StatusUnauthorized},
+		{name: "unknown api key", path: "/public", apiKey: "ml_000000000000_secret", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(mwAuth.APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyLastUsed(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tokens := libauth.NewTokens(key, libauth.TokenConfig{Issuer: "test", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	keys := memory.New().APIKeys()
+	raw := newAPIKey(t, keys, models.Scopes{models.ScopeSongsRead}, nil)
+
+	handler := mwAuth.New(tokens, keys, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(mwAuth.APIKeyHeader, raw)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	prefix, _ := libauth.APIKeyPrefixOf(raw)
+	stored, err := keys.GetByPrefix(context.Background(), prefix)
+	if err != nil {
+		t.Fatalf("GetByPrefix: %v", err)
+	}
+	if stored.LastUsedAt == nil {
+		t.Error("last_used_at is not recorded")
+	}
+}
+
+// newAPIKey сохраняет ключ в keys и возвращает его открытое значение.
+func newAPIKey(t *testing.T, keys storage.APIKeyRepository, scopes models.Scopes, expiresAt *time.Time) string {
+	t.Helper()
+
+	raw, prefix, hash, err := libauth.NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey: %v", err)
+	}
+
+	key := models.APIKey{Name: "test", Prefix: prefix, Hash: hash, Scopes: scopes, ExpiresAt: expiresAt}
+	if err := keys.Create(context.Background(), &key); err != nil {
+		t.Fatalf("create API key: %v", err)
+	}
+
+	return raw
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
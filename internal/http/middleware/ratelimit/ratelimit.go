@@ -0,0 +1,111 @@
+// Package ratelimit ограничивает частоту запросов каждого клиента.
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	"music-lib/internal/lib/ratelimit"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Limits - лимиты клиентов.
+type Limits struct {
+	// IP - общий лимит адреса. Проверяется до аутентификации, поэтому ограничивает и
+	// запросы с неверными токенами и ключами, которые до New не доходят.
+	IP ratelimit.Limit
+	// Anonymous и Authenticated - лимиты анонимных клиентов и пользователей и API-ключей.
+	Anonymous     ratelimit.Limit
+	Authenticated ratelimit.Limit
+	// Credentials - лимит адреса на вход и регистрацию, строже остальных: защищает
+	// от перебора паролей.
+	Credentials ratelimit.Limit
+}
+
+// New отклоняет с 429 запросы клиентов, исчерпавших лимит. Клиент определяется по
+// API-ключу, затем по пользователю, а анонимный - по IP, поэтому middleware ставится
+// после аутентификации и realip.
+//
+// Каждый ответ получает заголовки RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// и RateLimit-Policy, отклонённый - ещё и Retry-After. Если хранилище недоступно,
+// запрос пропускается: лучше временно остаться без лимита, чем без API.
+func New(store ratelimit.Store, limits Limits, log *slog.Logger) func(http.Handler) http.Handler {
+	return middleware(store, log, func(r *http.Request) (string, ratelimit.Limit) {
+		return client(r, limits)
+	})
+}
+
+// NewIP ограничивает запросы с одного IP независимо от аутентификации. У каждого scope
+// свои корзины. Ставится после realip; заголовки те же, что у New, и
+// middleware, стоящее дальше, их перезаписывает.
+func NewIP(store ratelimit.Store, scope string, limit ratelimit.Limit, log *slog.Logger) func(http.Handler) http.Handler {
+	return middleware(store, log, func(r *http.Request) (string, ratelimit.Limit) {
+		return scope + ":" + clientIP(r), limit
+	})
+}
+
+func middleware(
+	store ratelimit.Store, log *slog.Logger, client func(*http.Request) (string, ratelimit.Limit),
+) func(http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middleware/ratelimit"))
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key, limit := client(r)
+
+			res, err := store.Take(r.Context(), key, limit)
+			if err != nil {
+				log.Warn("rate limit store failed, request allowed", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window())))
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+				response.RenderError(w, r, log, ratelimit.ErrLimitExceeded)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// client возвращает ключ корзины клиента и его лимит.
+func client(r *http.Request, limits Limits) (string, ratelimit.Limit) {
+	if id, ok := request.APIKeyID(r); ok {
+		return "key:" + strconv.FormatUint(uint64(id), 10), limits.Authenticated
+	}
+	if id, ok := request.UserID(r); ok {
+		return "user:" + strconv.FormatUint(uint64(id), 10), limits.Authenticated
+	}
+
+	return "ip:" + clientIP(r), limits.Anonymous
+}
+
+func clientIP(r *http.Request) string {
+	// realip заменяет RemoteAddr адресом без порта, только если запрос пришёл от доверенного прокси
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	return ip
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,123 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	mwRateLimit "music-lib/internal/http/middleware/ratelimit"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/lib/ratelimit"
+	"music-lib/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimit(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	limits := mwRateLimit.Limits{
+		Anonymous:     ratelimit.Limit{Rate: 1, Burst: 2},
+		Authenticated: ratelimit.Limit{Rate: 1, Burst: 5},
+	}
+	handler := mwRateLimit.New(ratelimit.NewMemoryStore(), limits, log)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	serve := func(ctx context.Context, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/songs", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	anon := context.Background()
+	for i := range 2 {
+		if rec := serve(anon, "10.0.0.1:1000"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	// Другой порт того же IP - тот же клиент
+	rec := serve(anon, "10.0.0.1:2000")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	for header, want := range map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "2;w=2",
+		"Retry-After":         "1",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	if rec := serve(anon, "10.0.0.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("other IP: status = %d, want 200", rec.Code)
+	}
+
+	// Пользователь получает свою корзину независимо от IP
+	user := actor.WithUser(context.Background(), 7, models.RoleViewer)
+	rec = serve(user, "10.0.0.1:1000")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("user: status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "5" {
+		t.Errorf("user: RateLimit-Limit = %q, want 5", got)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimitStoreFailure(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := mwRateLimit.New(failingStore{}, mwRateLimit.Limits{}, log)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitIP(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Burst: 1}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func(handler http.Handler, ctx context.Context) int {
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = "10.0.0.1:1000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	login := mwRateLimit.NewIP(store, "credentials", limit, log)(ok)
+	if code := serve(login, context.Background()); code != http.StatusOK {
+		t.Fatalf("first request: status = %d, want 200", code)
+	}
+
+	// Пользователь не получает отдельной корзины: лимит считается по адресу
+	user := actor.WithUser(context.Background(), 7, models.RoleViewer)
+	if code := serve(login, user); code != http.StatusTooManyRequests {
+		t.Errorf("user from the same IP: status = %d, want 429", code)
+	}
+
+	// У другого scope свои корзины
+	if code := serve(mwRateLimit.NewIP(store, "addr", limit, log)(ok), context.Background()); code != http.StatusOK {
+		t.Errorf("other scope: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,77 @@
+// Package realip определяет адрес клиента за обратным прокси.
+package realip
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// New заменяет r.RemoteAddr адресом клиента без порта, если запрос пришёл от доверенного
+// прокси из trusted. Адрес берётся из X-Forwarded-For: справа налево пропускаются
+// доверенные прокси, первый чужой адрес и есть клиент. Без X-Forwarded-For используется
+// X-Real-IP. Заголовки запросов не от доверенных прокси игнорируются: иначе клиент
+// подставил бы любой адрес и обошёл лимиты по IP.
+func New(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := clientIP(r, trusted); ok {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// clientIP возвращает адрес клиента из заголовков прокси, если им можно верить.
+func clientIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	peer, ok := parseAddr(r.RemoteAddr)
+	if !ok || !isTrusted(peer, trusted) {
+		return netip.Addr{}, false
+	}
+
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, ok := parseAddr(strings.TrimSpace(hops[i]))
+			if !ok {
+				break
+			}
+			client = addr
+			if !isTrusted(addr, trusted) {
+				break
+			}
+		}
+		return client, client.IsValid()
+	}
+
+	return parseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+}
+
+// parseAddr разбирает адрес с портом или без.
+func parseAddr(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package realip_test
+
+import (
+	"music-lib/internal/http/middleware/realip"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	var got string
+	handler := realip.New(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{
+		{
+			name: "direct client spoofs header", remoteAddr: "203.0.113.7:4000",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:   "203.0.113.7:4000",
+		},
+		{
+			name: "trusted proxy", remoteAddr: "10.0.0.2:4000",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:   "198.51.100.1",
+		},
+		{
+			name: "client prepends fake hop", remoteAddr: "10.0.0.2:4000",
+			header: map[string]string{"X-Forwarded-For": "192.0.2.9, 198.51.100.1, 10.0.0.3"},
+			want:   "198.51.100.1",
+		},
+		{
+			name: "real ip header", remoteAddr: "10.0.0.2:4000",
+			header: map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:   "198.51.100.1",
+		},
+		{
+			name: "invalid header", remoteAddr: "10.0.0.2:4000",
+			header: map[string]string{"X-Real-IP": "unknown"},
+			want:   "10.0.0.2:4000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
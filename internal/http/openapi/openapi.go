@@ -0,0 +1,916 @@
+// Package openapi собирает спецификацию OpenAPI 3 по описаниям эндпоинтов и
+// Go-типам запросов и ответов, а также раздаёт её вместе со Swagger UI.
+package openapi
+
+import (
+	_ "embed"
+	"music-lib/internal/http/handlers/album"
+	"music-lib/internal/http/handlers/apikey"
+	"music-lib/internal/http/handlers/artist"
+	"music-lib/internal/http/handlers/audit"
+	"music-lib/internal/http/handlers/auth"
+	"music-lib/internal/http/handlers/cover"
+	"music-lib/internal/http/handlers/importer"
+	"music-lib/internal/http/handlers/playlist"
+	"music-lib/internal/http/handlers/search"
+	"music-lib/internal/http/handlers/song"
+	"music-lib/internal/http/handlers/trash"
+	"music-lib/internal/http/handlers/user"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/api/response"
+	libcover "music-lib/internal/lib/cover"
+	"music-lib/internal/models"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/render"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+const Version = "1.0.0"
+
+//go:embed swagger/swagger-initializer.js
+var swaggerInitializer []byte
+
+// endpoint описывает один маршрут router.New.
+type endpoint struct {
+	method  string
+	path    string
+	summary string
+	tag     string
+	// role - минимальная роль из токена; пустая - эндпоинт доступен без входа
+	role    models.Role
+	params  []Parameter
+	request *body
+	// upload - типы содержимого тела, которое передаётся файлом, а не JSON, с примерами;
+	// для multipart/form-data вместо примера - имя поля с файлом
+	upload map[string]string
+	// status - код успешного ответа, по умолчанию 200
+	status   int
+	response *body
+	// download - типы содержимого успешного ответа, который отдаётся файлом или текстом, с примерами
+	download map[string]string
+	// errors - коды ошибок из components/responses
+	errors []int
+}
+
+// body - тело запроса или ответа с именем схемы в components.
+type body struct {
+	name  string
+	value any
+}
+
+var (
+	includeSongsParam = query("include", "string", "songs - добавить песни артиста, songs.details - песни с деталями")
+
+	songFilterParams = []Parameter{
+		query("name", "string", "Подстрока названия песни"),
+		query("artist_id", "integer", "ID артиста"),
+		query("artist", "string", "Подстрока имени артиста"),
+		query("release_from", "string", "Дата релиза не раньше, DD.MM.YYYY или YYYY-MM-DD"),
+		query("release_to", "string", "Дата релиза не позже, DD.MM.YYYY или YYYY-MM-DD"),
+		query("has_link", "boolean", "Наличие ссылки"),
+		query("text", "string", "Подстрока текста песни"),
+		query("credit_artist_id", "integer", "ID артиста в титрах песни"),
+		query("role", "string", "Роль в титрах: primary, featured, composer, lyricist или producer"),
+	}
+
+	songListParams = []Parameter{
+		query("sort", "string", "id, name или release_date; минус в начале - по убыванию"),
+		query("include", "string", "details - добавить детали песен, credits - титры; можно через запятую"),
+		query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+		query("offset", "integer", "Смещение; игнорируется при заданном cursor"),
+	}
+
+	exportFormatParam = query("format", "string", "csv, jsonl или xlsx; важнее заголовка Accept, без обоих - csv")
+
+	thresholdParam = query("threshold", "number", "Минимальное сходство от 0 до 1; по умолчанию из настроек сервиса")
+
+	ifMatchParam = header("If-Match", true, "ETag плейлиста из последнего ответа, например \"3\"")
+	itemIDParam  = pathParam("item_id", "ID элемента плейлиста")
+)
+
+var endpoints = []endpoint{
+	{
+		method: http.MethodGet, path: "/health", tag: "system",
+		summary:  "Проверка доступности сервиса",
+		download: map[string]string{"text/plain": "OK"},
+	},
+
+	{
+		method: http.MethodPost, path: "/auth/register", tag: "auth",
+		summary:  "Регистрация пользователя с ролью viewer",
+		request:  &body{"RegisterRequest", auth.RequestRegister{}},
+		status:   http.StatusCreated,
+		response: &body{"Tokens", auth.ResponseTokens{}},
+		errors:   []int{400, 409, 500},
+	},
+	{
+		method: http.MethodPost, path: "/auth/login", tag: "auth",
+		summary:  "Вход по email и паролю",
+		request:  &body{"LoginRequest", auth.RequestLogin{}},
+		response: &body{"Tokens", auth.ResponseTokens{}},
+		errors:   []int{400, 401, 500},
+	},
+	{
+		method: http.MethodPost, path: "/auth/refresh", tag: "auth",
+		summary:  "Новая пара токенов по refresh-токену",
+		request:  &body{"RefreshRequest", auth.RequestRefresh{}},
+		response: &body{"Tokens", auth.ResponseTokens{}},
+		errors:   []int{400, 401, 500},
+	},
+	{
+		method: http.MethodGet, path: "/auth/me", tag: "auth",
+		summary:  "Текущий пользователь",
+		role:     models.RoleViewer,
+		response: &body{"CurrentUser", auth.ResponseUser{}},
+		errors:   []int{404, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/users", tag: "users",
+		summary: "Список пользователей",
+		role:    models.RoleAdmin,
+		params: []Parameter{
+			query("role", "string", "viewer, editor или admin"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"UserList", user.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodPut, path: "/users/{id}/role", tag: "users",
+		summary:  "Изменение роли; действует после обновления токенов пользователя",
+		role:     models.RoleAdmin,
+		params:   []Parameter{pathID("ID пользователя")},
+		request:  &body{"UserRoleRequest", user.RequestSetRole{}},
+		response: &body{"UserSingle", user.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/api-keys", tag: "api keys",
+		summary:  "Список API-ключей без секретов",
+		role:     models.RoleAdmin,
+		response: &body{"APIKeyList", apikey.ResponseList{}},
+		errors:   []int{500},
+	},
+	{
+		method: http.MethodPost, path: "/api-keys", tag: "api keys",
+		summary:  "Выпуск API-ключа; ключ возвращается только в этом ответе",
+		role:     models.RoleAdmin,
+		request:  &body{"APIKeyCreateRequest", apikey.RequestCreate{}},
+		status:   http.StatusCreated,
+		response: &body{"APIKeyIssued", apikey.ResponseIssued{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodGet, path: "/api-keys/{id}", tag: "api keys",
+		summary:  "API-ключ по ID без секрета",
+		role:     models.RoleAdmin,
+		params:   []Parameter{pathID("ID ключа")},
+		response: &body{"APIKeySingle", apikey.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/api-keys/{id}", tag: "api keys",
+		summary: "Отзыв API-ключа",
+		role:    models.RoleAdmin,
+		params:  []Parameter{pathID("ID ключа")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPost, path: "/api-keys/{id}/rotate", tag: "api keys",
+		summary: "Ротация API-ключа: новый ключ с теми же scope, старый работает ещё grace",
+		role:    models.RoleAdmin,
+		params: []Parameter{
+			pathID("ID ключа"),
+			query("grace", "string", "Сколько ещё действует старый ключ, например 24h; 0-168h, по умолчанию 0"),
+		},
+		status:   http.StatusCreated,
+		response: &body{"APIKeyIssued", apikey.ResponseIssued{}},
+		errors:   []int{400, 404, 409, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/artists", tag: "artists",
+		summary:  "Список артистов; песни артиста - /artists/{id}/songs",
+		response: &body{"ArtistList", artist.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodPost, path: "/artists", tag: "artists",
+		summary:  "Создание артиста",
+		role:     models.RoleEditor,
+		request:  &body{"ArtistCreateRequest", artist.RequestCreate{}},
+		status:   http.StatusCreated,
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 409, 422, 500},
+	},
+	{
+		method: http.MethodGet, path: "/artists/{id}", tag: "artists",
+		summary:  "Артист по ID",
+		params:   []Parameter{pathID("ID артиста"), includeSongsParam},
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/artists/{id}", tag: "artists",
+		summary:  "Изменение артиста",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID артиста")},
+		request:  &body{"ArtistUpdateRequest", artist.RequestUpdate{}},
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 422, 500},
+	},
+	{
+		method: http.MethodPut, path: "/artists/{id}/cover", tag: "artists",
+		summary:  "Загрузка обложки артиста: JPEG, PNG или WebP; копии 64, 256 и 1024 px создаются сразу",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID артиста")},
+		upload:   map[string]string{multipartForm: cover.FileField},
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 404, 413, 415, 422, 500, 503},
+	},
+	{
+		method: http.MethodDelete, path: "/artists/{id}/cover", tag: "artists",
+		summary:  "Удаление обложки артиста",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID артиста")},
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/artists/{id}", tag: "artists",
+		summary: "Перенос артиста вместе с его песнями в корзину",
+		role:    models.RoleAdmin,
+		params:  []Parameter{pathID("ID артиста")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPost, path: "/artists/{id}/restore", tag: "artists",
+		summary:  "Восстановление артиста из корзины вместе с песнями, удалёнными вместе с ним",
+		role:     models.RoleAdmin,
+		params:   []Parameter{pathID("ID артиста в корзине")},
+		response: &body{"ArtistSingle", artist.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 500},
+	},
+	{
+		method: http.MethodGet, path: "/artists/{id}/songs", tag: "artists",
+		summary: "Дискография артиста",
+		params: append([]Parameter{
+			pathID("ID артиста"),
+			query("role", "string", "Песни, где у артиста эта роль в титрах (primary, featured, composer, lyricist, producer) или any - любая"),
+		}, songListParams...),
+		response: &body{"ArtistDiscography", song.ResponseDiscography{}},
+		errors:   []int{400, 404, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/songs", tag: "songs",
+		summary: "Список песен с фильтрами и пагинацией",
+		params: slices.Concat(songFilterParams, []Parameter{
+			query("cursor", "string", "Значение next_cursor из предыдущего ответа; только с sort=id"),
+		}, songListParams),
+		response: &body{"SongList", song.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodPost, path: "/songs", tag: "songs",
+		summary:  "Создание песни с обогащением из внешнего API",
+		role:     models.RoleEditor,
+		request:  &body{"SongCreateRequest", song.RequestCreate{}},
+		status:   http.StatusCreated,
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 422, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}", tag: "songs",
+		summary: "Песня по ID",
+		params: []Parameter{
+			pathID("ID песни"),
+			query("include", "string", "details - добавить в ответ артиста и детали песни, credits - титры"),
+		},
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/songs/{id}", tag: "songs",
+		summary:  "Изменение песни",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		request:  &body{"SongUpdateRequest", song.RequestUpdate{}},
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 422, 500},
+	},
+	{
+		method: http.MethodPut, path: "/songs/{id}/cover", tag: "songs",
+		summary:  "Загрузка обложки песни: JPEG, PNG или WebP; копии 64, 256 и 1024 px создаются сразу",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		upload:   map[string]string{multipartForm: cover.FileField},
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 413, 415, 422, 500, 503},
+	},
+	{
+		method: http.MethodDelete, path: "/songs/{id}/cover", tag: "songs",
+		summary:  "Удаление обложки песни",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/songs/{id}", tag: "songs",
+		summary: "Перенос песни в корзину",
+		role:    models.RoleEditor,
+		params:  []Parameter{pathID("ID песни")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPost, path: "/songs/{id}/restore", tag: "songs",
+		summary:  "Восстановление песни из корзины; 409, если её артист тоже в корзине",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни в корзине")},
+		response: &body{"SongSingle", song.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/lyrics", tag: "songs",
+		summary: "Текст песни по куплетам",
+		params: []Parameter{
+			pathID("ID песни"),
+			query("page", "integer", "Номер страницы, с 1"),
+			query("per_page", "integer", "Куплетов на странице, 1-50, по умолчанию 10"),
+		},
+		response: &body{"SongLyrics", song.ResponseLyrics{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/credits", tag: "songs",
+		summary:  "Титры песни",
+		params:   []Parameter{pathID("ID песни")},
+		response: &body{"SongCredits", song.ResponseCredits{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/songs/{id}/credits", tag: "songs",
+		summary:  "Замена титров песни",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		request:  &body{"SongCreditsRequest", song.RequestCredits{}},
+		response: &body{"SongCredits", song.ResponseCredits{}},
+		errors:   []int{400, 404, 422, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/details", tag: "song details",
+		summary:  "Детали песни",
+		params:   []Parameter{pathID("ID песни")},
+		response: &body{"SongDetailSingle", song.ResponseDetail{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/songs/{id}/details", tag: "song details",
+		summary:  "Создание или полная замена деталей песни",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		request:  &body{"SongDetailPutRequest", song.RequestDetailPut{}},
+		response: &body{"SongDetailSingle", song.ResponseDetail{}},
+		errors:   []int{400, 404, 422, 500},
+	},
+	{
+		method: http.MethodPatch, path: "/songs/{id}/details", tag: "song details",
+		summary:  "Частичное изменение деталей песни",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни")},
+		request:  &body{"SongDetailPatchRequest", song.RequestDetailPatch{}},
+		response: &body{"SongDetailSingle", song.ResponseDetail{}},
+		errors:   []int{400, 404, 422, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/songs/{id}/details", tag: "song details",
+		summary: "Удаление деталей песни",
+		role:    models.RoleEditor,
+		params:  []Parameter{pathID("ID песни")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/lyrics/revisions", tag: "song details",
+		summary: "История версий текста песни, новые первыми",
+		params: []Parameter{
+			pathID("ID песни"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"LyricsRevisionList", song.ResponseRevisions{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/lyrics/revisions/diff", tag: "song details",
+		summary: "Построчный diff двух версий текста",
+		params: []Parameter{
+			pathID("ID песни"),
+			query("from", "integer", "Номер исходной версии, обязателен"),
+			query("to", "integer", "Номер итоговой версии, обязателен"),
+		},
+		response: &body{"LyricsRevisionDiff", song.ResponseRevisionDiff{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodGet, path: "/songs/{id}/lyrics/revisions/{rev}", tag: "song details",
+		summary:  "Версия текста песни",
+		params:   []Parameter{pathID("ID песни"), pathParam("rev", "Номер версии")},
+		response: &body{"LyricsRevisionSingle", song.ResponseRevision{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPost, path: "/songs/{id}/lyrics/revisions/{rev}/restore", tag: "song details",
+		summary:  "Откат текста песни к версии: сохраняется как новая версия",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID песни"), pathParam("rev", "Номер версии")},
+		response: &body{"SongDetailSingle", song.ResponseDetail{}},
+		errors:   []int{400, 404, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/albums", tag: "albums",
+		summary: "Список альбомов, новые релизы первыми",
+		params: []Parameter{
+			query("artist_id", "integer", "ID артиста"),
+			query("release_type", "string", "lp, ep, single или compilation"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"AlbumList", album.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodPost, path: "/albums", tag: "albums",
+		summary:  "Создание альбома",
+		role:     models.RoleEditor,
+		request:  &body{"AlbumCreateRequest", album.RequestCreate{}},
+		status:   http.StatusCreated,
+		response: &body{"AlbumSingle", album.ResponseSingle{}},
+		errors:   []int{400, 404, 422, 500},
+	},
+	{
+		method: http.MethodGet, path: "/albums/{id}", tag: "albums",
+		summary: "Альбом по ID",
+		params: []Parameter{
+			pathID("ID альбома"),
+			query("include", "string", "tracks - добавить артиста и треклист"),
+		},
+		response: &body{"AlbumSingle", album.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/albums/{id}", tag: "albums",
+		summary:  "Изменение альбома",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID альбома")},
+		request:  &body{"AlbumUpdateRequest", album.RequestUpdate{}},
+		response: &body{"AlbumSingle", album.ResponseSingle{}},
+		errors:   []int{400, 404, 422, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/albums/{id}", tag: "albums",
+		summary: "Удаление альбома; песни остаются без альбома",
+		role:    models.RoleEditor,
+		params:  []Parameter{pathID("ID альбома")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/albums/{id}/tracks", tag: "albums",
+		summary:  "Замена треклиста альбома",
+		role:     models.RoleEditor,
+		params:   []Parameter{pathID("ID альбома")},
+		request:  &body{"AlbumTracksRequest", album.RequestTracks{}},
+		response: &body{"AlbumSingle", album.ResponseSingle{}},
+		errors:   []int{400, 404, 409, 422, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/trash", tag: "trash",
+		summary: "Удалённые артисты и песни, последние удалённые первыми",
+		role:    models.RoleEditor,
+		params: []Parameter{
+			query("type", "string", "artist или song"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"TrashList", trash.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/audit", tag: "audit",
+		summary: "Журнал изменений артистов, песен и деталей песен, последние записи первыми",
+		role:    models.RoleAdmin,
+		params: []Parameter{
+			query("entity", "string", "artist, song или song_detail"),
+			query("id", "integer", "ID сущности, только вместе с entity; для song_detail - ID песни"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"AuditList", audit.ResponseList{}},
+		errors:   []int{400, 500},
+	},
+
+	{
+		method: http.MethodPost, path: "/import", tag: "import",
+		summary: "Массовый импорт артистов, песен и деталей из CSV или JSON Lines",
+		role:    models.RoleEditor,
+		params: []Parameter{
+			query("format", "string", "csv или jsonl; по умолчанию определяется по Content-Type"),
+			query("dry_run", "boolean", "true - проверить файл и вернуть отчёт, ничего не сохраняя"),
+		},
+		upload: map[string]string{
+			"text/csv":             "artist,is_group,song,release_date,text,link\nMuse,true,Supermassive Black Hole,16.07.2006,\"Ooh baby, don't you know I suffer?\",https://www.youtube.com/watch?v=Xsp3_a-PMTw\n",
+			"application/x-ndjson": `{"artist":"Muse","is_group":true,"song":"Supermassive Black Hole","release_date":"16.07.2006","text":"Ooh baby, don't you know I suffer?"}` + "\n",
+		},
+		response: &body{"ImportReport", importer.ResponseImport{}},
+		errors:   []int{400, 413, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/export/songs", tag: "export",
+		summary: "Выгрузка песен с артистами и деталями в CSV, JSON Lines или XLSX",
+		role:    models.RoleViewer,
+		params: slices.Concat(songFilterParams, []Parameter{
+			query("sort", "string", "id, name или release_date; минус в начале - по убыванию"),
+			exportFormatParam,
+		}),
+		download: exportExamples(
+			"id,name,artist_id,artist_name,album_id,album_title,disc_number,track_number,release_date,link,text\n"+
+				"1,Supermassive Black Hole,1,Muse,1,Black Holes and Revelations,1,2,2006-07-16,https://www.youtube.com/watch?v=Xsp3_a-PMTw,Ooh baby\n",
+			`{"id":1,"name":"Supermassive Black Hole","artist_id":1,"artist_name":"Muse","album_id":1,"album_title":"Black Holes and Revelations","disc_number":1,"track_number":2,"release_date":"2006-07-16","link":"https://www.youtube.com/watch?v=Xsp3_a-PMTw","text":"Ooh baby"}`+"\n",
+		),
+		errors: []int{400, 406, 500},
+	},
+	{
+		method: http.MethodGet, path: "/export/artists", tag: "export",
+		summary: "Выгрузка артистов с числом песен в CSV, JSON Lines или XLSX",
+		role:    models.RoleViewer,
+		params:  []Parameter{exportFormatParam},
+		download: exportExamples(
+			"id,name,is_group,songs\n1,Muse,true,12\n",
+			`{"id":1,"name":"Muse","is_group":true,"songs":12}`+"\n",
+		),
+		errors: []int{400, 406, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/search", tag: "search",
+		summary: "Полнотекстовый поиск по названиям, артистам и текстам песен",
+		params: []Parameter{
+			{
+				Name: "q", In: "query", Required: true,
+				Description: `Запрос: слова, "фраза в кавычках", or, -исключение`,
+				Schema:      &Schema{Type: "string"},
+			},
+			query("mode", "string", "fulltext (по умолчанию) или fuzzy - по сходству названий с учётом опечаток"),
+			query("lang", "string", "ru или en - стемминг одного языка; по умолчанию оба"),
+			thresholdParam,
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"SearchResults", search.ResponseSearch{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodGet, path: "/suggest", tag: "search",
+		summary: "Подсказки артистов и песен для автодополнения",
+		params: []Parameter{
+			{
+				Name: "q", In: "query", Required: true,
+				Description: "Начало или часть названия, допускаются опечатки",
+				Schema:      &Schema{Type: "string"},
+			},
+			thresholdParam,
+			query("limit", "integer", "Максимум артистов и песен по отдельности, 1-20, по умолчанию 5"),
+		},
+		response: &body{"Suggestions", search.ResponseSuggest{}},
+		errors:   []int{400, 500},
+	},
+
+	{
+		method: http.MethodGet, path: "/playlists", tag: "playlists",
+		summary: "Список плейлистов, недавно изменённые первыми",
+		params: []Parameter{
+			query("scope", "string", "mine (по умолчанию) - свои и совместные плейлисты, public - публичные"),
+			query("limit", "integer", "Размер страницы, 1-100, по умолчанию 20"),
+			query("offset", "integer", "Смещение"),
+		},
+		response: &body{"PlaylistList", playlist.ResponseList{}},
+		errors:   []int{400, 401, 500},
+	},
+	{
+		method: http.MethodPost, path: "/playlists", tag: "playlists",
+		summary:  "Создание плейлиста; ответ содержит ETag",
+		role:     models.RoleViewer,
+		request:  &body{"PlaylistCreateRequest", playlist.RequestCreate{}},
+		status:   http.StatusCreated,
+		response: &body{"PlaylistSingle", playlist.ResponseSingle{}},
+		errors:   []int{400, 500},
+	},
+	{
+		method: http.MethodGet, path: "/playlists/{id}", tag: "playlists",
+		summary:  "Плейлист с треками; ответ содержит ETag",
+		params:   []Parameter{pathID("ID плейлиста")},
+		response: &body{"PlaylistSingle", playlist.ResponseSingle{}},
+		errors:   []int{400, 404, 500},
+	},
+	{
+		method: http.MethodPut, path: "/playlists/{id}", tag: "playlists",
+		summary:  "Изменение плейлиста; видимость меняет только владелец",
+		role:     models.RoleViewer,
+		params:   []Parameter{pathID("ID плейлиста"), ifMatchParam},
+		request:  &body{"PlaylistUpdateRequest", playlist.RequestUpdate{}},
+		response: &body{"PlaylistSingle", playlist.ResponseSingle{}},
+		errors:   []int{400, 404, 412, 428, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/playlists/{id}", tag: "playlists",
+		summary: "Удаление плейлиста владельцем",
+		role:    models.RoleViewer,
+		params:  []Parameter{pathID("ID плейлиста"), ifMatchParam},
+		errors:  []int{400, 404, 412, 428, 500},
+	},
+	{
+		method: http.MethodPost, path: "/playlists/{id}/items", tag: "playlists",
+		summary:  "Добавление трека; без before_item_id и after_item_id - в конец",
+		role:     models.RoleViewer,
+		params:   []Parameter{pathID("ID плейлиста"), ifMatchParam},
+		request:  &body{"PlaylistAddItemRequest", playlist.RequestAddItem{}},
+		status:   http.StatusCreated,
+		response: &body{"PlaylistItem", playlist.ResponseItem{}},
+		errors:   []int{400, 404, 412, 422, 428, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/playlists/{id}/items/{item_id}", tag: "playlists",
+		summary: "Удаление трека из плейлиста",
+		role:    models.RoleViewer,
+		params:  []Parameter{pathID("ID плейлиста"), itemIDParam, ifMatchParam},
+		errors:  []int{400, 404, 412, 428, 500},
+	},
+	{
+		method: http.MethodPost, path: "/playlists/{id}/items/{item_id}/move", tag: "playlists",
+		summary: "Перенос трека перед или после другого трека",
+		role:    models.RoleViewer,
+		params:  []Parameter{pathID("ID плейлиста"), itemIDParam, ifMatchParam},
+		request: &body{"PlaylistMoveItemRequest", playlist.RequestMoveItem{}},
+		errors:  []int{400, 404, 412, 428, 500},
+	},
+	{
+		method: http.MethodPut, path: "/playlists/{id}/collaborators/{user_id}", tag: "playlists",
+		summary: "Добавление соавтора владельцем",
+		role:    models.RoleViewer,
+		params:  []Parameter{pathID("ID плейлиста"), pathParam("user_id", "ID пользователя")},
+		errors:  []int{400, 404, 500},
+	},
+	{
+		method: http.MethodDelete, path: "/playlists/{id}/collaborators/{user_id}", tag: "playlists",
+		summary: "Удаление соавтора владельцем",
+		role:    models.RoleViewer,
+		params:  []Parameter{pathID("ID плейлиста"), pathParam("user_id", "ID пользователя")},
+		errors:  []int{400, 404, 500},
+	},
+}
+
+// exportExamples - примеры ответа выгрузки: CSV и JSON Lines текстом, XLSX двоичным файлом.
+func exportExamples(csv, jsonl string) map[string]string {
+	return map[string]string{
+		"text/csv":             csv,
+		"application/x-ndjson": jsonl,
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": "",
+	}
+}
+
+// fileContent описывает тело, которое передаётся не JSON, по типам содержимого с примерами.
+// Тип без примера считается двоичным.
+// multipartForm - тип тела с файлом в поле формы.
+const multipartForm = "multipart/form-data"
+
+func fileContent(examples map[string]string) map[string]*MediaType {
+	content := make(map[string]*MediaType, len(examples))
+	for contentType, example := range examples {
+		if contentType == multipartForm {
+			content[contentType] = &MediaType{Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{example: {Type: "string", Format: "binary"}},
+				Required:   []string{example},
+			}}
+			continue
+		}
+		if example == "" {
+			content[contentType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			continue
+		}
+		content[contentType] = &MediaType{Schema: &Schema{Type: "string"}, Example: example}
+	}
+
+	return content
+}
+
+// Схемы авторизации: access-токен или API-ключ в Authorization: Bearer либо API-ключ в X-API-Key.
+const (
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKeyAuth"
+)
+
+// errorResponses - общие ответы с ошибками. Все они используют конверт response.Response.
+var errorResponses = map[int]struct {
+	name, description string
+	example           response.Response
+}{
+	400: {"BadRequest", "Некорректный запрос или ошибка валидации", response.Error("field Name is required, field Link is invalid URL")},
+	401: {"Unauthorized", "Операция требует аутентификации", response.Error("authentication required")},
+	403: {"Forbidden", "Недостаточно прав", response.Error("forbidden")},
+	404: {"NotFound", "Сущность не найдена", response.Error("song not found")},
+	409: {"Conflict", "Конфликт с существующими данными", response.Error("artist already exists")},
+	406: {"NotAcceptable", "Ни один тип из Accept не поддерживается", response.Error(request.ErrNotAcceptable.Error())},
+	412: {"PreconditionFailed", "Запись изменилась после чтения, нужно перечитать её", response.Error("version mismatch")},
+	413: {"PayloadTooLarge", "Файл или JSON-тело запроса больше допустимого размера", response.Error(request.ErrTooLarge.Error())},
+	415: {"UnsupportedMediaType", "Формат файла не поддерживается", response.Error(libcover.ErrUnsupportedType.Error())},
+	422: {"UnprocessableEntity", "Данные нарушают ограничения хранилища", response.Error("referenced record does not exist")},
+	428: {"PreconditionRequired", "Изменение требует заголовка If-Match", response.Error("If-Match header required")},
+	429: {"TooManyRequests", "Клиент исчерпал лимит запросов, повторить через Retry-After секунд", response.Error("rate limit exceeded")},
+	500: {"InternalError", "Внутренняя ошибка сервера", response.Error("internal error")},
+	503: {"ServiceUnavailable", "Возможность отключена в настройках сервера", response.Error(libcover.ErrDisabled.Error())},
+}
+
+// Spec возвращает спецификацию. Она строится один раз при первом обращении.
+var Spec = sync.OnceValue(build)
+
+func build() *Document {
+	g := newGenerator()
+	envelope := g.component("Response", response.Response{})
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Music library API",
+			Description: "Каталог артистов, песен и текстов. Все JSON-ответы содержат поле status (OK или Error) и error при ошибке. Изменения требуют access-токена из POST /auth/login или API-ключа в заголовке Authorization: Bearer; API-ключ можно передать и в X-API-Key. Частота запросов ограничена для каждого IP, а также для каждого API-ключа, пользователя или анонимного IP; вход, регистрация и обновление токенов ограничены строже. Остаток лимита передаётся в заголовках RateLimit-*, а при превышении возвращается 429.",
+			Version:     Version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Responses: make(map[string]*Response),
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerScheme: {
+					Type: "http", Scheme: "bearer",
+					Description: "Access-токен из POST /auth/login или API-ключ",
+				},
+				apiKeyScheme: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+
+	for _, e := range errorResponses {
+		doc.Components.Responses[e.name] = &Response{
+			Description: e.description,
+			Content:     jsonContent(envelope, e.example),
+		}
+	}
+
+	for _, e := range endpoints {
+		op := &Operation{
+			OperationID: operationID(e.method, e.path),
+			Summary:     e.summary,
+			Tags:        []string{e.tag},
+			Parameters:  e.params,
+			Responses:   make(map[string]*Response),
+		}
+
+		switch {
+		case e.request != nil:
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(g.component(e.request.name, e.request.value), nil),
+			}
+		case len(e.upload) > 0:
+			op.RequestBody = &RequestBody{Required: true, Content: fileContent(e.upload)}
+		}
+
+		status := e.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		switch {
+		case e.response != nil:
+			op.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content:     jsonContent(g.component(e.response.name, e.response.value), nil),
+			}
+		case len(e.download) > 0:
+			op.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content:     fileContent(e.download),
+			}
+		default:
+			op.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content:     jsonContent(envelope, response.OK()),
+			}
+		}
+
+		errs := e.errors
+		if e.role != "" {
+			// Эндпоинты с ролью отвечают 401 без токена и 403 при недостаточной роли
+			op.Security = []SecurityRequirement{{bearerScheme: {}}, {apiKeyScheme: {}}}
+			op.Description = "Требуется роль " + string(e.role) + " или выше либо API-ключ с соответствующим scope."
+			errs = append([]int{401, 403}, errs...)
+		}
+		if e.request != nil {
+			// JSON-тело ограничено request.MaxJSONBytes
+			errs = append(errs, 413)
+		}
+		// Ограничитель частоты стоит перед всеми маршрутами
+		errs = append(errs, 429)
+
+		for _, code := range errs {
+			op.Responses[strconv.Itoa(code)] = &Response{Ref: "#/components/responses/" + errorResponses[code].name}
+		}
+
+		item, ok := doc.Paths[e.path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[e.path] = item
+		}
+		(*item)[strings.ToLower(e.method)] = op
+
+		if !slices.ContainsFunc(doc.Tags, func(t Tag) bool { return t.Name == e.tag }) {
+			doc.Tags = append(doc.Tags, Tag{Name: e.tag})
+		}
+	}
+
+	doc.Components.Schemas = g.schemas
+
+	return doc
+}
+
+// Handler отдаёт спецификацию в JSON.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, Spec())
+}
+
+// SwaggerUI раздаёт встроенный Swagger UI, смонтированный по префиксу prefix.
+func SwaggerUI(prefix string) http.Handler {
+	files := http.StripPrefix(prefix, http.FileServerFS(swaggerFiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Стандартный инициализатор открывает petstore, подменяем его своим
+		if path.Base(r.URL.Path) == "swagger-initializer.js" {
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			_, _ = w.Write(swaggerInitializer)
+			return
+		}
+
+		files.ServeHTTP(w, r)
+	})
+}
+
+func jsonContent(schema *Schema, example any) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema, Example: example}}
+}
+
+func pathID(description string) Parameter {
+	return pathParam("id", description)
+}
+
+// pathParam описывает числовой параметр маршрута.
+func pathParam(name, description string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &Schema{Type: "integer", Minimum: ptr(1.0)},
+	}
+}
+
+func header(name string, required bool, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Required: required, Schema: &Schema{Type: "string"}}
+}
+
+// query описывает необязательный query-параметр.
+func query(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// operationID строит идентификатор вида getSongsIdLyrics.
+func operationID(method, p string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(p, func(r rune) bool { return r == '/' || r == '_' || r == '.' }) {
+		part = strings.Trim(part, "{}")
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// modelsPkg - типы из этого пакета выносятся в components/schemas и подключаются через $ref.
+const modelsPkg = "music-lib/internal/models"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// generator строит JSON Schema по Go-типам с учётом тегов json и validate.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema)}
+}
+
+// component регистрирует схему v под именем name и возвращает ссылку на неё.
+func (g *generator) component(name string, v any) *Schema {
+	if _, ok := g.schemas[name]; !ok {
+		g.schemas[name] = g.object(reflect.TypeOf(v))
+	}
+
+	return ref(name)
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schemaOf(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: ptr(0.0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.PkgPath() == modelsPkg && t.Name() != "" {
+			if _, ok := g.schemas[t.Name()]; !ok {
+				// Заглушка до обхода полей разрывает циклы вроде Artist.Songs -> Song.Artist
+				g.schemas[t.Name()] = &Schema{}
+				*g.schemas[t.Name()] = *g.object(t)
+			}
+			return ref(t.Name())
+		}
+		return g.object(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Встроенные структуры без json-имени сериализуются на уровне родителя
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schemaOf(f.Type)
+		if applyValidate(fs, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// applyValidate переносит правила validator в схему и сообщает, обязательно ли поле.
+func applyValidate(s *Schema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "url", "http_url":
+			s.Format = "uri"
+		case "date":
+			s.Description = "Дата в формате DD.MM.YYYY или YYYY-MM-DD"
+		case "oneof":
+			for _, v := range strings.Fields(arg) {
+				s.Enum = append(s.Enum, v)
+			}
+		case "max", "min", "lte", "gte":
+			n, err := strconv.Atoi(arg)
+			if err != nil || s.Ref != "" {
+				continue
+			}
+			switch {
+			case s.Type == "string" && (key == "max" || key == "lte"):
+				s.MaxLength = &n
+			case s.Type == "integer" || s.Type == "number":
+				if key == "max" || key == "lte" {
+					s.Maximum = ptr(float64(n))
+				} else {
+					s.Minimum = ptr(float64(n))
+				}
+			}
+		}
+	}
+
+	return required
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
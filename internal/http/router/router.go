@@ -1,29 +1,88 @@
 package router
 
 import (
+	"context"
+	"music-lib/internal/clients/songinfo"
+	"music-lib/internal/http/handlers/album"
+	"music-lib/internal/http/handlers/apikey"
 	"music-lib/internal/http/handlers/artist"
+	"music-lib/internal/http/handlers/audit"
+	"music-lib/internal/http/handlers/auth"
+	"music-lib/internal/http/handlers/cover"
+	"music-lib/internal/http/handlers/importer"
+	"music-lib/internal/http/handlers/playlist"
+	"music-lib/internal/http/handlers/search"
 	"music-lib/internal/http/handlers/song"
+	"music-lib/internal/http/handlers/trash"
+	"music-lib/internal/http/handlers/user"
+	libauth "music-lib/internal/lib/auth"
+	libcover "music-lib/internal/lib/cover"
+	"music-lib/internal/lib/ratelimit"
+	"music-lib/internal/models"
 	"net/http"
+	"net/netip"
+	"time"
 
 	"log/slog"
+	mwAuth "music-lib/internal/http/middleware/auth"
 	mvLog "music-lib/internal/http/middleware/logger"
-	"music-lib/internal/storage/pgsql"
+	mwRateLimit "music-lib/internal/http/middleware/ratelimit"
+	"music-lib/internal/http/middleware/realip"
+	"music-lib/internal/http/openapi"
+	"music-lib/internal/storage"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
+// Options - необязательные зависимости и настройки маршрутизатора.
+type Options struct {
+	// SongInfo - клиент внешнего API сведений о песнях. nil отключает обогащение новых песен.
+	SongInfo songinfo.Provider
+	// SearchThreshold - порог сходства для нечёткого поиска и подсказок, 0 - значение по умолчанию.
+	SearchThreshold float64
+	// RateLimit - хранилище корзин ограничителя частоты запросов. nil отключает ограничение.
+	RateLimit ratelimit.Store
+	// RateLimits - лимиты адреса, анонимных и аутентифицированных клиентов и входа.
+	RateLimits mwRateLimit.Limits
+	// Covers - загрузка обложек. nil отключает загрузку: эндпоинты обложек отвечают 503.
+	Covers *libcover.Covers
+	// Media раздаёт файлы локального хранилища blob по /media/. nil - файлы раздаёт само хранилище.
+	Media http.Handler
+	// TrustedProxies - прокси, чьим X-Forwarded-For и X-Real-IP можно верить. Пусто -
+	// адрес клиента берётся только из соединения.
+	TrustedProxies []netip.Prefix
+}
+
 // New создаёт новый Router с подключенными хэндлерами.
 // Параметры:
-// - storage: экземпляр вашего pgsql хранилища
+// - store: хранилище с репозиториями (pgsql или memory)
+// - tokens: выпуск и проверка JWT
+// - opts: необязательные зависимости и настройки
 // - logger: ваш логгер для логирования запросов и ошибок
-func New(storage *pgsql.Storage, logger *slog.Logger) http.Handler {
+//
+// Чтение каталога и поиск доступны без входа, изменение и импорт каталога и корзина требуют роли
+// editor, удаление и восстановление артистов, журнал аудита и управление пользователями
+// и API-ключами - admin, изменение плейлистов и выгрузка каталога - любой роли. API-ключ получает
+// роль по своим scope.
+func New(store storage.Storage, tokens *libauth.Tokens, opts Options, logger *slog.Logger) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(realip.New(opts.TrustedProxies))
 	r.Use(middleware.Recoverer)
 	r.Use(mvLog.New(logger))
+	// Лимит адреса стоит до аутентификации, чтобы ограничивать и запросы с неверными
+	// токенами и ключами; лимит клиента - после, ему нужны пользователь или ключ
+	limitIP, limitClient, limitCredentials := pass, pass, pass
+	if opts.RateLimit != nil {
+		limitIP = mwRateLimit.NewIP(opts.RateLimit, "addr", opts.RateLimits.IP, logger)
+		limitClient = mwRateLimit.New(opts.RateLimit, opts.RateLimits, logger)
+		limitCredentials = mwRateLimit.NewIP(opts.RateLimit, "credentials", opts.RateLimits.Credentials, logger)
+	}
+	r.Use(limitIP)
+	r.Use(mwAuth.New(tokens, store.APIKeys(), logger))
+	r.Use(limitClient)
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -33,24 +92,194 @@ func New(storage *pgsql.Storage, logger *slog.Logger) http.Handler {
 		}
 	})
 
-	artistHandlers := artist.NewArtistHandlers(storage, logger)
-	songHandlers := song.NewSongHandlers(storage, logger)
+	r.Get("/openapi.json", openapi.Handler)
+	r.Get("/swagger", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/swagger/", http.StatusMovedPermanently)
+	})
+	r.Handle("/swagger/*", openapi.SwaggerUI("/swagger"))
+
+	if opts.Media != nil {
+		r.Handle("/media/*", http.StripPrefix("/media", opts.Media))
+	}
+
+	artistHandlers := artist.NewArtistHandlers(store.Artists(), store.Export(), logger)
+	songHandlers := song.NewSongHandlers(
+		store.Songs(), store.Artists(), store.SongDetails(), store.Albums(), store.Export(), opts.SongInfo, logger,
+	)
+	albumHandlers := album.NewAlbumHandlers(store.Albums(), store.Artists(), logger)
+	searchHandlers := search.NewSearchHandlers(store.Search(), opts.SearchThreshold, logger)
+	playlistHandlers := playlist.NewPlaylistHandlers(store.Playlists(), logger)
+	authHandlers := auth.NewAuthHandlers(store.Users(), tokens, logger)
+	userHandlers := user.NewUserHandlers(store.Users(), logger)
+	apiKeyHandlers := apikey.NewAPIKeyHandlers(store.APIKeys(), logger)
+	trashHandlers := trash.NewTrashHandlers(store.Trash(), logger)
+	auditHandlers := audit.NewAuditHandlers(store.Audit(), logger)
+	importHandlers := importer.NewImportHandlers(store.Import(), logger)
+	coverHandlers := cover.NewCoverHandlers(store.Artists(), store.Songs(), opts.Covers, logger)
+
+	viewer := mwAuth.Require(models.RoleViewer, logger)
+	editor := mwAuth.Require(models.RoleEditor, logger)
+	admin := mwAuth.Require(models.RoleAdmin, logger)
+
+	r.Route("/auth", func(r chi.Router) {
+		r.With(limitCredentials).Post("/register", authHandlers.Register) // POST /auth/register
+		r.With(limitCredentials).Post("/login", authHandlers.Login)       // POST /auth/login
+		r.With(limitCredentials).Post("/refresh", authHandlers.Refresh)   // POST /auth/refresh
+		r.With(viewer).Get("/me", authHandlers.Me)                        // GET /auth/me
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Use(admin)
+
+		r.Get("/", userHandlers.List)             // GET /users
+		r.Put("/{id}/role", userHandlers.SetRole) // PUT /users/{id}/role
+	})
+
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(admin)
+
+		r.Get("/", apiKeyHandlers.List)               // GET /api-keys
+		r.Post("/", apiKeyHandlers.Create)            // POST /api-keys
+		r.Get("/{id}", apiKeyHandlers.Get)            // GET /api-keys/{id}
+		r.Delete("/{id}", apiKeyHandlers.Delete)      // DELETE /api-keys/{id}
+		r.Post("/{id}/rotate", apiKeyHandlers.Rotate) // POST /api-keys/{id}/rotate
+	})
 
 	r.Route("/artists", func(r chi.Router) {
-		r.Get("/", artistHandlers.List)          // GET /artists
-		r.Post("/", artistHandlers.Create)       // POST /artists
-		r.Get("/{id}", artistHandlers.Get)       // GET /artists/{id}
-		r.Put("/{id}", artistHandlers.Update)    // PUT /artists/{id}
-		r.Delete("/{id}", artistHandlers.Delete) // DELETE /artists/{id}
+		r.Get("/", artistHandlers.List)                 // GET /artists
+		r.Get("/{id}", artistHandlers.Get)              // GET /artists/{id}
+		r.Get("/{id}/songs", songHandlers.ListByArtist) // GET /artists/{id}/songs
+
+		r.Group(func(r chi.Router) {
+			r.Use(editor)
+
+			r.Post("/", artistHandlers.Create)    // POST /artists
+			r.Put("/{id}", artistHandlers.Update) // PUT /artists/{id}
+
+			r.Put("/{id}/cover", coverHandlers.PutArtist)       // PUT /artists/{id}/cover
+			r.Delete("/{id}/cover", coverHandlers.DeleteArtist) // DELETE /artists/{id}/cover
+		})
+
+		// Удаление артиста переносит в корзину и все его песни
+		r.Group(func(r chi.Router) {
+			r.Use(admin)
+
+			r.Delete("/{id}", artistHandlers.Delete)        // DELETE /artists/{id}
+			r.Post("/{id}/restore", artistHandlers.Restore) // POST /artists/{id}/restore
+		})
 	})
 
 	r.Route("/songs", func(r chi.Router) {
-		r.Get("/", songHandlers.List)          // GET /songs
-		r.Post("/", songHandlers.Create)       // POST /songs
-		r.Get("/{id}", songHandlers.Get)       // GET /songs/{id}
-		r.Put("/{id}", songHandlers.Update)    // PUT /songs/{id}
-		r.Delete("/{id}", songHandlers.Delete) // DELETE /songs/{id}
+		r.Get("/", songHandlers.List)                   // GET /songs
+		r.Get("/{id}", songHandlers.Get)                // GET /songs/{id}
+		r.Get("/{id}/lyrics", songHandlers.Lyrics)      // GET /songs/{id}/lyrics
+		r.Get("/{id}/credits", songHandlers.Credits)    // GET /songs/{id}/credits
+		r.Get("/{id}/details", songHandlers.GetDetails) // GET /songs/{id}/details
+
+		r.Get("/{id}/lyrics/revisions", songHandlers.Revisions)         // GET /songs/{id}/lyrics/revisions
+		r.Get("/{id}/lyrics/revisions/diff", songHandlers.RevisionDiff) // GET /songs/{id}/lyrics/revisions/diff
+		r.Get("/{id}/lyrics/revisions/{rev}", songHandlers.Revision)    // GET /songs/{id}/lyrics/revisions/{rev}
+
+		r.Group(func(r chi.Router) {
+			r.Use(editor)
+
+			r.Post("/", songHandlers.Create)       // POST /songs
+			r.Put("/{id}", songHandlers.Update)    // PUT /songs/{id}
+			r.Delete("/{id}", songHandlers.Delete) // DELETE /songs/{id}
+
+			r.Post("/{id}/restore", songHandlers.Restore) // POST /songs/{id}/restore
+
+			r.Put("/{id}/credits", songHandlers.SetCredits) // PUT /songs/{id}/credits
+
+			r.Put("/{id}/cover", coverHandlers.PutSong)       // PUT /songs/{id}/cover
+			r.Delete("/{id}/cover", coverHandlers.DeleteSong) // DELETE /songs/{id}/cover
+
+			r.Put("/{id}/details", songHandlers.PutDetails)       // PUT /songs/{id}/details
+			r.Patch("/{id}/details", songHandlers.PatchDetails)   // PATCH /songs/{id}/details
+			r.Delete("/{id}/details", songHandlers.DeleteDetails) // DELETE /songs/{id}/details
+
+			r.Post("/{id}/lyrics/revisions/{rev}/restore", songHandlers.RestoreRevision) // POST /songs/{id}/lyrics/revisions/{rev}/restore
+		})
 	})
 
+	r.Route("/albums", func(r chi.Router) {
+		r.Get("/", albumHandlers.List)    // GET /albums
+		r.Get("/{id}", albumHandlers.Get) // GET /albums/{id}
+
+		r.Group(func(r chi.Router) {
+			r.Use(editor)
+
+			r.Post("/", albumHandlers.Create)       // POST /albums
+			r.Put("/{id}", albumHandlers.Update)    // PUT /albums/{id}
+			r.Delete("/{id}", albumHandlers.Delete) // DELETE /albums/{id}
+
+			r.Put("/{id}/tracks", albumHandlers.SetTracks) // PUT /albums/{id}/tracks
+		})
+	})
+
+	r.Route("/playlists", func(r chi.Router) {
+		r.Get("/", playlistHandlers.List)    // GET /playlists
+		r.Get("/{id}", playlistHandlers.Get) // GET /playlists/{id}
+
+		r.Group(func(r chi.Router) {
+			r.Use(viewer)
+
+			r.Post("/", playlistHandlers.Create)       // POST /playlists
+			r.Put("/{id}", playlistHandlers.Update)    // PUT /playlists/{id}
+			r.Delete("/{id}", playlistHandlers.Delete) // DELETE /playlists/{id}
+
+			r.Post("/{id}/items", playlistHandlers.AddItem)                 // POST /playlists/{id}/items
+			r.Delete("/{id}/items/{item_id}", playlistHandlers.RemoveItem)  // DELETE /playlists/{id}/items/{item_id}
+			r.Post("/{id}/items/{item_id}/move", playlistHandlers.MoveItem) // POST /playlists/{id}/items/{item_id}/move
+
+			r.Put("/{id}/collaborators/{user_id}", playlistHandlers.AddCollaborator)       // PUT /playlists/{id}/collaborators/{user_id}
+			r.Delete("/{id}/collaborators/{user_id}", playlistHandlers.RemoveCollaborator) // DELETE /playlists/{id}/collaborators/{user_id}
+		})
+	})
+
+	r.With(editor).Get("/trash", trashHandlers.List) // GET /trash
+	r.With(admin).Get("/audit", auditHandlers.List)  // GET /audit
+
+	r.With(editor).Post("/import", importHandlers.Import) // POST /import
+
+	r.Route("/export", func(r chi.Router) {
+		r.Use(viewer)
+		r.Use(deadline(exportTimeout, logger))
+
+		r.Get("/songs", songHandlers.Export)     // GET /export/songs
+		r.Get("/artists", artistHandlers.Export) // GET /export/artists
+	})
+
+	r.Get("/search", searchHandlers.Search)   // GET /search
+	r.Get("/suggest", searchHandlers.Suggest) // GET /suggest
+
 	return r
 }
+
+// exportTimeout ограничивает выгрузку целиком, вместе с чтением из хранилища.
+const exportTimeout = 5 * time.Minute
+
+// deadline ограничивает обработку запроса временем d: контекст отменяет запросы к хранилищу,
+// а срок записи обрывает соединение с клиентом, который слишком медленно читает ответ
+// и держит курсор открытым.
+func deadline(d time.Duration, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d)); err != nil {
+				log.Warn("failed to set write deadline", slog.Any("error", err))
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// pass - middleware, которое ничего не делает.
+func pass(next http.Handler) http.Handler {
+	return next
+}
@@ -0,0 +1,126 @@
+package router_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"music-lib/internal/http/openapi"
+	"music-lib/internal/http/router"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/lib/ratelimit"
+	"music-lib/internal/storage/memory"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mwRateLimit "music-lib/internal/http/middleware/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// undocumented - служебные маршруты, которые не входят в спецификацию.
+var undocumented = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /swagger":      true,
+	"* /swagger/*":      true,
+	"* /media/*":        true,
+}
+
+func newTokens(t *testing.T) *auth.Tokens {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return auth.NewTokens(key, auth.TokenConfig{Issuer: "test", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+}
+
+// TestRoutesDocumented падает, если маршрут добавлен в router.New без описания в openapi, и наоборот.
+func TestRoutesDocumented(t *testing.T) {
+	handler := router.New(memory.New(), newTokens(t), router.Options{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	routes, ok := handler.(chi.Routes)
+	if !ok {
+		t.Fatalf("router.New returned %T, want chi.Routes", handler)
+	}
+
+	registered := make(map[string]bool)
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		key := method + " " + route
+		if strings.HasSuffix(route, "/*") {
+			key = "* " + route
+		}
+		registered[key] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range openapi.Spec().Paths {
+		for method := range *item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		if !documented[route] && !undocumented[route] {
+			t.Errorf("route %s is not documented in internal/http/openapi", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("documented route %s is not registered in router.New", route)
+		}
+	}
+}
+
+// TestRateLimitOrder проверяет, что лимит адреса действует и на запросы с неверным токеном,
+// а вход ограничен отдельной, более строгой корзиной.
+func TestRateLimitOrder(t *testing.T) {
+	handler := router.New(memory.New(), newTokens(t), router.Options{
+		RateLimit: ratelimit.NewMemoryStore(),
+		RateLimits: mwRateLimit.Limits{
+			IP:            ratelimit.Limit{Rate: 0.01, Burst: 3},
+			Anonymous:     ratelimit.Limit{Rate: 0.01, Burst: 3},
+			Authenticated: ratelimit.Limit{Rate: 0.01, Burst: 3},
+			Credentials:   ratelimit.Limit{Rate: 0.01, Burst: 1},
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	serve := func(method, path, remoteAddr string, header map[string]string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"email": "a@example.com", "password": "wrong password"}`))
+		req.RemoteAddr = remoteAddr
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	badToken := map[string]string{"Authorization": "Bearer garbage"}
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if code := serve(http.MethodGet, "/songs", "10.0.0.1:1000", badToken); code != want {
+			t.Errorf("bad token, request %d: status = %d, want %d", i+1, code, want)
+		}
+	}
+
+	for i, want := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if code := serve(http.MethodPost, "/auth/login", "10.0.0.2:1000", nil); code != want {
+			t.Errorf("login, request %d: status = %d, want %d", i+1, code, want)
+		}
+	}
+	if code := serve(http.MethodGet, "/health", "10.0.0.2:1000", nil); code != http.StatusOK {
+		t.Errorf("other route after login limit: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,36 @@
+// Package actor хранит в контексте исполнителя операции: пользователя или API-ключ и роль.
+// Контекст заполняет middleware аутентификации, а читают его и обработчики HTTP,
+// и хранилище, которому исполнитель нужен для журнала аудита и версий текстов.
+package actor
+
+import (
+	"context"
+	"music-lib/internal/models"
+)
+
+// Actor - аутентифицированный пользователь или API-ключ; незаполненный ID равен 0.
+type Actor struct {
+	UserID   uint
+	APIKeyID uint
+	Role     models.Role
+}
+
+type key struct{}
+
+// WithUser сохраняет в контексте ID и роль аутентифицированного пользователя.
+func WithUser(ctx context.Context, id uint, role models.Role) context.Context {
+	return context.WithValue(ctx, key{}, Actor{UserID: id, Role: role})
+}
+
+// WithAPIKey сохраняет в контексте API-ключ и роль, которую дают его scope.
+// Запрос с ключом не связан ни с каким пользователем.
+func WithAPIKey(ctx context.Context, keyID uint, role models.Role) context.Context {
+	return context.WithValue(ctx, key{}, Actor{APIKeyID: keyID, Role: role})
+}
+
+// From возвращает исполнителя из контекста; для анонимного запроса или системной
+// операции - пустое значение.
+func From(ctx context.Context) Actor {
+	a, _ := ctx.Value(key{}).(Actor)
+	return a
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"errors"
+	"music-lib/internal/lib/export"
+	"net/http"
+)
+
+// ErrNotAcceptable - ни один тип из заголовка Accept не поддерживается выгрузкой.
+var ErrNotAcceptable = errors.New("not acceptable, supported: text/csv, application/x-ndjson, " +
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+// ExportFormat возвращает формат выгрузки из параметра format, а без него - по заголовку Accept.
+// Без Accept выгрузка идёт в CSV.
+func ExportFormat(r *http.Request) (export.Format, error) {
+	if v := r.URL.Query().Get("format"); v != "" {
+		format, err := export.ParseFormat(v)
+		if err != nil {
+			return "", BadRequest("invalid format %q, allowed: csv, jsonl, xlsx", v)
+		}
+		return format, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return export.FormatCSV, nil
+	}
+	if format, ok := export.FormatByAccept(accept); ok {
+		return format, nil
+	}
+
+	return "", ErrNotAcceptable
+}
@@ -0,0 +1,140 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"music-lib/internal/lib/date"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+// validate кэширует разобранные теги структур, поэтому создаётся один раз.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// date - строка с датой в одном из форматов date.Layouts
+	if err := v.RegisterValidation("date", func(fl validator.FieldLevel) bool {
+		_, err := date.Parse(fl.Field().String())
+		return err == nil
+	}); err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Error - ошибка во входных данных запроса. Её текст безопасно отдавать клиенту.
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// BadRequest создаёт ошибку входных данных с сообщением для клиента.
+func BadRequest(format string, args ...any) error {
+	return &Error{msg: fmt.Sprintf(format, args...)}
+}
+
+// MaxJSONBytes - наибольший размер JSON-тела запроса. Самое длинное поле - текст песни.
+const MaxJSONBytes = 1 << 20
+
+// ErrBodyTooLarge - тело запроса больше MaxJSONBytes.
+var ErrBodyTooLarge = errors.New("request body is too large")
+
+// DecodeJSON разбирает тело запроса в v и проверяет его validate-тегами.
+// Ошибки валидации возвращаются как validator.ValidationErrors. Тело дальше
+// MaxJSONBytes не читается, а запрос отклоняется с ErrBodyTooLarge.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxJSONBytes)
+
+	err := render.DecodeJSON(r.Body, v)
+	if errors.Is(err, io.EOF) {
+		return BadRequest("empty request")
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrBodyTooLarge
+	}
+	if err != nil {
+		return BadRequest("failed to decode request")
+	}
+
+	return validate.Struct(v)
+}
+
+// ID возвращает числовой параметр маршрута с указанным именем.
+func ID(r *http.Request, name string) (uint, error) {
+	id, err := strconv.ParseUint(chi.URLParam(r, name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, BadRequest("invalid %s", name)
+	}
+
+	return uint(id), nil
+}
+
+// QueryID возвращает необязательный числовой query-параметр; 0 - параметр не задан.
+func QueryID(r *http.Request, name string) (uint, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || id == 0 {
+		return 0, BadRequest("invalid %s", name)
+	}
+
+	return uint(id), nil
+}
+
+// Include разбирает параметр include=a,b,c и проверяет, что все значения из allowed.
+func Include(r *http.Request, allowed ...string) (map[string]bool, error) {
+	include := make(map[string]bool)
+
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		return include, nil
+	}
+
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if !slices.Contains(allowed, v) {
+			return nil, BadRequest("invalid include %q, allowed: %s", v, strings.Join(allowed, ", "))
+		}
+		include[v] = true
+	}
+
+	return include, nil
+}
+
+// Page разбирает параметры limit и offset. Без limit используется defaultLimit.
+func Page(r *http.Request, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	limit = defaultLimit
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return 0, 0, BadRequest("limit must be between 1 and %d", maxLimit)
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, BadRequest("invalid offset")
+		}
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrTooLarge - загружаемый файл больше допустимого.
+var ErrTooLarge = errors.New("file is too large")
+
+// multipartOverhead - запас сверх размера файла на заголовки частей и другие поля формы.
+const multipartOverhead = 64 << 10
+
+// FormFile читает файл из поля field формы multipart/form-data. Файл больше maxBytes
+// даёт ErrTooLarge; тело запроса дальше предела не читается.
+func FormFile(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+	parts, err := r.MultipartReader()
+	if err != nil {
+		return nil, BadRequest("request must be multipart/form-data with a %q file", field)
+	}
+
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, BadRequest("missing %q file", field)
+		}
+		if err != nil {
+			return nil, formError(err)
+		}
+		// Остальные поля пропускаются: NextPart сам дочитывает предыдущую часть
+		if part.FormName() != field {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+		if err != nil {
+			return nil, formError(err)
+		}
+		if int64(len(data)) > maxBytes {
+			return nil, ErrTooLarge
+		}
+		if len(data) == 0 {
+			return nil, BadRequest("file %q is empty", field)
+		}
+
+		return data, nil
+	}
+}
+
+// formError отличает превышение размера тела от испорченной формы.
+func formError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrTooLarge
+	}
+
+	return BadRequest("failed to read multipart form")
+}
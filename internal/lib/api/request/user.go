@@ -0,0 +1,62 @@
+package request
+
+import (
+	"errors"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnauthorized - операция требует аутентифицированного пользователя.
+	ErrUnauthorized = errors.New("authentication required")
+	// ErrForbidden - у пользователя нет прав на операцию.
+	ErrForbidden = errors.New("forbidden")
+	// ErrPreconditionRequired - изменение без заголовка If-Match.
+	ErrPreconditionRequired = errors.New("If-Match header required")
+)
+
+// APIKeyID возвращает ID API-ключа, которым аутентифицирован запрос.
+func APIKeyID(r *http.Request) (uint, bool) {
+	id := actor.From(r.Context()).APIKeyID
+	return id, id != 0
+}
+
+// UserID возвращает ID пользователя, сохранённый middleware аутентификации.
+func UserID(r *http.Request) (uint, bool) {
+	id := actor.From(r.Context()).UserID
+	return id, id != 0
+}
+
+// Role возвращает роль пользователя или API-ключа; для анонимного запроса - пустую строку.
+func Role(r *http.Request) models.Role {
+	return actor.From(r.Context()).Role
+}
+
+// RequireUserID возвращает ID пользователя или ErrUnauthorized для анонимного запроса.
+func RequireUserID(r *http.Request) (uint, error) {
+	id, ok := UserID(r)
+	if !ok {
+		return 0, ErrUnauthorized
+	}
+
+	return id, nil
+}
+
+// IfMatch возвращает версию из заголовка If-Match вида "3" или W/"3". Без заголовка
+// возвращается ErrPreconditionRequired.
+func IfMatch(r *http.Request) (int, error) {
+	v := r.Header.Get("If-Match")
+	if v == "" {
+		return 0, ErrPreconditionRequired
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(v, "W/"), `"`))
+	if err != nil || version < 1 {
+		return 0, BadRequest("invalid If-Match header")
+	}
+
+	return version, nil
+}
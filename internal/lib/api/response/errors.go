@@ -0,0 +1,92 @@
+package response
+
+import (
+	"errors"
+	"log/slog"
+	"music-lib/internal/lib/api/request"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/lib/cover"
+	"music-lib/internal/lib/importer"
+	"music-lib/internal/lib/ratelimit"
+	"music-lib/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+// errorStatuses сопоставляет ошибки хранилища HTTP-статусам. Клиент получает текст
+// самой ошибки из таблицы, а не всей цепочки, чтобы не раскрывать детали БД.
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{storage.ErrArtistNotFound, http.StatusNotFound},
+	{storage.ErrSongNotFound, http.StatusNotFound},
+	{storage.ErrSongDetailNotFound, http.StatusNotFound},
+	{storage.ErrRevisionNotFound, http.StatusNotFound},
+	{storage.ErrAlbumNotFound, http.StatusNotFound},
+	{storage.ErrPlaylistNotFound, http.StatusNotFound},
+	{storage.ErrPlaylistItemNotFound, http.StatusNotFound},
+	{storage.ErrCollaboratorNotFound, http.StatusNotFound},
+	{storage.ErrUserNotFound, http.StatusNotFound},
+	{storage.ErrAPIKeyNotFound, http.StatusNotFound},
+	{cover.ErrNotFound, http.StatusNotFound},
+	{storage.ErrVersionMismatch, http.StatusPreconditionFailed},
+	{storage.ErrArtistExists, http.StatusConflict},
+	{storage.ErrTrackTaken, http.StatusConflict},
+	{storage.ErrArtistDeleted, http.StatusConflict},
+	{storage.ErrAPIKeyExpired, http.StatusConflict},
+	{storage.ErrUserExists, http.StatusConflict},
+	{storage.ErrAlreadyExists, http.StatusConflict},
+	{storage.ErrForeignKey, http.StatusUnprocessableEntity},
+	{storage.ErrConstraint, http.StatusUnprocessableEntity},
+	{cover.ErrInvalidImage, http.StatusUnprocessableEntity},
+	{cover.ErrDimensions, http.StatusUnprocessableEntity},
+	{cover.ErrUnsupportedType, http.StatusUnsupportedMediaType},
+	{request.ErrTooLarge, http.StatusRequestEntityTooLarge},
+	{request.ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+	{importer.ErrTooManyRows, http.StatusRequestEntityTooLarge},
+	{storage.ErrInvalidCursor, http.StatusBadRequest},
+	{request.ErrUnauthorized, http.StatusUnauthorized},
+	{auth.ErrInvalidCredentials, http.StatusUnauthorized},
+	{auth.ErrInvalidToken, http.StatusUnauthorized},
+	{auth.ErrInvalidAPIKey, http.StatusUnauthorized},
+	{request.ErrForbidden, http.StatusForbidden},
+	{request.ErrPreconditionRequired, http.StatusPreconditionRequired},
+	{request.ErrNotAcceptable, http.StatusNotAcceptable},
+	{ratelimit.ErrLimitExceeded, http.StatusTooManyRequests},
+	{cover.ErrDisabled, http.StatusServiceUnavailable},
+}
+
+// FromError возвращает HTTP-статус и тело ответа для ошибки хэндлера.
+func FromError(err error) (int, Response) {
+	var reqErr *request.Error
+	if errors.As(err, &reqErr) {
+		return http.StatusBadRequest, Error(reqErr.Error())
+	}
+
+	var validateErr validator.ValidationErrors
+	if errors.As(err, &validateErr) {
+		return http.StatusBadRequest, ValidationError(validateErr)
+	}
+
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.err) {
+			return e.status, Error(e.err.Error())
+		}
+	}
+
+	return http.StatusInternalServerError, Error("internal error")
+}
+
+// RenderError отправляет ответ с ошибкой. Ошибки сервера логируются, клиентские - нет.
+func RenderError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	status, resp := FromError(err)
+	if status >= http.StatusInternalServerError {
+		log.Error("request failed", slog.Any("error", err))
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, resp)
+}
@@ -0,0 +1,69 @@
+package response
+
+import (
+	"fmt"
+	"log/slog"
+	"music-lib/internal/lib/export"
+	"net/http"
+)
+
+// Export пишет выгрузку в тело ответа. Заголовки и начало файла уходят с первой строкой,
+// поэтому, пока строк не было, на ошибку ещё можно ответить обычным JSON.
+type Export struct {
+	w       http.ResponseWriter
+	format  export.Format
+	name    string
+	columns []string
+	out     export.Writer
+}
+
+// NewExport готовит выгрузку файла name.<format> с указанными колонками.
+func NewExport(w http.ResponseWriter, format export.Format, name string, columns []string) *Export {
+	return &Export{w: w, format: format, name: name, columns: columns}
+}
+
+// WriteRow записывает строку; значения идут в порядке колонок.
+func (e *Export) WriteRow(values ...any) error {
+	if e.out == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	return e.out.WriteRow(values...)
+}
+
+func (e *Export) start() error {
+	h := e.w.Header()
+	h.Set("Content-Type", e.format.ContentType())
+	h.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.name, e.format))
+
+	out, err := export.NewWriter(e.w, e.format, e.name, e.columns)
+	if err != nil {
+		return err
+	}
+	e.out = out
+
+	return nil
+}
+
+// Finish завершает выгрузку с итоговой ошибкой err. Ошибка до первой строки отправляется
+// клиенту как обычно. После первой строки статус уже отправлен, поэтому соединение
+// обрывается: клиент не примет обрезанный файл за целый.
+func (e *Export) Finish(r *http.Request, log *slog.Logger, err error) {
+	if err != nil && e.out == nil {
+		RenderError(e.w, r, log, err)
+		return
+	}
+
+	if err == nil && e.out == nil {
+		err = e.start()
+	}
+	if err == nil {
+		err = e.out.Close()
+	}
+	if err != nil {
+		log.Warn("export interrupted", slog.String("name", e.name), slog.Any("error", err))
+		panic(http.ErrAbortHandler)
+	}
+}
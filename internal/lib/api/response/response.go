@@ -3,6 +3,8 @@ package response
 import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -34,10 +36,22 @@ func ValidationError(errs validator.ValidationErrors) Response {
 
 	for _, err := range errs {
 		switch err.ActualTag() {
-		case "required":
+		case "required", "required_unless":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is required", err.Field()))
+		case "required_without":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s or %s is required", err.Field(), err.Param()))
+		case "excluded_with":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s cannot be used with %s", err.Field(), err.Param()))
 		case "url":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is invalid URL", err.Field()))
+		case "date":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be a date in DD.MM.YYYY or YYYY-MM-DD format", err.Field()))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s exceeds maximum of %s", err.Field(), err.Param()))
+		case "min":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %s", err.Field(), err.Param()))
+		case "oneof":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be one of: %s", err.Field(), err.Param()))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is invalid", err.Field()))
 		}
@@ -48,3 +62,8 @@ func ValidationError(errs validator.ValidationErrors) Response {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+// ETag выставляет заголовок ETag с версией записи; клиент возвращает его в If-Match.
+func ETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
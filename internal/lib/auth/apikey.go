@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix отличает API-ключ от JWT в заголовке Authorization и помогает
+// сканерам секретов находить утёкшие ключи.
+const APIKeyPrefix = "ml_"
+
+// ErrInvalidAPIKey - ключ неизвестен, отозван или истёк.
+var ErrInvalidAPIKey = errors.New("invalid or expired API key")
+
+// NewAPIKey создаёт ключ вида ml_<id>_<secret>. id сохраняется открыто и служит
+// для поиска ключа, в хранилище попадает только хеш всего ключа.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", fmt.Errorf("generate API key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("generate API key: %w", err)
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(id)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// IsAPIKey сообщает, похоже ли значение на API-ключ, а не на JWT.
+func IsAPIKey(v string) bool {
+	return strings.HasPrefix(v, APIKeyPrefix)
+}
+
+// APIKeyPrefixOf возвращает открытую часть ключа для поиска в хранилище. Секрет
+// в base64url сам может содержать "_", поэтому граница ищется от начала.
+func APIKeyPrefixOf(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+
+	i := strings.IndexByte(key[len(APIKeyPrefix):], '_')
+	if i <= 0 {
+		return "", false
+	}
+
+	return key[:len(APIKeyPrefix)+i], true
+}
+
+// HashAPIKey возвращает SHA-256 ключа. Ключ содержит 256 бит случайности,
+// поэтому медленный хеш вроде bcrypt не нужен.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckAPIKey сравнивает ключ с хешем за постоянное время.
+func CheckAPIKey(hash, key string) error {
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(HashAPIKey(key))) != 1 {
+		return ErrInvalidAPIKey
+	}
+
+	return nil
+}
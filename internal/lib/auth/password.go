@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength - bcrypt учитывает только первые 72 байта пароля.
+const MaxPasswordLength = 72
+
+// ErrInvalidCredentials - неизвестный email или неверный пароль. Ответ не уточняет,
+// что именно не так, чтобы по нему нельзя было перебирать зарегистрированные адреса.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// HashPassword возвращает bcrypt-хеш пароля.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword сравнивает пароль с хешем за время, не зависящее от совпадения.
+func CheckPassword(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// dummyHash сравнивается с паролем, когда пользователь не найден, чтобы время ответа
+// не выдавало существование email.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// RejectPassword тратит на проверку столько же времени, сколько CheckPassword, и всегда
+// возвращает ErrInvalidCredentials.
+func RejectPassword(password string) error {
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+	return ErrInvalidCredentials
+}
@@ -0,0 +1,127 @@
+// Package auth выпускает и проверяет JWT и хеширует пароли пользователей.
+package auth
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenType отличает короткоживущий access-токен от refresh-токена,
+// которым можно получить только новую пару.
+type TokenType string
+
+const (
+	TokenAccess  TokenType = "access"
+	TokenRefresh TokenType = "refresh"
+)
+
+// ErrInvalidToken - токен не подписан нашим ключом, истёк или не того типа.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims - содержимое токена. Subject - ID пользователя.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role models.Role `json:"role"`
+	Type TokenType   `json:"typ"`
+}
+
+// UserID возвращает ID пользователя из Subject.
+func (c Claims) UserID() uint {
+	id, _ := strconv.ParseUint(c.Subject, 10, 64)
+	return uint(id)
+}
+
+// Pair - пара токенов, которую получает клиент при входе.
+type Pair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// ExpiresIn - время жизни access-токена в секундах.
+	ExpiresIn int `json:"expires_in"`
+}
+
+type TokenConfig struct {
+	Issuer     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// Tokens подписывает токены ключом Ed25519 (алгоритм EdDSA).
+type Tokens struct {
+	key    ed25519.PrivateKey
+	cfg    TokenConfig
+	parser *jwt.Parser
+}
+
+func NewTokens(key ed25519.PrivateKey, cfg TokenConfig) *Tokens {
+	return &Tokens{
+		key: key,
+		cfg: cfg,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(30*time.Second),
+		),
+	}
+}
+
+// Issue выпускает пару токенов для пользователя.
+func (t *Tokens) Issue(user models.User) (Pair, error) {
+	access, err := t.sign(user, TokenAccess, t.cfg.AccessTTL)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	refresh, err := t.sign(user, TokenRefresh, t.cfg.RefreshTTL)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	return Pair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(t.cfg.AccessTTL.Seconds()),
+	}, nil
+}
+
+// Parse проверяет подпись, срок действия и тип токена.
+func (t *Tokens) Parse(token string, typ TokenType) (Claims, error) {
+	var claims Claims
+	_, err := t.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return t.key.Public(), nil
+	})
+	if err != nil || claims.Type != typ || claims.UserID() == 0 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (t *Tokens) sign(user models.User, typ TokenType, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    t.cfg.Issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role: user.Role,
+		Type: typ,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(t.key)
+	if err != nil {
+		return "", fmt.Errorf("sign %s token: %w", typ, err)
+	}
+
+	return signed, nil
+}
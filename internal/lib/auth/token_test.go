@@ -0,0 +1,156 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"testing"
+	"time"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return key
+}
+
+func newTokens(key ed25519.PrivateKey, accessTTL time.Duration) *auth.Tokens {
+	return auth.NewTokens(key, auth.TokenConfig{Issuer: "test", AccessTTL: accessTTL, RefreshTTL: time.Hour})
+}
+
+func TestTokensRoundTrip(t *testing.T) {
+	tokens := newTokens(newKey(t), time.Minute)
+	user := models.User{ID: 42, Role: models.RoleEditor}
+
+	pair, err := tokens.Issue(user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	claims, err := tokens.Parse(pair.AccessToken, auth.TokenAccess)
+	if err != nil {
+		t.Fatalf("Parse access: %v", err)
+	}
+	if claims.UserID() != user.ID || claims.Role != user.Role {
+		t.Errorf("access claims = user %d role %q, want user %d role %q", claims.UserID(), claims.Role, user.ID, user.Role)
+	}
+
+	if _, err := tokens.Parse(pair.RefreshToken, auth.TokenRefresh); err != nil {
+		t.Errorf("Parse refresh: %v", err)
+	}
+}
+
+func TestTokensReject(t *testing.T) {
+	key := newKey(t)
+	tokens := newTokens(key, time.Minute)
+
+	pair, err := tokens.Issue(models.User{ID: 1, Role: models.RoleViewer})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	expired, err := newTokens(key, -time.Hour).Issue(models.User{ID: 1, Role: models.RoleViewer})
+	if err != nil {
+		t.Fatalf("Issue expired: %v", err)
+	}
+	foreign, err := newTokens(newKey(t), time.Minute).Issue(models.User{ID: 1, Role: models.RoleAdmin})
+	if err != nil {
+		t.Fatalf("Issue foreign: %v", err)
+	}
+	otherIssuer, err := auth.NewTokens(key, auth.TokenConfig{Issuer: "other", AccessTTL: time.Minute}).
+		Issue(models.User{ID: 1, Role: models.RoleAdmin})
+	if err != nil {
+		t.Fatalf("Issue other issuer: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		typ   auth.TokenType
+	}{
+		{name: "refresh used as access", token: pair.RefreshToken, typ: auth.TokenAccess},
+		{name: "access used as refresh", token: pair.AccessToken, typ: auth.TokenRefresh},
+		{name: "expired", token: expired.AccessToken, typ: auth.TokenAccess},
+		{name: "signed by another key", token: foreign.AccessToken, typ: auth.TokenAccess},
+		{name: "another issuer", token: otherIssuer.AccessToken, typ: auth.TokenAccess},
+		{name: "tampered", token: pair.AccessToken[:len(pair.AccessToken)-2] + "AA", typ: auth.TokenAccess},
+		{name: "garbage", token: "not.a.jwt", typ: auth.TokenAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.Parse(tt.token, tt.typ); !errors.Is(err, auth.ErrInvalidToken) {
+				t.Errorf("Parse = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := newKey(t)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	got, err := auth.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey: %v", err)
+	}
+	if !got.Equal(key) {
+		t.Error("ParsePrivateKey returned a different key")
+	}
+
+	if _, err := auth.ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("ParsePrivateKey accepted garbage")
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+
+	if err := auth.CheckPassword(hash, "correct horse"); err != nil {
+		t.Errorf("CheckPassword with right password: %v", err)
+	}
+	if err := auth.CheckPassword(hash, "wrong horse"); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("CheckPassword with wrong password = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	for range 50 {
+		key, prefix, hash, err := auth.NewAPIKey()
+		if err != nil {
+			t.Fatalf("NewAPIKey: %v", err)
+		}
+
+		got, ok := auth.APIKeyPrefixOf(key)
+		if !ok || got != prefix {
+			t.Fatalf("APIKeyPrefixOf(%q) = %q, %v, want %q", key, got, ok, prefix)
+		}
+		if err := auth.CheckAPIKey(hash, key); err != nil {
+			t.Fatalf("CheckAPIKey with right key: %v", err)
+		}
+		if err := auth.CheckAPIKey(hash, key+"x"); !errors.Is(err, auth.ErrInvalidAPIKey) {
+			t.Fatalf("CheckAPIKey with wrong key = %v, want ErrInvalidAPIKey", err)
+		}
+	}
+
+	for _, v := range []string{"", "ml_", "ml__secret", "eyJhbGciOi.jwt.token"} {
+		if _, ok := auth.APIKeyPrefixOf(v); ok {
+			t.Errorf("APIKeyPrefixOf(%q) accepted malformed key", v)
+		}
+	}
+}
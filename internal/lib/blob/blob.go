@@ -0,0 +1,44 @@
+// Package blob хранит файлы приложения, например обложки, по ключам вида covers/<id>/64.jpg.
+//
+// Хранилище выбирается настройкой BLOB_STORE: локальный каталог (FS) или бакет
+// S3-совместимого сервиса (S3). Клиенты получают файлы напрямую по адресу из URL,
+// поэтому ключи не переиспользуются: новый файл всегда пишется под новым ключом.
+package blob
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidKey - ключ пустой, начинается с / или содержит сегменты . и ..
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store - хранилище файлов.
+type Store interface {
+	// Put сохраняет data под ключом key, заменяя прежний файл.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete удаляет файл. Удаление отсутствующего файла ошибкой не считается.
+	Delete(ctx context.Context, key string) error
+	// URL возвращает публичный адрес файла.
+	URL(key string) string
+}
+
+// checkKey проверяет, что ключ - относительный путь без выхода за пределы хранилища.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
+
+// join добавляет ключ к базовому адресу.
+func join(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
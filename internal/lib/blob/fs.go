@@ -0,0 +1,122 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// FS хранит файлы в локальном каталоге. Раздаёт их само приложение через Handler,
+// поэтому этот вариант подходит для одной реплики или общего сетевого каталога.
+type FS struct {
+	dir     string
+	baseURL string
+}
+
+// NewFS создаёт каталог dir, если его нет. baseURL - адрес, по которому смонтирован Handler.
+func NewFS(dir, baseURL string) (*FS, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+
+	return &FS{dir: dir, baseURL: baseURL}, nil
+}
+
+// Put пишет файл во временный в том же каталоге и переименовывает его, чтобы
+// читатели не увидели файл наполовину записанным.
+func (s *FS) Put(_ context.Context, key string, data []byte, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	// CreateTemp создаёт файл с правами 0600, а раздавать его может другой пользователь
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *FS) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *FS) URL(key string) string {
+	return join(s.baseURL, key)
+}
+
+// Handler раздаёт файлы по пути, равному ключу, без списков каталогов. Ключи не
+// переиспользуются, поэтому файлы можно кэшировать бессрочно.
+func (s *FS) Handler() http.Handler {
+	files := http.FileServerFS(filesOnly{os.DirFS(s.dir)})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// При ошибке http.FileServer сам убирает Cache-Control, и 404 не кэшируется
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		files.ServeHTTP(w, r)
+	})
+}
+
+func (s *FS) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// filesOnly скрывает каталоги, чтобы http.FileServer не показывал их содержимое.
+type filesOnly struct {
+	fs.FS
+}
+
+func (f filesOnly) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, fs.ErrNotExist
+	}
+
+	return file, nil
+}
@@ -0,0 +1,238 @@
+// Package cover обрабатывает загруженные обложки: проверяет формат и размеры картинки,
+// делает уменьшенные копии и сохраняет оригинал и копии в хранилище blob.
+//
+// Принимаются JPEG, PNG и WebP. Копии PNG остаются в PNG, чтобы не терять прозрачность,
+// остальные сохраняются в JPEG: кодировщика WebP в стандартной библиотеке нет.
+package cover
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"music-lib/internal/lib/blob"
+	"music-lib/internal/models"
+	"net/http"
+	"slices"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	// ErrDisabled - хранилище обложек не настроено.
+	ErrDisabled = errors.New("cover uploads are disabled")
+	// ErrNotFound - у записи нет обложки.
+	ErrNotFound = errors.New("cover not found")
+	// ErrUnsupportedType - файл не JPEG, PNG или WebP.
+	ErrUnsupportedType = errors.New("unsupported image type, allowed: image/jpeg, image/png, image/webp")
+	// ErrInvalidImage - файл не удалось разобрать как картинку заявленного формата.
+	ErrInvalidImage = errors.New("invalid image")
+	// ErrDimensions - картинка слишком мала или слишком велика в пикселях.
+	ErrDimensions = errors.New("image dimensions are out of range")
+)
+
+// DefaultSizes - размеры копий по умолчанию: для списков, карточек и полноэкранного показа.
+var DefaultSizes = []int{64, 256, 1024}
+
+// Config - ограничения загрузки.
+type Config struct {
+	// MaxBytes - наибольший размер файла. Проверяется при чтении запроса.
+	MaxBytes int64
+	// MaxPixels - наибольшее число пикселей картинки. Защищает от файлов, которые
+	// малы на диске, но занимают гигабайты памяти после распаковки.
+	MaxPixels int
+	// MinSide - наименьшая допустимая сторона в пикселях.
+	MinSide int
+	// Sizes - ограничения большей стороны копий; пустой - DefaultSizes.
+	Sizes []int
+}
+
+// Covers загружает и удаляет обложки.
+type Covers struct {
+	store blob.Store
+	cfg   Config
+}
+
+func New(store blob.Store, cfg Config) *Covers {
+	if len(cfg.Sizes) == 0 {
+		cfg.Sizes = DefaultSizes
+	}
+	cfg.Sizes = slices.Sorted(slices.Values(cfg.Sizes))
+
+	return &Covers{store: store, cfg: cfg}
+}
+
+// MaxBytes возвращает наибольший размер загружаемого файла.
+func (c *Covers) MaxBytes() int64 {
+	return c.cfg.MaxBytes
+}
+
+// format - поддерживаемый формат картинки.
+type format struct {
+	ext          string
+	decode       func(io.Reader) (image.Image, error)
+	decodeConfig func(io.Reader) (image.Config, error)
+}
+
+// formats - поддерживаемые форматы по типу, который определяет http.DetectContentType.
+var formats = map[string]format{
+	"image/jpeg": {"jpg", jpeg.Decode, jpeg.DecodeConfig},
+	"image/png":  {"png", png.Decode, png.DecodeConfig},
+	"image/webp": {"webp", webp.Decode, webp.DecodeConfig},
+}
+
+// Upload проверяет картинку, сохраняет её и копии под новыми ключами и возвращает обложку.
+// Тип определяется по содержимому файла, а не по имени или заголовку Content-Type.
+// Если сохранить не удалось, уже записанные файлы удаляются.
+func (c *Covers) Upload(ctx context.Context, data []byte) (*models.Cover, error) {
+	contentType := http.DetectContentType(data)
+	f, ok := formats[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	// Размеры читаются из заголовка, чтобы не распаковывать картинку, которую всё равно отклоним
+	cfg, err := f.decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if min(cfg.Width, cfg.Height) < max(c.cfg.MinSide, 1) ||
+		(c.cfg.MaxPixels > 0 && cfg.Width*cfg.Height > c.cfg.MaxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d", ErrDimensions, cfg.Width, cfg.Height)
+	}
+
+	img, err := f.decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	prefix := "covers/" + id + "/"
+
+	cover := &models.Cover{Original: models.CoverImage{
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		ContentType: contentType,
+		Key:         prefix + "original." + f.ext,
+	}}
+	files := [][]byte{data}
+
+	for _, size := range c.cfg.Sizes {
+		thumb, thumbType := resize(img, size, contentType == "image/png")
+		bounds := thumb.Bounds()
+		encoded, err := encode(thumb, thumbType)
+		if err != nil {
+			return nil, fmt.Errorf("encode %d: %w", size, err)
+		}
+
+		cover.Thumbnails = append(cover.Thumbnails, models.CoverImage{
+			Size:        size,
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			ContentType: thumbType,
+			Key:         fmt.Sprintf("%s%d.%s", prefix, size, formats[thumbType].ext),
+		})
+		files = append(files, encoded)
+	}
+
+	images := append([]*models.CoverImage{&cover.Original}, pointers(cover.Thumbnails)...)
+	for i, file := range images {
+		if err := c.store.Put(ctx, file.Key, files[i], file.ContentType); err != nil {
+			// Обложка ещё нигде не сохранена, поэтому её файлы можно удалить сразу
+			_ = c.delete(context.WithoutCancel(ctx), cover.Keys()[:i])
+			return nil, fmt.Errorf("store cover: %w", err)
+		}
+		file.URL = c.store.URL(file.Key)
+	}
+
+	return cover, nil
+}
+
+// Delete удаляет файлы обложки. nil ничего не удаляет.
+func (c *Covers) Delete(ctx context.Context, cover *models.Cover) error {
+	if cover == nil {
+		return nil
+	}
+
+	return c.delete(ctx, cover.Keys())
+}
+
+func (c *Covers) delete(ctx context.Context, keys []string) error {
+	var errs []error
+	for _, key := range keys {
+		if err := c.store.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resize вписывает картинку в квадрат size x size с сохранением пропорций. Картинки
+// меньше квадрата не увеличиваются. Возвращает копию и тип, в котором её сохранить.
+func resize(src image.Image, size int, keepPNG bool) (image.Image, string) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if keepPNG {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+		return dst, "image/png"
+	}
+
+	// В JPEG нет прозрачности: прозрачные места WebP становятся белыми, а не чёрными
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	return dst, "image/jpeg"
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	}
+
+	return buf.Bytes(), err
+}
+
+func pointers(images []models.CoverImage) []*models.CoverImage {
+	ptrs := make([]*models.CoverImage, len(images))
+	for i := range images {
+		ptrs[i] = &images[i]
+	}
+
+	return ptrs
+}
+
+// newID возвращает случайный идентификатор обложки. Ключи файлов не переиспользуются,
+// поэтому их можно кэшировать бессрочно.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate cover id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
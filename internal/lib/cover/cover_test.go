@@ -0,0 +1,148 @@
+package cover_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"music-lib/internal/lib/cover"
+	"strings"
+	"testing"
+)
+
+// memStore - хранилище blob в памяти. Put ключа с суффиксом failSuffix завершается ошибкой.
+type memStore struct {
+	files      map[string][]byte
+	failSuffix string
+}
+
+func (s *memStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	if s.failSuffix != "" && strings.HasSuffix(key, s.failSuffix) {
+		return errors.New("disk full")
+	}
+	s.files[key] = data
+	return nil
+}
+
+func (s *memStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memStore) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func encoded(t *testing.T, w, h int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		img.Set(x, x*h/w, color.RGBA{R: 200, A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func pngFile(t *testing.T, w, h int) []byte {
+	return encoded(t, w, h, func(b *bytes.Buffer, img image.Image) error { return png.Encode(b, img) })
+}
+
+func TestUpload(t *testing.T) {
+	store := &memStore{files: make(map[string][]byte)}
+	covers := cover.New(store, cover.Config{MaxPixels: 4_000_000, MinSide: 16})
+	ctx := context.Background()
+
+	t.Run("png keeps format", func(t *testing.T) {
+		c, err := covers.Upload(ctx, pngFile(t, 2000, 1000))
+		if err != nil {
+			t.Fatalf("Upload: %v", err)
+		}
+		if c.Original.ContentType != "image/png" || c.Original.Width != 2000 || c.Original.Height != 1000 {
+			t.Errorf("original = %+v", c.Original)
+		}
+
+		want := [][2]int{{64, 32}, {256, 128}, {1024, 512}}
+		if len(c.Thumbnails) != len(want) {
+			t.Fatalf("got %d thumbnails, want %d", len(c.Thumbnails), len(want))
+		}
+		for i, thumb := range c.Thumbnails {
+			if thumb.Width != want[i][0] || thumb.Height != want[i][1] || thumb.ContentType != "image/png" {
+				t.Errorf("thumbnail %d = %+v, want %dx%d png", i, thumb, want[i][0], want[i][1])
+			}
+			data, ok := store.files[thumb.Key]
+			if !ok {
+				t.Fatalf("thumbnail %s is not stored", thumb.Key)
+			}
+			cfg, err := png.DecodeConfig(bytes.NewReader(data))
+			if err != nil || cfg.Width != thumb.Width || cfg.Height != thumb.Height {
+				t.Errorf("stored thumbnail %s: %dx%d, %v", thumb.Key, cfg.Width, cfg.Height, err)
+			}
+			if thumb.URL != "https://cdn.example.com/"+thumb.Key {
+				t.Errorf("thumbnail URL = %q", thumb.URL)
+			}
+		}
+
+		if err := covers.Delete(ctx, c); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if len(store.files) != 0 {
+			t.Errorf("files left after Delete: %d", len(store.files))
+		}
+	})
+
+	t.Run("small jpeg is not upscaled", func(t *testing.T) {
+		data := encoded(t, 50, 100, func(b *bytes.Buffer, img image.Image) error { return jpeg.Encode(b, img, nil) })
+		c, err := covers.Upload(ctx, data)
+		if err != nil {
+			t.Fatalf("Upload: %v", err)
+		}
+
+		want := [][2]int{{32, 64}, {50, 100}, {50, 100}}
+		for i, thumb := range c.Thumbnails {
+			if thumb.Width != want[i][0] || thumb.Height != want[i][1] || thumb.ContentType != "image/jpeg" {
+				t.Errorf("thumbnail %d = %+v, want %dx%d jpeg", i, thumb, want[i][0], want[i][1])
+			}
+		}
+		_ = covers.Delete(ctx, c)
+	})
+
+	rejected := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"gif", encoded(t, 20, 20, func(b *bytes.Buffer, img image.Image) error { return gif.Encode(b, img, nil) }), cover.ErrUnsupportedType},
+		{"text", []byte("not an image"), cover.ErrUnsupportedType},
+		{"truncated png", pngFile(t, 100, 100)[:60], cover.ErrInvalidImage},
+		{"too small", pngFile(t, 100, 8), cover.ErrDimensions},
+		{"too many pixels", pngFile(t, 4000, 1001), cover.ErrDimensions},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := covers.Upload(ctx, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Upload: err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("failed store removes written files", func(t *testing.T) {
+		failing := &memStore{files: make(map[string][]byte), failSuffix: "/1024.png"}
+		covers := cover.New(failing, cover.Config{})
+
+		if _, err := covers.Upload(ctx, pngFile(t, 300, 300)); err == nil {
+			t.Fatal("Upload succeeded, want store error")
+		}
+		if len(failing.files) != 0 {
+			t.Errorf("files left after failed Upload: %v", len(failing.files))
+		}
+	})
+}
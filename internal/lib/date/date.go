@@ -0,0 +1,24 @@
+package date
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid date, expected DD.MM.YYYY or YYYY-MM-DD")
+
+// Layouts - поддерживаемые форматы дат: как во внешнем API сведений о песнях и ISO 8601.
+var Layouts = []string{"02.01.2006", "2006-01-02"}
+
+// Parse разбирает дату в одном из форматов Layouts.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range Layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrInvalid
+}
@@ -0,0 +1,171 @@
+// Package export записывает табличные выгрузки в CSV, JSON Lines и XLSX построчно,
+// не накапливая файл в памяти.
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format - формат выгрузки.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatXLSX  Format = "xlsx"
+)
+
+// Formats - поддерживаемые форматы; при Accept: */* выбирается первый.
+var Formats = []Format{FormatCSV, FormatJSONL, FormatXLSX}
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// contentTypes - MIME-типы форматов; первый тип формата отдаётся в Content-Type.
+var contentTypes = map[Format][]string{
+	FormatCSV:   {"text/csv"},
+	FormatJSONL: {"application/x-ndjson", "application/jsonl", "application/x-jsonlines"},
+	FormatXLSX:  {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+}
+
+// dateLayout - формат дат в выгрузке, ISO 8601.
+const dateLayout = "2006-01-02"
+
+// ParseFormat разбирает название формата.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatCSV, FormatJSONL, FormatXLSX:
+		return f, nil
+	case "ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownFormat, s)
+	}
+}
+
+// FormatByAccept выбирает формат по заголовку Accept: первый из перечисленных типов,
+// который поддерживается. Веса q не учитываются, q=0 исключает тип.
+func FormatByAccept(accept string) (Format, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		if mediaType == "*/*" {
+			return Formats[0], true
+		}
+		for _, f := range Formats {
+			for _, ct := range contentTypes[f] {
+				if ct == mediaType {
+					return f, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// ContentType возвращает MIME-тип формата.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return contentTypes[f][0]
+}
+
+// Writer записывает строки выгрузки. Значение ячейки - string, bool, целое число,
+// time.Time (пишется как дата) или указатель на них; nil - пустая ячейка.
+type Writer interface {
+	// WriteRow записывает строку; значения идут в порядке колонок.
+	WriteRow(values ...any) error
+	// Close дописывает конец файла. Нижележащий io.Writer не закрывается.
+	Close() error
+}
+
+// NewWriter создаёт Writer и сразу записывает заголовок с колонками. name - имя листа XLSX.
+func NewWriter(w io.Writer, format Format, name string, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := &csvWriter{w: csv.NewWriter(w), record: make([]string, len(columns))}
+		if err := cw.w.Write(columns); err != nil {
+			return nil, err
+		}
+		return cw, nil
+	case FormatJSONL:
+		return newJSONLWriter(w, columns), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, name, columns)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// cell приводит значение ячейки к nil, string, bool, int64 или uint64.
+func cell(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string, bool, int64, uint64:
+		return v
+	case int:
+		return int64(v)
+	case uint:
+		return uint64(v)
+	case time.Time:
+		return v.Format(dateLayout)
+	case *string:
+		return deref(v)
+	case *bool:
+		return deref(v)
+	case *int:
+		return cell(deref(v))
+	case *uint:
+		return cell(deref(v))
+	case *time.Time:
+		return cell(deref(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// deref возвращает значение по указателю или nil.
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (w *csvWriter) WriteRow(values ...any) error {
+	for i, v := range values {
+		switch v := cell(v).(type) {
+		case nil:
+			w.record[i] = ""
+		case string:
+			w.record[i] = v
+		case bool:
+			w.record[i] = strconv.FormatBool(v)
+		default:
+			w.record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,96 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"music-lib/internal/lib/export"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	columns = []string{"id", "name", "is_group", "release_date", "link"}
+	release = time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+)
+
+func write(t *testing.T, format export.Format) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := export.NewWriter(&buf, format, "songs", columns)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteRow(uint(1), "Uprising, \"live\"", true, &release, (*string)(nil)); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.WriteRow(uint(2), "<Starlight>", false, (*time.Time)(nil), "https://example.com"); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestCSV(t *testing.T) {
+	want := "id,name,is_group,release_date,link\n" +
+		"1,\"Uprising, \"\"live\"\"\",true,2006-07-16,\n" +
+		"2,<Starlight>,false,,https://example.com\n"
+	if got := string(write(t, export.FormatCSV)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	want := `{"id":1,"name":"Uprising, \"live\"","is_group":true,"release_date":"2006-07-16","link":null}` + "\n" +
+		`{"id":2,"name":"<Starlight>","is_group":false,"release_date":null,"link":"https://example.com"}` + "\n"
+	if got := string(write(t, export.FormatJSONL)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	data := write(t, export.FormatXLSX)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	f, err := zr.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatalf("open sheet: %v", err)
+	}
+	sheet, _ := io.ReadAll(f)
+
+	for _, want := range []string{
+		`<c r="E1" t="inlineStr"><is><t xml:space="preserve">link</t></is></c>`,
+		`<c r="A2"><v>1</v></c>`,
+		`<c r="C2" t="b"><v>1</v></c>`,
+		`<t xml:space="preserve">&lt;Starlight&gt;</t>`,
+		`<row r="3">`,
+	} {
+		if !strings.Contains(string(sheet), want) {
+			t.Errorf("sheet has no %s:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestFormatByAccept(t *testing.T) {
+	for accept, want := range map[string]export.Format{
+		"application/x-ndjson":             export.FormatJSONL,
+		"application/json, text/csv;q=0.5": export.FormatCSV,
+		"text/csv;q=0, */*":                export.FormatCSV,
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": export.FormatXLSX,
+	} {
+		if got, ok := export.FormatByAccept(accept); !ok || got != want {
+			t.Errorf("FormatByAccept(%q) = %q, %v, want %q", accept, got, ok, want)
+		}
+	}
+	if _, ok := export.FormatByAccept("application/json"); ok {
+		t.Error("FormatByAccept(application/json) matched a format")
+	}
+}
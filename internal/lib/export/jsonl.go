@@ -0,0 +1,59 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// jsonlWriter пишет строку объектом с колонками в исходном порядке; пустые ячейки - null.
+// HTML-символы не экранируются: выгрузка читается программами, а не браузером.
+type jsonlWriter struct {
+	w       io.Writer
+	columns []string
+	buf     bytes.Buffer
+	enc     *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer, columns []string) *jsonlWriter {
+	jw := &jsonlWriter{w: w, columns: columns}
+	jw.enc = json.NewEncoder(&jw.buf)
+	jw.enc.SetEscapeHTML(false)
+
+	return jw
+}
+
+func (w *jsonlWriter) WriteRow(values ...any) error {
+	w.buf.Reset()
+	w.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+		if err := w.encode(w.columns[i]); err != nil {
+			return err
+		}
+		w.buf.WriteByte(':')
+		if err := w.encode(cell(v)); err != nil {
+			return err
+		}
+	}
+	w.buf.WriteString("}\n")
+
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
+
+// encode дописывает v в буфер строки без перевода строки, который добавляет json.Encoder.
+func (w *jsonlWriter) encode(v any) error {
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	w.buf.Truncate(w.buf.Len() - 1)
+
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
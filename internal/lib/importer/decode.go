@@ -0,0 +1,249 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format - формат файла импорта.
+type Format string
+
+const (
+	// FormatCSV - CSV с заголовком из имён колонок Columns в любом порядке.
+	FormatCSV Format = "csv"
+	// FormatJSONL - JSON Lines: по объекту Record на строку, пустые строки пропускаются.
+	FormatJSONL Format = "jsonl"
+)
+
+// Columns - колонки CSV. Обязательна только artist.
+var Columns = []string{"artist", "is_group", "song", "text", "release_date", "link"}
+
+// maxLineSize ограничивает строку JSON Lines и запись CSV: текст песни целиком помещается
+// в одну строку.
+const maxLineSize = 1 << 20
+
+// errRecordTooLong - запись CSV длиннее maxLineSize.
+var errRecordTooLong = errors.New("record too long")
+
+// ParseFormat разбирает название формата.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatCSV, FormatJSONL:
+		return f, nil
+	case "ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("%w: unknown format %q, allowed: csv, jsonl", ErrMalformed, s)
+	}
+}
+
+// FormatByContentType определяет формат по Content-Type запроса.
+func FormatByContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, true
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return FormatJSONL, true
+	default:
+		return "", false
+	}
+}
+
+// FormatByPath определяет формат по расширению файла.
+func FormatByPath(path string) (Format, bool) {
+	f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	return f, err == nil
+}
+
+// decoder читает записи файла по одной. Ошибка разбора отдельной записи возвращается
+// вместе с номером её строки, и чтение можно продолжать; ErrMalformed и ошибки чтения
+// прерывают импорт. В конце файла next возвращает io.EOF.
+type decoder interface {
+	next() (line int, rec Record, err error)
+}
+
+// recordError - ошибка разбора одной записи.
+type recordError struct {
+	msg string
+}
+
+func (e *recordError) Error() string {
+	return e.msg
+}
+
+func newDecoder(r io.Reader, format Format) (decoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVDecoder(r)
+	case FormatJSONL:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlDecoder{sc: sc}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrMalformed, format)
+	}
+}
+
+type csvDecoder struct {
+	r     *csv.Reader
+	limit *recordLimiter
+	// columns - индекс колонки в строке по имени
+	columns map[string]int
+	// line - строка, на которой начинается последняя прочитанная запись
+	line int
+}
+
+func newCSVDecoder(r io.Reader) (*csvDecoder, error) {
+	limit := &recordLimiter{r: r}
+	cr := csv.NewReader(limit)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty file", ErrMalformed)
+	}
+	if errors.Is(err, errRecordTooLong) {
+		return nil, fmt.Errorf("%w: header is longer than %d bytes", ErrMalformed, maxLineSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrMalformed, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(Columns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q, allowed: %s", ErrMalformed, name, strings.Join(Columns, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrMalformed, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["artist"]; !ok {
+		return nil, fmt.Errorf("%w: missing column artist", ErrMalformed)
+	}
+
+	limit.n = 0
+	return &csvDecoder{r: cr, limit: limit, columns: columns, line: 1}, nil
+}
+
+func (d *csvDecoder) next() (int, Record, error) {
+	fields, err := d.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			d.limit.n = 0
+			return parseErr.StartLine, Record{}, &recordError{msg: parseErr.Err.Error()}
+		}
+		if errors.Is(err, errRecordTooLong) {
+			return 0, Record{}, fmt.Errorf("%w: record after line %d is longer than %d bytes", ErrMalformed, d.line, maxLineSize)
+		}
+		return 0, Record{}, err
+	}
+	d.limit.n = 0
+
+	line, _ := d.r.FieldPos(0)
+	d.line = line
+	if len(fields) != len(d.columns) {
+		return line, Record{}, &recordError{msg: fmt.Sprintf("expected %d fields, got %d", len(d.columns), len(fields))}
+	}
+
+	field := func(name string) string {
+		if i, ok := d.columns[name]; ok {
+			return fields[i]
+		}
+		return ""
+	}
+
+	rec := Record{
+		Artist:      field("artist"),
+		Song:        field("song"),
+		Text:        field("text"),
+		ReleaseDate: field("release_date"),
+		Link:        field("link"),
+	}
+	if v := strings.TrimSpace(field("is_group")); v != "" {
+		isGroup, err := strconv.ParseBool(v)
+		if err != nil {
+			return line, Record{}, &recordError{msg: fmt.Sprintf("invalid is_group %q", v)}
+		}
+		rec.IsGroup = &isGroup
+	}
+
+	return line, rec, nil
+}
+
+// recordLimiter ограничивает число байт, прочитанных ради одной записи CSV: csv.Reader
+// не ограничивает длину поля и держит запись целиком в памяти. Декодер обнуляет n после
+// каждой записи. csv.Reader читает с упреждением, поэтому граница приблизительная:
+// запись может превысить maxLineSize на размер его буфера.
+type recordLimiter struct {
+	r io.Reader
+	n int
+}
+
+func (l *recordLimiter) Read(p []byte) (int, error) {
+	if l.n >= maxLineSize {
+		return 0, errRecordTooLong
+	}
+	if len(p) > maxLineSize-l.n {
+		p = p[:maxLineSize-l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n += n
+	return n, err
+}
+
+type jsonlDecoder struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func (d *jsonlDecoder) next() (int, Record, error) {
+	for d.sc.Scan() {
+		d.line++
+
+		raw := bytes.TrimSpace(d.sc.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var rec Record
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			return d.line, Record{}, &recordError{msg: "invalid JSON: " + err.Error()}
+		}
+		if dec.More() {
+			return d.line, Record{}, &recordError{msg: "invalid JSON: more than one value on the line"}
+		}
+
+		return d.line, rec, nil
+	}
+	if err := d.sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return 0, Record{}, fmt.Errorf("%w: line %d is longer than %d bytes", ErrMalformed, d.line+1, maxLineSize)
+		}
+		return 0, Record{}, err
+	}
+
+	return 0, Record{}, io.EOF
+}
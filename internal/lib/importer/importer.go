@@ -0,0 +1,251 @@
+// Package importer загружает в каталог артистов, песни и их детали из CSV и JSON Lines.
+//
+// Каждая запись - артист и, если задано название, его песня с деталями. Артисты ищутся
+// по имени и создаются, если их нет; песни ищутся по артисту и названию. Записи
+// сохраняются пакетами, каждый пакет - в своей транзакции.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"music-lib/internal/lib/date"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultBatchSize - записей в пакете по умолчанию.
+const DefaultBatchSize = 500
+
+// maxNameLength, maxTextLength и maxLinkLength повторяют ограничения API на создание
+// артистов, песен и деталей.
+const (
+	maxNameLength = 255
+	maxTextLength = 20000
+	maxLinkLength = 255
+)
+
+// ErrMalformed - файл нельзя разобрать целиком: неизвестный формат или неверный заголовок CSV.
+var ErrMalformed = errors.New("malformed import file")
+
+// ErrTooManyRows - в файле больше записей, чем разрешено WithMaxRows.
+var ErrTooManyRows = errors.New("too many records in import file")
+
+// Record - запись файла импорта. Детали песни задаются целиком: text и release_date
+// обязательны, если задано хотя бы одно из полей деталей.
+type Record struct {
+	Artist  string `json:"artist"`
+	IsGroup *bool  `json:"is_group"`
+	Song    string `json:"song"`
+	Text    string `json:"text"`
+	// ReleaseDate - дата в формате DD.MM.YYYY или YYYY-MM-DD.
+	ReleaseDate string `json:"release_date"`
+	Link        string `json:"link"`
+}
+
+// Row - итог импорта записи. Status - created, если запись создала песню (или артиста,
+// если песни в записи нет), updated, если что-то изменила, skipped, если всё уже было
+// в каталоге, и failed с Error, если запись не сохранена.
+type Row struct {
+	// Line - номер строки файла, с которой начинается запись.
+	Line   int                  `json:"line"`
+	Status storage.ImportStatus `json:"status"`
+	storage.ImportResult
+	Error string `json:"error,omitempty"`
+}
+
+// Report - итог импорта файла.
+type Report struct {
+	DryRun  bool  `json:"dry_run"`
+	Created int   `json:"created"`
+	Updated int   `json:"updated"`
+	Skipped int   `json:"skipped"`
+	Failed  int   `json:"failed"`
+	Rows    []Row `json:"rows"`
+}
+
+// Importer сохраняет записи через storage.ImportRepository.
+type Importer struct {
+	repo      storage.ImportRepository
+	batchSize int
+	// maxRows - наибольшее число записей в файле, 0 - без ограничения
+	maxRows int
+}
+
+// New создаёт Importer. batchSize меньше 1 заменяется на DefaultBatchSize.
+func New(repo storage.ImportRepository, batchSize int) *Importer {
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Importer{repo: repo, batchSize: batchSize}
+}
+
+// WithMaxRows ограничивает число записей в файле: отчёт хранит строку на каждую запись.
+// n меньше 1 снимает ограничение.
+func (im *Importer) WithMaxRows(n int) *Importer {
+	im.maxRows = max(n, 0)
+	return im
+}
+
+// Run читает файл и сохраняет записи пакетами. Записи с ошибками попадают в отчёт как failed
+// и не мешают остальным. При dryRun записи проверяются и проходят через хранилище,
+// но каждый пакет откатывается; поэтому артист, созданный в одном пакете, в следующем
+// снова будет created.
+//
+// Ошибка возвращается, только если файл не удалось прочитать (ErrMalformed), в нём
+// больше записей, чем разрешено (ErrTooManyRows), или хранилище отказало; пакеты,
+// сохранённые до этого, остаются в каталоге.
+func (im *Importer) Run(ctx context.Context, r io.Reader, format Format, dryRun bool) (Report, error) {
+	dec, err := newDecoder(r, format)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{DryRun: dryRun, Rows: make([]Row, 0)}
+	batch := make([]storage.ImportRecord, 0, im.batchSize)
+	// rows - строки отчёта, ожидающие результата пакета, по порядку batch
+	rows := make([]int, 0, im.batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := im.repo.Import(ctx, batch, dryRun)
+		if err != nil {
+			return err
+		}
+		for i, res := range results {
+			row := &report.Rows[rows[i]]
+			row.ImportResult = res
+			if res.Err != nil {
+				row.Status, row.Error = storage.ImportFailed, res.Err.Error()
+			} else {
+				row.Status = status(res)
+			}
+		}
+
+		batch, rows = batch[:0], rows[:0]
+		return nil
+	}
+
+	for {
+		line, rec, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var recErr *recordError
+		if err != nil && !errors.As(err, &recErr) {
+			return Report{}, err
+		}
+		if im.maxRows > 0 && len(report.Rows) == im.maxRows {
+			return Report{}, fmt.Errorf("%w: more than %d", ErrTooManyRows, im.maxRows)
+		}
+
+		var record storage.ImportRecord
+		if err == nil {
+			record, err = validate(rec)
+		}
+		if err != nil {
+			report.Rows = append(report.Rows, Row{Line: line, Status: storage.ImportFailed, Error: err.Error()})
+			continue
+		}
+
+		report.Rows = append(report.Rows, Row{Line: line})
+		rows = append(rows, len(report.Rows)-1)
+		batch = append(batch, record)
+
+		if len(batch) == im.batchSize {
+			if err := flush(); err != nil {
+				return Report{}, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return Report{}, err
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case storage.ImportCreated:
+			report.Created++
+		case storage.ImportUpdated:
+			report.Updated++
+		case storage.ImportSkipped:
+			report.Skipped++
+		case storage.ImportFailed:
+			report.Failed++
+		}
+	}
+
+	return report, nil
+}
+
+// status сводит статусы сущностей записи в статус строки отчёта.
+func status(res storage.ImportResult) storage.ImportStatus {
+	switch {
+	case res.Song == storage.ImportCreated, res.Song == "" && res.Artist == storage.ImportCreated:
+		return storage.ImportCreated
+	case res.Artist == storage.ImportCreated, res.Artist == storage.ImportUpdated,
+		res.Detail == storage.ImportCreated, res.Detail == storage.ImportUpdated:
+		return storage.ImportUpdated
+	default:
+		return storage.ImportSkipped
+	}
+}
+
+// validate проверяет запись так же, как API проверяет создание артиста, песни и деталей.
+func validate(rec Record) (storage.ImportRecord, error) {
+	record := storage.ImportRecord{
+		Artist:  strings.TrimSpace(rec.Artist),
+		IsGroup: rec.IsGroup,
+		Song:    strings.TrimSpace(rec.Song),
+	}
+
+	switch {
+	case record.Artist == "":
+		return storage.ImportRecord{}, &recordError{msg: "artist is required"}
+	case utf8.RuneCountInString(record.Artist) > maxNameLength:
+		return storage.ImportRecord{}, &recordError{msg: fmt.Sprintf("artist is longer than %d characters", maxNameLength)}
+	case utf8.RuneCountInString(record.Song) > maxNameLength:
+		return storage.ImportRecord{}, &recordError{msg: fmt.Sprintf("song is longer than %d characters", maxNameLength)}
+	}
+
+	if rec.Text == "" && rec.ReleaseDate == "" && rec.Link == "" {
+		return record, nil
+	}
+
+	switch {
+	case record.Song == "":
+		return storage.ImportRecord{}, &recordError{msg: "song is required for text, release_date and link"}
+	case rec.Text == "" || rec.ReleaseDate == "":
+		return storage.ImportRecord{}, &recordError{msg: "text and release_date are required for song details"}
+	case utf8.RuneCountInString(rec.Text) > maxTextLength:
+		return storage.ImportRecord{}, &recordError{msg: fmt.Sprintf("text is longer than %d characters", maxTextLength)}
+	}
+
+	releaseDate, err := date.Parse(rec.ReleaseDate)
+	if err != nil {
+		return storage.ImportRecord{}, &recordError{msg: err.Error()}
+	}
+
+	link := strings.TrimSpace(rec.Link)
+	if link != "" {
+		if u, err := url.Parse(link); err != nil || u.Scheme == "" || u.Host == "" {
+			return storage.ImportRecord{}, &recordError{msg: "invalid link"}
+		}
+		if utf8.RuneCountInString(link) > maxLinkLength {
+			return storage.ImportRecord{}, &recordError{msg: fmt.Sprintf("link is longer than %d characters", maxLinkLength)}
+		}
+	}
+
+	record.Detail = &models.SongDetail{Text: rec.Text, ReleaseDate: releaseDate, Link: link}
+
+	return record, nil
+}
@@ -0,0 +1,128 @@
+package importer_test
+
+import (
+	"context"
+	"errors"
+	"music-lib/internal/lib/importer"
+	"music-lib/internal/storage"
+	"music-lib/internal/storage/memory"
+	"strings"
+	"testing"
+)
+
+const catalogue = `artist,is_group,song,release_date,text,link
+Muse,true,Uprising,07.09.2009,"Paranoia is in bloom",https://example.com/uprising
+Muse,,Starlight,03.09.2006,"Far away",
+Muse,false,,,,
+,,Orphan,,,
+Muse,,Uprising,07.09.2009,"Paranoia is in bloom",https://example.com/uprising
+Muse,,Uprising,07.09.2009,"The PR transmissions will resume",https://example.com/uprising
+Radiohead,yes,Creep,21.09.1992,,
+`
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	report, err := importer.New(store.Import(), 2).Run(ctx, strings.NewReader(catalogue), importer.FormatCSV, false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []struct {
+		line   int
+		status storage.ImportStatus
+	}{
+		{2, storage.ImportCreated},
+		{3, storage.ImportCreated},
+		{4, storage.ImportUpdated},
+		{5, storage.ImportFailed},
+		{6, storage.ImportSkipped},
+		{7, storage.ImportUpdated},
+		{8, storage.ImportFailed},
+	}
+	if len(report.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(report.Rows), len(want), report.Rows)
+	}
+	for i, w := range want {
+		if row := report.Rows[i]; row.Line != w.line || row.Status != w.status {
+			t.Errorf("row %d: line %d status %s (%s), want line %d status %s", i, row.Line, row.Status, row.Error, w.line, w.status)
+		}
+	}
+	if report.Created != 2 || report.Updated != 2 || report.Skipped != 1 || report.Failed != 2 {
+		t.Errorf("totals = %d/%d/%d/%d, want 2/2/1/2", report.Created, report.Updated, report.Skipped, report.Failed)
+	}
+
+	artists, _ := store.Artists().List(ctx)
+	if len(artists) != 1 || artists[0].IsGroup {
+		t.Fatalf("artists = %+v, want one solo artist Muse", artists)
+	}
+
+	songID := report.Rows[0].SongID
+	detail, err := store.SongDetails().GetBySongID(ctx, songID)
+	if err != nil {
+		t.Fatalf("GetBySongID: %v", err)
+	}
+	if detail.Text != "The PR transmissions will resume" {
+		t.Errorf("text = %q, want the last imported text", detail.Text)
+	}
+	revisions, _ := store.SongDetails().Revisions(ctx, songID, 10, 0)
+	if revisions.Total != 2 {
+		t.Errorf("got %d lyrics revisions, want 2", revisions.Total)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	input := `{"artist":"Muse","song":"Uprising"}` + "\n\n" +
+		`{"artist":"Muse","song":"Uprising","release_date":"2009-13-01","text":"x"}` + "\n" +
+		`{"artist":"Muse","tempo":120}` + "\n"
+
+	report, err := importer.New(store.Import(), 0).Run(ctx, strings.NewReader(input), importer.FormatJSONL, true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.DryRun || report.Created != 1 || report.Failed != 2 {
+		t.Errorf("report = %+v, want dry run with 1 created and 2 failed", report)
+	}
+	if report.Rows[1].Line != 3 || report.Rows[2].Line != 4 {
+		t.Errorf("failed lines = %d, %d, want 3, 4", report.Rows[1].Line, report.Rows[2].Line)
+	}
+
+	if artists, _ := store.Artists().List(ctx); len(artists) != 0 {
+		t.Errorf("dry run saved %d artists", len(artists))
+	}
+}
+
+func TestRunMalformed(t *testing.T) {
+	_, err := importer.New(memory.New().Import(), 0).
+		Run(context.Background(), strings.NewReader("name,song\nMuse,Uprising\n"), importer.FormatCSV, false)
+	if !errors.Is(err, importer.ErrMalformed) {
+		t.Errorf("err = %v, want ErrMalformed", err)
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	ctx := context.Background()
+
+	long := "artist,song,text\nMuse,Uprising,\"" + strings.Repeat("la ", 1<<19) + "\"\n"
+	_, err := importer.New(memory.New().Import(), 0).Run(ctx, strings.NewReader(long), importer.FormatCSV, true)
+	if !errors.Is(err, importer.ErrMalformed) {
+		t.Errorf("long record: err = %v, want ErrMalformed", err)
+	}
+
+	rows := "artist\nMuse\nBlur\nOasis\n"
+	_, err = importer.New(memory.New().Import(), 0).WithMaxRows(2).
+		Run(ctx, strings.NewReader(rows), importer.FormatCSV, true)
+	if !errors.Is(err, importer.ErrTooManyRows) {
+		t.Errorf("too many rows: err = %v, want ErrTooManyRows", err)
+	}
+
+	report, err := importer.New(memory.New().Import(), 0).WithMaxRows(3).
+		Run(ctx, strings.NewReader(rows), importer.FormatCSV, true)
+	if err != nil || len(report.Rows) != 3 {
+		t.Errorf("rows at limit: %d rows, err = %v, want 3 rows", len(report.Rows), err)
+	}
+}
@@ -0,0 +1,167 @@
+package lyrics
+
+import "strings"
+
+// DiffOp - действие над строкой при переходе от старой версии текста к новой.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine - строка построчного сравнения двух версий текста.
+type DiffLine struct {
+	Op DiffOp `json:"op"`
+	// OldLine и NewLine - номера строки в старой и новой версии с единицы; 0 - строки там нет.
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Diff построчно сравнивает две версии текста по наибольшей общей подпоследовательности.
+// Удалённые строки идут перед вставленными на их место. Общие начало и конец текстов
+// отбрасываются заранее, поэтому небольшие правки большого текста дёшевы. Середина
+// сравнивается алгоритмом Хиршберга: память линейна по числу строк, время - O(n*m).
+func Diff(oldText, newText string) []DiffLine {
+	a, b := lines(oldText), lines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]DiffLine, 0, max(len(a), len(b)))
+	for i := range prefix {
+		diff = append(diff, DiffLine{Op: DiffEqual, OldLine: i + 1, NewLine: i + 1, Text: a[i]})
+	}
+	d := differ{a: a, b: b}
+	d.ids(prefix, len(a)-suffix, prefix, len(b)-suffix)
+	diff = d.appendMiddle(diff, prefix, len(a)-suffix, prefix, len(b)-suffix)
+	for i := range suffix {
+		oi, ni := len(a)-suffix+i, len(b)-suffix+i
+		diff = append(diff, DiffLine{Op: DiffEqual, OldLine: oi + 1, NewLine: ni + 1, Text: a[oi]})
+	}
+
+	return diff
+}
+
+// differ сравнивает середины текстов. Строки заменяются номерами, чтобы сравнивать
+// числа, а не строки; fwd и rev - строки таблицы длин, общие для всех шагов.
+type differ struct {
+	a, b     []string
+	idA, idB []int
+	fwd, rev []int
+}
+
+// ids нумерует строки a[aLo:aHi] и b[bLo:bHi]: одинаковые строки получают одинаковые номера.
+func (d *differ) ids(aLo, aHi, bLo, bHi int) {
+	seen := make(map[string]int)
+	id := func(line string) int {
+		n, ok := seen[line]
+		if !ok {
+			n = len(seen)
+			seen[line] = n
+		}
+		return n
+	}
+
+	d.idA, d.idB = make([]int, len(d.a)), make([]int, len(d.b))
+	for i := aLo; i < aHi; i++ {
+		d.idA[i] = id(d.a[i])
+	}
+	for j := bLo; j < bHi; j++ {
+		d.idB[j] = id(d.b[j])
+	}
+	d.fwd, d.rev = make([]int, bHi-bLo+1), make([]int, bHi-bLo+1)
+}
+
+// appendMiddle добавляет сравнение a[aLo:aHi] и b[bLo:bHi]. Старая часть делится пополам,
+// а новая - в точке, через которую проходит наибольшая общая подпоследовательность.
+func (d *differ) appendMiddle(diff []DiffLine, aLo, aHi, bLo, bHi int) []DiffLine {
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			diff = append(diff, DiffLine{Op: DiffInsert, NewLine: j + 1, Text: d.b[j]})
+		}
+		return diff
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			diff = append(diff, DiffLine{Op: DiffDelete, OldLine: i + 1, Text: d.a[i]})
+		}
+		return diff
+	case aHi-aLo == 1:
+		j := bLo
+		for j < bHi && d.idB[j] != d.idA[aLo] {
+			j++
+		}
+		if j == bHi {
+			diff = append(diff, DiffLine{Op: DiffDelete, OldLine: aLo + 1, Text: d.a[aLo]})
+			return d.appendMiddle(diff, aHi, aHi, bLo, bHi)
+		}
+		diff = d.appendMiddle(diff, aLo, aLo, bLo, j)
+		diff = append(diff, DiffLine{Op: DiffEqual, OldLine: aLo + 1, NewLine: j + 1, Text: d.a[aLo]})
+		return d.appendMiddle(diff, aHi, aHi, j+1, bHi)
+	}
+
+	mid := (aLo + aHi) / 2
+	n := bHi - bLo
+
+	// fwd[k] - длина общей подпоследовательности a[aLo:mid] и b[bLo:bLo+k]
+	fwd := d.fwd[:n+1]
+	clear(fwd)
+	for i := aLo; i < mid; i++ {
+		diag := 0
+		for k := 1; k <= n; k++ {
+			up := fwd[k]
+			if d.idA[i] == d.idB[bLo+k-1] {
+				fwd[k] = diag + 1
+			} else {
+				fwd[k] = max(up, fwd[k-1])
+			}
+			diag = up
+		}
+	}
+
+	// rev[k] - длина общей подпоследовательности a[mid:aHi] и b[bHi-k:bHi]
+	rev := d.rev[:n+1]
+	clear(rev)
+	for i := aHi - 1; i >= mid; i-- {
+		diag := 0
+		for k := 1; k <= n; k++ {
+			up := rev[k]
+			if d.idA[i] == d.idB[bHi-k] {
+				rev[k] = diag + 1
+			} else {
+				rev[k] = max(up, rev[k-1])
+			}
+			diag = up
+		}
+	}
+
+	// Из равных точек берётся самая ранняя, чтобы удаления шли перед вставками
+	split, best := 0, -1
+	for k := 0; k <= n; k++ {
+		if l := fwd[k] + rev[n-k]; l > best {
+			split, best = k, l
+		}
+	}
+
+	diff = d.appendMiddle(diff, aLo, mid, bLo, bLo+split)
+	return d.appendMiddle(diff, mid, aHi, bLo+split, bHi)
+}
+
+// lines делит текст на строки; у пустого текста строк нет.
+func lines(text string) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,104 @@
+package lyrics
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{name: "equal", old: "a\nb", new: "a\nb", want: []string{" a", " b"}},
+		{name: "from empty", old: "", new: "a\nb", want: []string{"+a", "+b"}},
+		{name: "to empty", old: "a\nb", new: "", want: []string{"-a", "-b"}},
+		{name: "changed line", old: "a\nb\nc", new: "a\nB\nc", want: []string{" a", "-b", "+B", " c"}},
+		{name: "inserted lines", old: "a\nc", new: "a\nb\nb2\nc", want: []string{" a", "+b", "+b2", " c"}},
+		{name: "moved line", old: "a\nb\nc", new: "b\nc\na", want: []string{"-a", " b", " c", "+a"}},
+		{name: "crlf and trailing newline", old: "a\r\nb\r\n", new: "a\nb", want: []string{" a", " b"}},
+	}
+
+	ops := map[DiffOp]string{DiffEqual: " ", DiffInsert: "+", DiffDelete: "-"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Diff(tt.old, tt.new)
+
+			got := make([]string, len(diff))
+			for i, l := range diff {
+				got[i] = ops[l.Op] + l.Text
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+
+			// Номера строк должны восстанавливать обе версии
+			var oldLines, newLines []string
+			for _, l := range diff {
+				if l.OldLine != 0 {
+					if l.OldLine != len(oldLines)+1 {
+						t.Errorf("old_line %d out of order", l.OldLine)
+					}
+					oldLines = append(oldLines, l.Text)
+				}
+				if l.NewLine != 0 {
+					if l.NewLine != len(newLines)+1 {
+						t.Errorf("new_line %d out of order", l.NewLine)
+					}
+					newLines = append(newLines, l.Text)
+				}
+			}
+			if got, want := strings.Join(newLines, "\n"), strings.Join(lines(tt.new), "\n"); got != want {
+				t.Errorf("new version = %q, want %q", got, want)
+			}
+			if got, want := strings.Join(oldLines, "\n"), strings.Join(lines(tt.old), "\n"); got != want {
+				t.Errorf("old version = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestDiffMinimal сверяет число общих строк со случайными текстами с длиной
+// наибольшей общей подпоследовательности, посчитанной полной таблицей.
+func TestDiffMinimal(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	text := func() []string {
+		l := make([]string, rnd.IntN(30))
+		for i := range l {
+			l[i] = string(rune('a' + rnd.IntN(4)))
+		}
+		return l
+	}
+
+	for range 200 {
+		a, b := text(), text()
+
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		equal := 0
+		for _, l := range Diff(strings.Join(a, "\n"), strings.Join(b, "\n")) {
+			if l.Op == DiffEqual {
+				equal++
+			}
+		}
+		if equal != lcs[0][0] {
+			t.Fatalf("Diff(%q, %q): %d equal lines, want %d", a, b, equal, lcs[0][0])
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// verseSeparator - одна или несколько пустых строк (в том числе из пробелов) между куплетами.
+var verseSeparator = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// Verses делит текст на куплеты по пустым строкам. Куплет с индексом i имеет номер i+1.
+func Verses(text string) []string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return []string{}
+	}
+
+	parts := verseSeparator.Split(text, -1)
+	verses := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		verses = append(verses, part)
+	}
+
+	return verses
+}
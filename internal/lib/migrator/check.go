@@ -0,0 +1,384 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Drift - расхождение схемы базы с тем, что ожидает модель GORM.
+type Drift struct {
+	Table   string
+	Message string
+}
+
+func (d Drift) String() string {
+	return d.Table + ": " + d.Message
+}
+
+// Check сравнивает текущую схему базы с моделями GORM и возвращает расхождения:
+// отсутствующие таблицы и колонки, несовпадение NOT NULL и первичного ключа, отсутствующие
+// уникальные ограничения, индексы и внешние ключи из тегов моделей. Лишние таблицы, колонки
+// и индексы не считаются расхождением, если не мешают записи через модель.
+func (m *Migrator) Check(ctx context.Context, models ...any) ([]Drift, error) {
+	db, err := readSchema(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	return compare(db, models)
+}
+
+// dbSchema - схема базы из системного каталога PostgreSQL.
+type dbSchema struct {
+	// columns - колонки по таблицам
+	columns     map[string]map[string]dbColumn
+	indexes     map[string][]dbIndex
+	foreignKeys []dbForeignKey
+}
+
+type dbColumn struct {
+	nullable bool
+	// hasDefault - значение подставляет база: DEFAULT, IDENTITY или вычисляемая колонка
+	hasDefault bool
+}
+
+// dbIndex - индекс, в том числе созданный ограничением PRIMARY KEY или UNIQUE.
+type dbIndex struct {
+	// columns - ключевые колонки по порядку; на месте выражения - пустая строка
+	columns []string
+	unique  bool
+	primary bool
+	// partial - у индекса есть условие WHERE
+	partial bool
+}
+
+type dbForeignKey struct {
+	table      string
+	columns    []string
+	refTable   string
+	refColumns []string
+	// onDelete - действие ON DELETE в том виде, как его пишут в SQL: CASCADE, SET NULL...
+	onDelete string
+}
+
+// onDeleteActions переводит pg_constraint.confdeltype в SQL.
+var onDeleteActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+func readSchema(ctx context.Context, db *sql.DB) (*dbSchema, error) {
+	s := &dbSchema{
+		columns: make(map[string]map[string]dbColumn),
+		indexes: make(map[string][]dbIndex),
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name, column_name, is_nullable = 'YES',
+			column_default IS NOT NULL OR is_identity = 'YES' OR is_generated <> 'NEVER'
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var table, name string
+		var c dbColumn
+		if err := rows.Scan(&table, &name, &c.nullable, &c.hasDefault); err != nil {
+			return err
+		}
+		if s.columns[table] == nil {
+			s.columns[table] = make(map[string]dbColumn)
+		}
+		s.columns[table][name] = c
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+
+	// Колонки INCLUDE идут в indkey после ключевых, их отсекает indnkeyatts
+	rows, err = db.QueryContext(ctx, `
+		SELECT t.relname, i.indisunique, i.indisprimary, i.indpred IS NOT NULL,
+			ARRAY(SELECT COALESCE(a.attname, '')
+				FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, n)
+				LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+				WHERE k.n <= i.indnkeyatts
+				ORDER BY k.n)
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		WHERE t.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())`)
+	if err != nil {
+		return nil, fmt.Errorf("read indexes: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var table string
+		var idx dbIndex
+		if err := rows.Scan(&table, &idx.unique, &idx.primary, &idx.partial, pq.Array(&idx.columns)); err != nil {
+			return err
+		}
+		s.indexes[table] = append(s.indexes[table], idx)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read indexes: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT t.relname, r.relname, c.confdeltype,
+			ARRAY(SELECT a.attname
+				FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, n)
+				JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+				ORDER BY k.n),
+			ARRAY(SELECT a.attname
+				FROM unnest(c.confkey) WITH ORDINALITY AS k(attnum, n)
+				JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum
+				ORDER BY k.n)
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_class r ON r.oid = c.confrelid
+		WHERE c.contype = 'f' AND c.connamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())`)
+	if err != nil {
+		return nil, fmt.Errorf("read foreign keys: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var fk dbForeignKey
+		var action string
+		if err := rows.Scan(&fk.table, &fk.refTable, &action, pq.Array(&fk.columns), pq.Array(&fk.refColumns)); err != nil {
+			return err
+		}
+		fk.onDelete = onDeleteActions[action]
+		s.foreignKeys = append(s.foreignKeys, fk)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read foreign keys: %w", err)
+	}
+
+	return s, nil
+}
+
+// scanRows вызывает scan для каждой строки и закрывает rows.
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// compare сверяет схему базы с моделями. Расхождения идут в порядке моделей и их полей.
+func compare(db *dbSchema, models []any) ([]Drift, error) {
+	var (
+		drifts []Drift
+		cache  sync.Map
+		// Внешние ключи собираются со всех моделей: связь has-many описана в одной модели,
+		// а ключ лежит в таблице другой
+		foreignKeys = make(map[string]*schema.Constraint)
+		fkOrder     []string
+	)
+
+	for _, model := range models {
+		s, err := schema.Parse(model, &cache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, fmt.Errorf("parse model %T: %w", model, err)
+		}
+
+		report := func(format string, args ...any) {
+			drifts = append(drifts, Drift{Table: s.Table, Message: fmt.Sprintf(format, args...)})
+		}
+
+		columns, ok := db.columns[s.Table]
+		if !ok {
+			report("table is missing")
+			continue
+		}
+
+		for _, f := range s.Fields {
+			if f.DBName == "" || f.IgnoreMigration {
+				continue
+			}
+
+			c, ok := columns[f.DBName]
+			switch {
+			case !ok:
+				report("column %s is missing", f.DBName)
+				continue
+			case (f.NotNull || f.PrimaryKey) && c.nullable:
+				report("column %s is nullable, model requires NOT NULL", f.DBName)
+			case !f.NotNull && !f.PrimaryKey && nullable(f) && !c.nullable:
+				report("column %s is NOT NULL, model writes NULL for %s", f.DBName, f.Name)
+			}
+
+			if f.Unique && !db.hasUnique(s.Table, []string{f.DBName}, softDeleted(s)) {
+				report("unique constraint on %s is missing", f.DBName)
+			}
+		}
+
+		// Колонку, которой нет в модели, GORM не заполняет: без DEFAULT вставка упадёт
+		for _, name := range slices.Sorted(maps.Keys(columns)) {
+			if c := columns[name]; s.FieldsByDBName[name] == nil && !c.nullable && !c.hasDefault {
+				report("column %s is NOT NULL without default and is not in the model", name)
+			}
+		}
+
+		if pk := db.primaryKey(s.Table); !sameSet(pk, s.PrimaryFieldDBNames) {
+			report("primary key is (%s), model expects (%s)", strings.Join(pk, ", "), strings.Join(s.PrimaryFieldDBNames, ", "))
+		}
+
+		indexes := s.ParseIndexes()
+		for _, name := range slices.Sorted(maps.Keys(indexes)) {
+			idx := indexes[name]
+			cols := make([]string, len(idx.Fields))
+			for i, f := range idx.Fields {
+				cols[i] = f.DBName
+			}
+
+			if idx.Class == "UNIQUE" {
+				if !db.hasUnique(s.Table, cols, softDeleted(s)) {
+					report("unique index on (%s) is missing", strings.Join(cols, ", "))
+				}
+			} else if !db.hasIndex(s.Table, cols) {
+				report("index on (%s) is missing", strings.Join(cols, ", "))
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(s.Relationships.Relations)) {
+			c := s.Relationships.Relations[name].ParseConstraint()
+			if c == nil || c.Schema == nil || c.ReferenceSchema == nil {
+				continue
+			}
+			key := c.Schema.Table + "(" + strings.Join(dbNames(c.ForeignKeys), ",") + ")->" + c.ReferenceSchema.Table
+			if prev, ok := foreignKeys[key]; !ok {
+				foreignKeys[key] = c
+				fkOrder = append(fkOrder, key)
+			} else if prev.OnDelete == "" {
+				// Действие может быть указано только на одной стороне связи
+				foreignKeys[key] = c
+			}
+		}
+	}
+
+	for _, key := range fkOrder {
+		c := foreignKeys[key]
+		if _, ok := db.columns[c.Schema.Table]; !ok {
+			// Об отсутствующей таблице уже сообщено, если её модель передана в Check
+			continue
+		}
+		drifts = append(drifts, db.checkForeignKey(c)...)
+	}
+
+	return drifts, nil
+}
+
+// checkForeignKey ищет внешний ключ связи. ON UPDATE не сравнивается: первичные ключи
+// в миграциях - GENERATED ALWAYS AS IDENTITY и не меняются.
+func (db *dbSchema) checkForeignKey(c *schema.Constraint) []Drift {
+	cols, refCols := dbNames(c.ForeignKeys), dbNames(c.References)
+	desc := fmt.Sprintf("foreign key (%s) -> %s (%s)", strings.Join(cols, ", "), c.ReferenceSchema.Table, strings.Join(refCols, ", "))
+
+	for _, fk := range db.foreignKeys {
+		if fk.table != c.Schema.Table || fk.refTable != c.ReferenceSchema.Table ||
+			!slices.Equal(fk.columns, cols) || !slices.Equal(fk.refColumns, refCols) {
+			continue
+		}
+
+		want := strings.ToUpper(strings.Join(strings.Fields(c.OnDelete), " "))
+		if want != "" && fk.onDelete != want {
+			return []Drift{{Table: c.Schema.Table, Message: fmt.Sprintf("%s is ON DELETE %s, model expects ON DELETE %s", desc, fk.onDelete, want)}}
+		}
+		return nil
+	}
+
+	return []Drift{{Table: c.Schema.Table, Message: desc + " is missing"}}
+}
+
+// hasUnique сообщает, есть ли уникальный индекс ровно по колонкам cols. Частичный индекс
+// подходит только модели с мягким удалением: уникальность среди неудалённых строк.
+func (db *dbSchema) hasUnique(table string, cols []string, softDeleted bool) bool {
+	for _, idx := range db.indexes[table] {
+		if idx.unique && sameSet(idx.columns, cols) && (!idx.partial || softDeleted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasIndex сообщает, есть ли индекс, который начинается с колонок cols: такой индекс
+// подходит и для поиска по ним одним. Метод доступа и условие WHERE не проверяются.
+func (db *dbSchema) hasIndex(table string, cols []string) bool {
+	for _, idx := range db.indexes[table] {
+		if len(idx.columns) >= len(cols) && slices.Equal(idx.columns[:len(cols)], cols) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (db *dbSchema) primaryKey(table string) []string {
+	for _, idx := range db.indexes[table] {
+		if idx.primary {
+			return idx.columns
+		}
+	}
+
+	return nil
+}
+
+// nullable сообщает, может ли модель записать в поле NULL: указатель или тип,
+// нулевое значение которого driver.Valuer превращает в NULL, как у gorm.DeletedAt.
+func nullable(f *schema.Field) bool {
+	if f.FieldType.Kind() == reflect.Pointer {
+		return true
+	}
+	if v, ok := reflect.Zero(f.FieldType).Interface().(driver.Valuer); ok {
+		value, err := v.Value()
+		return err == nil && value == nil
+	}
+
+	return false
+}
+
+// softDeleted сообщает, удаляет ли модель строки мягко, через gorm.DeletedAt.
+func softDeleted(s *schema.Schema) bool {
+	for _, f := range s.Fields {
+		if f.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dbNames(fields []*schema.Field) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.DBName
+	}
+
+	return names
+}
+
+func sameSet(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
@@ -0,0 +1,63 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// versionLayout - версия создаваемой миграции: время создания в UTC. Такие версии больше
+// порядковых 1, 2, ... первых миграций, поэтому новые миграции всегда идут после них.
+const versionLayout = "20060102150405"
+
+var ErrInvalidName = errors.New("invalid migration name")
+
+// Create создаёт в каталоге dir пустые файлы <версия>_<name>.up.sql и .down.sql
+// и возвращает их пути. Имя приводится к нижнему регистру, пробелы и дефисы заменяются на "_".
+func Create(dir, name string, now time.Time) (up, down string, err error) {
+	name = normalizeName(name)
+	if name == "" {
+		return "", "", fmt.Errorf("%w: use letters, digits and underscores", ErrInvalidName)
+	}
+
+	base := filepath.Join(dir, now.UTC().Format(versionLayout)+"_"+name)
+	up, down = base+".up.sql", base+".down.sql"
+
+	if err := createFile(up); err != nil {
+		return "", "", err
+	}
+	if err := createFile(down); err != nil {
+		os.Remove(up)
+		return "", "", err
+	}
+
+	return up, down, nil
+}
+
+// normalizeName возвращает имя миграции или "", если в нём есть недопустимые символы.
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+
+	for _, r := range name {
+		if r != '_' && (r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return ""
+		}
+	}
+
+	return strings.Trim(name, "_")
+}
+
+// createFile создаёт пустой файл; существующий файл не перезаписывается.
+func createFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("create migration: %w", err)
+	}
+
+	return f.Close()
+}
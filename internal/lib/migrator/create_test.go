@@ -0,0 +1,41 @@
+package migrator_test
+
+import (
+	"errors"
+	"music-lib/internal/lib/migrator"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 18, 15, 4, 5, 0, time.FixedZone("MSK", 3*60*60))
+
+	up, down, err := migrator.Create(dir, " Add-Genres table ", now)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if want := filepath.Join(dir, "20261018120405_add_genres_table.up.sql"); up != want {
+		t.Errorf("up = %s, want %s", up, want)
+	}
+	if want := filepath.Join(dir, "20261018120405_add_genres_table.down.sql"); down != want {
+		t.Errorf("down = %s, want %s", down, want)
+	}
+	for _, path := range []string{up, down} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("stat %s: %v", path, err)
+		}
+	}
+
+	if _, _, err := migrator.Create(dir, "add_genres_table", now); err == nil {
+		t.Error("Create overwrote an existing migration")
+	}
+
+	for _, name := range []string{"", "___", "drop;table", "../escape", "жанры"} {
+		if _, _, err := migrator.Create(dir, name, now); !errors.Is(err, migrator.ErrInvalidName) {
+			t.Errorf("Create(%q) err = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package migrator применяет SQL-миграции из каталога migrations через golang-migrate.
+// Его используют cmd/migrator и интеграционные тесты, чтобы схема строилась одинаково.
+package migrator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// ErrNoChange - все миграции уже применены.
+var ErrNoChange = migrate.ErrNoChange
+
+// ErrDirty - последняя миграция упала на середине; схему нужно поправить вручную и вызвать Force.
+var ErrDirty = errors.New("database is dirty")
+
+// Migration - миграция из каталога.
+type Migration struct {
+	Version uint
+	// Name - имя файла без версии и направления, например song_timestamps
+	Name string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Migrator применяет миграции к базе по адресу postgres://.
+type Migrator struct {
+	m   *migrate.Migrate
+	src source.Driver
+	db  *sql.DB
+}
+
+// New подключается к базе dsn и читает миграции из каталога path.
+// Соединение закрывается методом Close.
+func New(dsn, path string) (*Migrator, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init database driver: %w", err)
+	}
+
+	// Источник открывается отдельно, чтобы по нему можно было перечислить миграции для Plan
+	src, err := source.Open("file://" + path)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("open migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("file", src, "postgres", driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, fmt.Errorf("init migrations: %w", err)
+	}
+
+	return &Migrator{m: m, src: src, db: db}, nil
+}
+
+// Up применяет n следующих миграций, при n == 0 - все непримененные.
+// Если применять нечего, возвращает ErrNoChange.
+func (m *Migrator) Up(n int) error {
+	if n == 0 {
+		return m.m.Up()
+	}
+	return m.m.Steps(n)
+}
+
+// Down откатывает n последних миграций.
+func (m *Migrator) Down(n int) error {
+	return m.m.Steps(-n)
+}
+
+// Goto применяет или откатывает миграции до версии version.
+func (m *Migrator) Goto(version uint) error {
+	return m.m.Migrate(version)
+}
+
+// Force записывает версию без выполнения миграций и снимает признак dirty.
+// Версия -1 означает, что ни одна миграция не применена.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Version возвращает текущую версию схемы; 0 - миграции ещё не применялись.
+func (m *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// Plan возвращает миграции, которые выполнит переход к версии target, в порядке выполнения:
+// при движении вверх - применяемые, вниз - откатываемые. Схема не меняется.
+// Если база в состоянии dirty, возвращает ErrDirty: golang-migrate откажется что-либо выполнять.
+func (m *Migrator) Plan(target uint) ([]Migration, error) {
+	current, dirty, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w: version %d", ErrDirty, current)
+	}
+
+	all, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []Migration
+	if target >= current {
+		for _, mig := range all {
+			if mig.Version > current && mig.Version <= target {
+				plan = append(plan, mig)
+			}
+		}
+		return plan, nil
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		if mig := all[i]; mig.Version <= current && mig.Version > target {
+			plan = append(plan, mig)
+		}
+	}
+
+	return plan, nil
+}
+
+// PlanSteps возвращает миграции, которые выполнят Up(n) при n >= 0 или Down(-n) при n < 0.
+func (m *Migrator) PlanSteps(n int) ([]Migration, error) {
+	if n >= 0 {
+		plan, err := m.Plan(^uint(0))
+		if n > 0 && len(plan) > n {
+			plan = plan[:n]
+		}
+		return plan, err
+	}
+
+	plan, err := m.Plan(0)
+	if len(plan) > -n {
+		plan = plan[:-n]
+	}
+
+	return plan, err
+}
+
+// migrations перечисляет миграции каталога по возрастанию версии.
+func (m *Migrator) migrations() ([]Migration, error) {
+	var all []Migration
+
+	version, err := m.src.First()
+	for err == nil {
+		all = append(all, Migration{Version: version, Name: m.name(version)})
+
+		version, err = m.src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	return all, nil
+}
+
+// name возвращает имя миграции по up-файлу, а если его нет - по down-файлу.
+func (m *Migrator) name(version uint) string {
+	r, name, err := m.src.ReadUp(version)
+	if err != nil {
+		r, name, err = m.src.ReadDown(version)
+	}
+	if err != nil {
+		return ""
+	}
+	r.Close()
+
+	return name
+}
+
+// Close закрывает источник миграций и соединение с базой: драйвер postgres закрывает и *sql.DB.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
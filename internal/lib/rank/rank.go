@@ -0,0 +1,61 @@
+// Package rank строит строковые ключи для упорядочивания элементов списка.
+// Между любыми двумя ключами можно получить новый, поэтому вставка и перемещение
+// элемента меняют только его собственный ключ, а не нумерацию всего списка.
+package rank
+
+import "errors"
+
+// digits - алфавит ключей. Порядок символов совпадает с побайтовым сравнением строк,
+// поэтому в Postgres ключи нужно сравнивать с COLLATE "C".
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var ErrInvalidRange = errors.New("rank: lower bound must be less than upper bound")
+
+// Between возвращает ключ строго между a и b. Пустой a означает начало списка,
+// пустой b - конец. Результат никогда не заканчивается на "0", благодаря этому
+// между двумя выданными ключами всегда найдётся ещё один.
+func Between(a, b string) (string, error) {
+	if b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+
+	var key []byte
+	for n := 0; ; n++ {
+		da, db := digit(a, n, 0), digit(b, n, base)
+
+		if da == db {
+			key = append(key, digits[da])
+			continue
+		}
+		if db-da > 1 {
+			return string(append(key, digits[(da+db)/2])), nil
+		}
+
+		// Соседние цифры: берём цифру a и ищем продолжение больше остатка a
+		key = append(key, digits[da])
+		for n++; ; n++ {
+			da = digit(a, n, 0)
+			if da < base-1 {
+				return string(append(key, digits[(da+base+1)/2])), nil
+			}
+			key = append(key, digits[da])
+		}
+	}
+}
+
+// digit возвращает n-ю цифру ключа или fallback, если ключ короче.
+func digit(key string, n, fallback int) int {
+	if n >= len(key) {
+		return fallback
+	}
+
+	for i := 0; i < base; i++ {
+		if digits[i] == key[n] {
+			return i
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,77 @@
+package rank
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "empty list", a: "", b: ""},
+		{name: "before first", a: "", b: "i"},
+		{name: "after last", a: "i", b: ""},
+		{name: "wide gap", a: "a", b: "z"},
+		{name: "adjacent digits", a: "a", b: "b"},
+		{name: "prefix", a: "a", b: "a1"},
+		{name: "nines", a: "azz", b: "b"},
+		{name: "upper bound with zeros", a: "", b: "001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between(%q, %q): %v", tt.a, tt.b, err)
+			}
+			if got <= tt.a || (tt.b != "" && got >= tt.b) {
+				t.Errorf("Between(%q, %q) = %q, not between bounds", tt.a, tt.b, got)
+			}
+			if strings.HasSuffix(got, "0") {
+				t.Errorf("Between(%q, %q) = %q, ends with 0", tt.a, tt.b, got)
+			}
+		})
+	}
+}
+
+func TestBetweenInvalidRange(t *testing.T) {
+	for _, tt := range [][2]string{{"b", "a"}, {"a", "a"}} {
+		if _, err := Between(tt[0], tt[1]); err == nil {
+			t.Errorf("Between(%q, %q): want error", tt[0], tt[1])
+		}
+	}
+}
+
+// TestBetweenRandomInserts вставляет ключи в случайные места и проверяет, что порядок сохраняется.
+func TestBetweenRandomInserts(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	keys := []string{}
+
+	for range 2000 {
+		i := r.IntN(len(keys) + 1)
+		var a, b string
+		if i > 0 {
+			a = keys[i-1]
+		}
+		if i < len(keys) {
+			b = keys[i]
+		}
+
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", a, b, err)
+		}
+		keys = slices.Insert(keys, i, key)
+	}
+
+	if !slices.IsSorted(keys) {
+		t.Fatal("keys are not sorted")
+	}
+	if len(slices.Compact(slices.Clone(keys))) != len(keys) {
+		t.Fatal("keys are not unique")
+	}
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery - через сколько вызовов Take MemoryStore удаляет наполнившиеся корзины.
+const sweepEvery = 1024
+
+// MemoryStore хранит корзины в памяти процесса. Каждая реплика считает лимит отдельно.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+	calls   int
+	now     func() time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]memoryBucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, allowed := Take(s.buckets[key].Bucket, limit, now)
+	s.buckets[key] = memoryBucket{Bucket: b, limit: limit}
+
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	return NewResult(allowed, b.Tokens, limit), nil
+}
+
+// sweep удаляет корзины, которые уже наполнились: новая корзина ведёт себя так же.
+// Вызывается под блокировкой.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.UpdatedAt) >= b.limit.Window() {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package ratelimit реализует ограничение частоты запросов алгоритмом token bucket.
+//
+// У каждого клиента своя корзина на Limit.Burst токенов, которая пополняется со
+// скоростью Limit.Rate токенов в секунду. Запрос забирает один токен; запрос к пустой
+// корзине отклоняется. Состояние корзин хранит Store: в памяти процесса или в общей
+// базе, чтобы лимит действовал сразу на все реплики.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrLimitExceeded - клиент израсходовал свой лимит запросов.
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// Limit - параметры корзины.
+type Limit struct {
+	// Rate - сколько токенов добавляется в секунду.
+	Rate float64
+	// Burst - ёмкость корзины, то есть сколько запросов можно сделать подряд.
+	Burst int
+}
+
+// Window возвращает время, за которое пустая корзина наполняется полностью.
+func (l Limit) Window() time.Duration {
+	return seconds(float64(l.Burst) / l.Rate)
+}
+
+// Result - итог попытки взять токен.
+type Result struct {
+	Allowed bool
+	Limit   Limit
+	// Remaining - сколько целых токенов осталось в корзине.
+	Remaining int
+	// RetryAfter - через сколько появится токен; 0, если запрос разрешён.
+	RetryAfter time.Duration
+	// Reset - через сколько корзина снова будет полной.
+	Reset time.Duration
+}
+
+// Store хранит корзины клиентов.
+type Store interface {
+	// Take забирает токен из корзины key и сообщает, был ли он там.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket - состояние корзины на момент UpdatedAt.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take пополняет корзину за время с прошлого обращения и забирает из неё токен, если он есть.
+// Нулевая Bucket считается новой полной корзиной.
+func Take(b Bucket, limit Limit, now time.Time) (Bucket, bool) {
+	tokens := float64(limit.Burst)
+	if !b.UpdatedAt.IsZero() {
+		elapsed := max(now.Sub(b.UpdatedAt).Seconds(), 0)
+		tokens = min(float64(limit.Burst), b.Tokens+elapsed*limit.Rate)
+	}
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	return Bucket{Tokens: tokens, UpdatedAt: now}, allowed
+}
+
+// NewResult описывает корзину, в которой после попытки осталось tokens токенов.
+func NewResult(allowed bool, tokens float64, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
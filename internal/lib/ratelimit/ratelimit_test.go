@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Burst: 3}
+	take := func(key string) Result {
+		t.Helper()
+		res, err := s.Take(context.Background(), key, limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return res
+	}
+
+	for i := range limit.Burst {
+		res := take("a")
+		if !res.Allowed {
+			t.Fatalf("request %d denied within burst", i+1)
+		}
+		if want := limit.Burst - i - 1; res.Remaining != want {
+			t.Errorf("request %d: Remaining = %d, want %d", i+1, res.Remaining, want)
+		}
+	}
+
+	res := take("a")
+	if res.Allowed {
+		t.Fatal("request over burst allowed")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 500ms", res.RetryAfter)
+	}
+	if res.Reset != 1500*time.Millisecond {
+		t.Errorf("Reset = %v, want 1.5s", res.Reset)
+	}
+
+	if !take("b").Allowed {
+		t.Error("other key shares the bucket")
+	}
+
+	now = start.Add(500 * time.Millisecond)
+	if !take("a").Allowed {
+		t.Error("bucket is not refilled")
+	}
+	if take("a").Allowed {
+		t.Error("bucket refilled more than rate allows")
+	}
+
+	now = start.Add(time.Hour)
+	if res := take("a"); res.Remaining != limit.Burst-1 {
+		t.Errorf("Remaining after idle = %d, want %d", res.Remaining, limit.Burst-1)
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ReleaseType - тип релиза альбома.
+type ReleaseType string
+
+const (
+	ReleaseLP          ReleaseType = "lp"
+	ReleaseEP          ReleaseType = "ep"
+	ReleaseSingle      ReleaseType = "single"
+	ReleaseCompilation ReleaseType = "compilation"
+)
+
+// Album - релиз артиста. У сборника (ReleaseCompilation) артиста может не быть,
+// а его треки могут принадлежать разным артистам.
+type Album struct {
+	ID          uint        `gorm:"primaryKey"`
+	Title       string      `gorm:"not null" json:"title"`
+	ArtistID    *uint       `gorm:"index" json:"artist_id,omitempty"`
+	Artist      *Artist     `gorm:"foreignKey:ArtistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"artist,omitempty"`
+	ReleaseType ReleaseType `gorm:"type:varchar(16);not null" json:"release_type"`
+	ReleaseDate *time.Time  `gorm:"type:date" json:"release_date,omitempty"`
+	CoverURL    string      `gorm:"type:varchar(1024)" json:"cover_url,omitempty"`
+	// Tracks - песни альбома по порядку дисков и номеров треков.
+	Tracks    []Song    `gorm:"foreignKey:AlbumID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"tracks,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
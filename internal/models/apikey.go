@@ -0,0 +1,97 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scope - право API-ключа. Каждый scope соответствует роли пользователя.
+type Scope string
+
+const (
+	// ScopeSongsRead - чтение каталога, как у роли viewer.
+	ScopeSongsRead Scope = "songs:read"
+	// ScopeSongsWrite - изменение каталога, как у роли editor.
+	ScopeSongsWrite Scope = "songs:write"
+	// ScopeAdmin - всё, включая удаление артистов и управление пользователями и ключами.
+	ScopeAdmin Scope = "admin"
+)
+
+// scopeRoles сопоставляет scope роли с теми же правами.
+var scopeRoles = map[Scope]Role{
+	ScopeSongsRead:  RoleViewer,
+	ScopeSongsWrite: RoleEditor,
+	ScopeAdmin:      RoleAdmin,
+}
+
+// Role возвращает роль, права которой даёт scope; для неизвестного scope - пустую строку.
+func (s Scope) Role() Role {
+	return scopeRoles[s]
+}
+
+// Scopes хранится в одной колонке через пробел, как scope в OAuth 2.0.
+type Scopes []Scope
+
+// Role возвращает наибольшую из ролей, которые дают scopes.
+func (s Scopes) Role() Role {
+	var role Role
+	for _, scope := range s {
+		if r := scope.Role(); r != "" && (role == "" || r.Allows(role)) {
+			role = r
+		}
+	}
+
+	return role
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	parts := make([]string, len(s))
+	for i, scope := range s {
+		parts[i] = string(scope)
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+func (s *Scopes) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("scan scopes: unsupported type %T", src)
+	}
+
+	*s = (*s)[:0]
+	for _, part := range strings.Fields(raw) {
+		*s = append(*s, Scope(part))
+	}
+
+	return nil
+}
+
+// APIKey - долгоживущий ключ машинного клиента. Сам ключ показывается один раз при
+// выпуске; хранятся только его префикс для поиска и SHA-256 хеш.
+type APIKey struct {
+	ID     uint   `gorm:"primaryKey"`
+	Name   string `gorm:"not null" json:"name"`
+	Prefix string `gorm:"unique;not null" json:"prefix"`
+	Hash   string `gorm:"not null" json:"-"`
+	Scopes Scopes `gorm:"type:text;not null" json:"scopes"`
+	// CreatedBy - администратор, выпустивший ключ; nil, если его выпустил другой ключ
+	// или учётная запись удалена.
+	CreatedBy  *uint      `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Expired сообщает, истёк ли срок действия ключа к моменту now.
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
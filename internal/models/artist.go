@@ -1,8 +1,14 @@
 package models
 
+import "gorm.io/gorm"
+
 type Artist struct {
 	ID      uint   `gorm:"primaryKey"`
 	Name    string `gorm:"unique;not null;index" json:"name"`
 	IsGroup bool   `json:"is_group"`
 	Songs   []Song `gorm:"foreignKey:ArtistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"songs,omitempty"`
+	// Cover меняется только через SetCover, Update его не трогает.
+	Cover *Cover `gorm:"serializer:json;type:jsonb" json:"cover,omitempty"`
+	// DeletedAt - время переноса в корзину. GORM скрывает таких артистов из запросов.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
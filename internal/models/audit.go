@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEntity - вид сущности в журнале аудита.
+type AuditEntity string
+
+const (
+	AuditArtist AuditEntity = "artist"
+	AuditSong   AuditEntity = "song"
+	// AuditSongDetail - детали песни; EntityID записи - ID песни.
+	AuditSongDetail AuditEntity = "song_detail"
+)
+
+// AuditEntities - все виды сущностей журнала.
+var AuditEntities = []AuditEntity{AuditArtist, AuditSong, AuditSongDetail}
+
+// AuditAction - операция над сущностью.
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	// AuditPurge - окончательное удаление из корзины.
+	AuditPurge AuditAction = "purge"
+)
+
+// AuditChange - значения поля до и после операции; null - поля не было.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditChanges - изменённые поля по их JSON-именам. Хранится в колонке jsonb.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit changes: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func (c *AuditChanges) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("scan audit changes: unsupported type %T", src)
+	}
+
+	return json.Unmarshal(raw, c)
+}
+
+// AuditEntry - запись журнала аудита. Записи только добавляются и никогда не меняются.
+// Исполнитель - пользователь или API-ключ; у системных операций оба поля пусты.
+type AuditEntry struct {
+	ID        uint         `gorm:"primaryKey"`
+	Entity    AuditEntity  `gorm:"type:varchar(32);not null" json:"entity"`
+	EntityID  uint         `gorm:"not null" json:"entity_id"`
+	Action    AuditAction  `gorm:"type:varchar(16);not null" json:"action"`
+	UserID    *uint        `json:"user_id,omitempty"`
+	APIKeyID  *uint        `json:"api_key_id,omitempty"`
+	RequestID string       `gorm:"type:varchar(64)" json:"request_id,omitempty"`
+	Changes   AuditChanges `gorm:"type:jsonb;not null" json:"changes"`
+	CreatedAt time.Time    `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Cover - обложка артиста или песни: загруженный оригинал и уменьшенные копии.
+// Хранится в колонке cover как JSON; файлы лежат в хранилище blob под ключами Key.
+type Cover struct {
+	Original CoverImage `json:"original"`
+	// Thumbnails - уменьшенные копии по возрастанию Size.
+	Thumbnails []CoverImage `json:"thumbnails"`
+}
+
+// CoverImage - файл обложки.
+type CoverImage struct {
+	// Size - ограничение большей стороны копии в пикселях; у оригинала не задан.
+	// Копия не больше оригинала, поэтому у маленькой картинки стороны могут быть меньше Size.
+	Size        int    `json:"size,omitempty"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"`
+	Key         string `json:"key"`
+}
+
+// Keys возвращает ключи всех файлов обложки.
+func (c *Cover) Keys() []string {
+	keys := []string{c.Original.Key}
+	for _, t := range c.Thumbnails {
+		keys = append(keys, t.Key)
+	}
+
+	return keys
+}
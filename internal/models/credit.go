@@ -0,0 +1,25 @@
+package models
+
+// CreditRole - роль артиста в создании песни.
+type CreditRole string
+
+const (
+	CreditPrimary  CreditRole = "primary"
+	CreditFeatured CreditRole = "featured"
+	CreditComposer CreditRole = "composer"
+	CreditLyricist CreditRole = "lyricist"
+	CreditProducer CreditRole = "producer"
+)
+
+// CreditRoles - все допустимые роли в порядке, принятом для титров.
+var CreditRoles = []CreditRole{CreditPrimary, CreditFeatured, CreditComposer, CreditLyricist, CreditProducer}
+
+// SongCredit - участие артиста в песне. Один артист может иметь в песне несколько ролей.
+// Position задаёт порядок в титрах. Song.ArtistID всегда указан в титрах с ролью primary.
+type SongCredit struct {
+	SongID   uint       `gorm:"primaryKey;autoIncrement:false" json:"song_id"`
+	ArtistID uint       `gorm:"primaryKey;autoIncrement:false;index" json:"artist_id"`
+	Role     CreditRole `gorm:"primaryKey;type:varchar(16)" json:"role"`
+	Position int        `gorm:"not null" json:"position"`
+	Artist   *Artist    `gorm:"foreignKey:ArtistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"artist,omitempty"`
+}
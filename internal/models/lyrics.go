@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LyricsRevision - версия текста песни. Номер версии растёт с единицы в пределах песни.
+// Автор - пользователь или API-ключ; у версий, созданных системой, оба поля пусты.
+type LyricsRevision struct {
+	ID        uint      `gorm:"primaryKey"`
+	SongID    uint      `gorm:"not null;index" json:"song_id"`
+	Number    int       `gorm:"not null" json:"number"`
+	Text      string    `gorm:"type:text;not null" json:"text,omitempty"`
+	UserID    *uint     `json:"user_id,omitempty"`
+	APIKeyID  *uint     `json:"api_key_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// Tables - модели, которым соответствуют таблицы из migrations/. По ним migrator check
+// сверяет схему базы с тем, что ожидает GORM.
+var Tables = []any{
+	&Artist{},
+	&Song{},
+	&SongDetail{},
+	&SongCredit{},
+	&LyricsRevision{},
+	&Album{},
+	&Playlist{},
+	&PlaylistItem{},
+	&PlaylistCollaborator{},
+	&User{},
+	&APIKey{},
+	&AuditEntry{},
+}
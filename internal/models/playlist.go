@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Visibility определяет, кто видит плейлист.
+type Visibility string
+
+const (
+	// VisibilityPrivate - только владелец и соавторы.
+	VisibilityPrivate Visibility = "private"
+	// VisibilityUnlisted - любой, кто знает ID, но плейлиста нет в общем списке.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPublic - все, плейлист есть в общем списке.
+	VisibilityPublic Visibility = "public"
+)
+
+// Playlist - пользовательский плейлист. Version увеличивается при каждом изменении
+// плейлиста или его треков и служит ETag для оптимистичной блокировки.
+type Playlist struct {
+	ID            uint                   `gorm:"primaryKey"`
+	OwnerID       uint                   `gorm:"not null;index" json:"owner_id"`
+	Name          string                 `gorm:"not null" json:"name"`
+	Description   string                 `gorm:"type:text;not null;default:''" json:"description"`
+	Visibility    Visibility             `gorm:"type:varchar(16);not null" json:"visibility"`
+	Version       int                    `gorm:"not null;default:1" json:"version"`
+	Items         []PlaylistItem         `gorm:"foreignKey:PlaylistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"items,omitempty"`
+	Collaborators []PlaylistCollaborator `gorm:"foreignKey:PlaylistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"collaborators,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// PlaylistItem - песня в плейлисте. Одна песня может встречаться несколько раз.
+// Элементы упорядочены по Position (см. пакет rank), при перемещении меняется только
+// Position самого элемента.
+type PlaylistItem struct {
+	ID         uint      `gorm:"primaryKey"`
+	PlaylistID uint      `gorm:"not null;index" json:"playlist_id"`
+	SongID     uint      `gorm:"not null;index" json:"song_id"`
+	Song       *Song     `gorm:"foreignKey:SongID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"song,omitempty"`
+	Position   string    `gorm:"type:varchar(255);not null" json:"position"`
+	AddedBy    uint      `gorm:"not null" json:"added_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PlaylistCollaborator - пользователь, которому владелец разрешил редактировать треки плейлиста.
+type PlaylistCollaborator struct {
+	PlaylistID uint      `gorm:"primaryKey;autoIncrement:false" json:"playlist_id"`
+	UserID     uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -1,13 +1,26 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Song - песня артиста. AlbumID, DiscNumber и TrackNumber заданы вместе или не заданы вовсе.
 type Song struct {
-	ID         uint       `gorm:"primaryKey"`
-	Name       string     `gorm:"not null" json:"name"`
-	ArtistID   uint       `gorm:"not null;index" json:"artist_id"`
-	Artist     Artist     `gorm:"foreignKey:ArtistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"artist,omitempty"`
-	SongDetail SongDetail `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"song_detail,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID          uint         `gorm:"primaryKey"`
+	Name        string       `gorm:"not null" json:"name"`
+	ArtistID    uint         `gorm:"not null;index" json:"artist_id"`
+	Artist      *Artist      `gorm:"foreignKey:ArtistID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"artist,omitempty"`
+	AlbumID     *uint        `gorm:"index" json:"album_id,omitempty"`
+	DiscNumber  *int         `json:"disc_number,omitempty"`
+	TrackNumber *int         `json:"track_number,omitempty"`
+	SongDetail  *SongDetail  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"song_detail,omitempty"`
+	Credits     []SongCredit `gorm:"foreignKey:SongID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"credits,omitempty"`
+	// Cover меняется только через SetCover, Update его не трогает.
+	Cover     *Cover    `gorm:"serializer:json;type:jsonb" json:"cover,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt - время переноса в корзину. GORM скрывает такие песни из запросов.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Role определяет права пользователя. Каждая следующая роль включает права предыдущих.
+type Role string
+
+const (
+	// RoleViewer - чтение каталога и собственные плейлисты.
+	RoleViewer Role = "viewer"
+	// RoleEditor - дополнительно изменение каталога.
+	RoleEditor Role = "editor"
+	// RoleAdmin - дополнительно удаление артистов и управление пользователями.
+	RoleAdmin Role = "admin"
+)
+
+// Roles перечисляет роли по возрастанию прав.
+var Roles = []Role{RoleViewer, RoleEditor, RoleAdmin}
+
+// Allows сообщает, включает ли роль права роли required.
+func (r Role) Allows(required Role) bool {
+	have, need := slices.Index(Roles, r), slices.Index(Roles, required)
+	return have >= 0 && need >= 0 && have >= need
+}
+
+// User - учётная запись. Email хранится в нижнем регистре.
+type User struct {
+	ID           uint      `gorm:"primaryKey"`
+	Email        string    `gorm:"unique;not null" json:"email"`
+	PasswordHash string    `gorm:"not null" json:"-"`
+	Role         Role      `gorm:"type:varchar(16);not null" json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
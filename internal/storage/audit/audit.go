@@ -0,0 +1,106 @@
+// Package audit составляет записи журнала аудита изменений артистов, песен и деталей песен.
+//
+// Wrap передаёт хранилищу Recorder: репозитории каталога добавляют запись с исполнителем
+// и ID запроса из контекста и изменёнными полями в той же транзакции, что и само изменение.
+// Состояние до изменения читается под блокировкой строки, поэтому одновременные изменения
+// одной сущности записываются по очереди, а если запись не удалась, изменение отменяется.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"reflect"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ignoredFields не попадают в журнал: связанные сущности пишутся своими записями,
+// ID уже есть в EntityID, а updated_at меняется при каждом изменении.
+var ignoredFields = []string{"ID", "artist", "songs", "song_detail", "credits", "updated_at"}
+
+// Wrap возвращает хранилище, записывающее изменения каталога в store.Audit().
+func Wrap(store storage.Storage) storage.Storage {
+	return store.WithAudit(Recorder{})
+}
+
+// Recorder реализует storage.Recorder.
+type Recorder struct{}
+
+// Entry составляет запись об операции. Изменение без отличающихся полей не записывается.
+func (Recorder) Entry(
+	ctx context.Context, entity models.AuditEntity, id uint, action models.AuditAction, before, after any,
+) (*models.AuditEntry, error) {
+	changes, err := diff(before, after)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s %d snapshots: %w", entity, id, err)
+	}
+	if len(changes) == 0 && action == models.AuditUpdate {
+		return nil, nil
+	}
+
+	entry := &models.AuditEntry{
+		Entity:    entity,
+		EntityID:  id,
+		Action:    action,
+		RequestID: middleware.GetReqID(ctx),
+		Changes:   changes,
+	}
+	if a := actor.From(ctx); a.UserID != 0 {
+		entry.UserID = &a.UserID
+	} else if a.APIKeyID != 0 {
+		entry.APIKeyID = &a.APIKeyID
+	}
+
+	return entry, nil
+}
+
+// diff сравнивает JSON-представления сущностей и возвращает отличающиеся поля.
+func diff(before, after any) (models.AuditChanges, error) {
+	b, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(models.AuditChanges)
+	for field, v := range b {
+		if av, ok := a[field]; !ok || !reflect.DeepEqual(v, av) {
+			changes[field] = models.AuditChange{Before: v, After: a[field]}
+		}
+	}
+	for field, v := range a {
+		if _, ok := b[field]; !ok {
+			changes[field] = models.AuditChange{After: v}
+		}
+	}
+
+	return changes, nil
+}
+
+// snapshot возвращает поля сущности по их JSON-именам; для nil - пустой набор.
+func snapshot(v any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if v == nil {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for _, f := range ignoredFields {
+		delete(fields, f)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,165 @@
+package audit_test
+
+import (
+	"context"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"music-lib/internal/storage/audit"
+	"music-lib/internal/storage/memory"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestArtistChanges(t *testing.T) {
+	store := audit.Wrap(memory.New())
+	ctx := actor.WithUser(context.Background(), 7, models.RoleAdmin)
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+
+	artists := store.Artists()
+	artist := models.Artist{Name: "Muse"}
+	if err := artists.Create(ctx, &artist); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	artist.Name = "MUSE"
+	if err := artists.Update(ctx, &artist); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	// Повторное сохранение без изменений не попадает в журнал
+	if err := artists.Update(ctx, &artist); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := artists.Delete(ctx, artist.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	page, err := store.Audit().List(ctx, storage.AuditFilter{Entity: models.AuditArtist, EntityID: artist.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	wantActions := []models.AuditAction{models.AuditDelete, models.AuditUpdate, models.AuditCreate}
+	if len(page.Entries) != len(wantActions) {
+		t.Fatalf("got %d entries, want %d", len(page.Entries), len(wantActions))
+	}
+	for i, entry := range page.Entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entry %d: action = %s, want %s", i, entry.Action, wantActions[i])
+		}
+		if entry.UserID == nil || *entry.UserID != 7 {
+			t.Errorf("entry %d: user_id = %v, want 7", i, entry.UserID)
+		}
+		if entry.RequestID != "req-1" {
+			t.Errorf("entry %d: request_id = %q, want req-1", i, entry.RequestID)
+		}
+	}
+
+	update := page.Entries[1].Changes
+	if len(update) != 1 || update["name"].Before != "Muse" || update["name"].After != "MUSE" {
+		t.Errorf("update changes = %v, want only name Muse -> MUSE", update)
+	}
+	if name := page.Entries[0].Changes["name"]; name.Before != "MUSE" || name.After != nil {
+		t.Errorf("delete changes name = %v, want MUSE -> null", name)
+	}
+}
+
+func TestImportChanges(t *testing.T) {
+	store := audit.Wrap(memory.New())
+	ctx := actor.WithUser(context.Background(), 7, models.RoleEditor)
+
+	group := true
+	first := []storage.ImportRecord{{Artist: "Muse", Song: "Uprising", Detail: &models.SongDetail{Text: "Paranoia is in bloom"}}}
+	second := []storage.ImportRecord{{Artist: "Muse", IsGroup: &group, Song: "Uprising", Detail: &models.SongDetail{Text: "They will not force us"}}}
+	for _, records := range [][]storage.ImportRecord{first, second} {
+		if _, err := store.Import().Import(ctx, records, false); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+	}
+
+	artist, err := store.Audit().List(ctx, storage.AuditFilter{Entity: models.AuditArtist, EntityID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(artist.Entries) != 2 || artist.Entries[0].Action != models.AuditUpdate {
+		t.Fatalf("artist entries = %+v, want update and create", artist.Entries)
+	}
+	if changes := artist.Entries[0].Changes; len(changes) != 1 || changes["is_group"].Before != false || changes["is_group"].After != true {
+		t.Errorf("artist update changes = %v, want only is_group false -> true", changes)
+	}
+
+	detail, err := store.Audit().List(ctx, storage.AuditFilter{Entity: models.AuditSongDetail, EntityID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(detail.Entries) != 2 || detail.Entries[0].Action != models.AuditUpdate {
+		t.Fatalf("detail entries = %+v, want update and create", detail.Entries)
+	}
+	if text := detail.Entries[0].Changes["text"]; text.Before != "Paranoia is in bloom" || text.After != "They will not force us" {
+		t.Errorf("detail update text = %v, want the previous and the imported text", text)
+	}
+}
+
+func TestAlbumAndPurgeChanges(t *testing.T) {
+	store := audit.Wrap(memory.New())
+	ctx := actor.WithUser(context.Background(), 7, models.RoleAdmin)
+
+	artist := models.Artist{Name: "Muse"}
+	if err := store.Artists().Create(ctx, &artist); err != nil {
+		t.Fatalf("Create artist: %v", err)
+	}
+	song := models.Song{Name: "Uprising", ArtistID: artist.ID}
+	if err := store.Songs().Create(ctx, &song); err != nil {
+		t.Fatalf("Create song: %v", err)
+	}
+	album := models.Album{Title: "The Resistance", ArtistID: &artist.ID, ReleaseType: models.ReleaseLP}
+	if err := store.Albums().Create(ctx, &album); err != nil {
+		t.Fatalf("Create album: %v", err)
+	}
+
+	if err := store.Albums().SetTracks(ctx, album.ID, []storage.Track{{SongID: song.ID, DiscNumber: 1, TrackNumber: 1}}); err != nil {
+		t.Fatalf("SetTracks: %v", err)
+	}
+	if err := store.Albums().Delete(ctx, album.ID); err != nil {
+		t.Fatalf("Delete album: %v", err)
+	}
+	cover := &models.Cover{Original: models.CoverImage{Key: "songs/uprising.jpg"}}
+	if _, err := store.Songs().SetCover(ctx, song.ID, cover); err != nil {
+		t.Fatalf("SetCover: %v", err)
+	}
+	if err := store.Songs().Delete(ctx, song.ID); err != nil {
+		t.Fatalf("Delete song: %v", err)
+	}
+	res, err := store.Trash().Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(res.Covers) != 1 || res.Covers[0].Original.Key != cover.Original.Key {
+		t.Errorf("purged covers = %+v, want %s", res.Covers, cover.Original.Key)
+	}
+
+	page, err := store.Audit().List(ctx, storage.AuditFilter{Entity: models.AuditSong, EntityID: song.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	wantActions := []models.AuditAction{
+		models.AuditPurge, models.AuditDelete, models.AuditUpdate, models.AuditUpdate, models.AuditUpdate, models.AuditCreate,
+	}
+	if len(page.Entries) != len(wantActions) {
+		t.Fatalf("got %d entries, want %d: %+v", len(page.Entries), len(wantActions), page.Entries)
+	}
+	for i, entry := range page.Entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entry %d: action = %s, want %s", i, entry.Action, wantActions[i])
+		}
+	}
+
+	if track := page.Entries[4].Changes["track_number"]; track.Before != nil || track.After != float64(1) {
+		t.Errorf("set tracks track_number = %v, want null -> 1", track)
+	}
+	if albumID := page.Entries[3].Changes["album_id"]; albumID.Before != float64(album.ID) || albumID.After != nil {
+		t.Errorf("delete album album_id = %v, want %d -> null", albumID, album.ID)
+	}
+}
@@ -0,0 +1,27 @@
+package storage
+
+import "time"
+
+// SongRow - песня для выгрузки вместе с именем артиста, альбомом и деталями.
+// Поля деталей пусты, если деталей у песни нет.
+type SongRow struct {
+	ID          uint
+	Name        string
+	ArtistID    uint
+	ArtistName  string
+	AlbumID     *uint
+	AlbumTitle  *string
+	DiscNumber  *int
+	TrackNumber *int
+	ReleaseDate *time.Time
+	Link        *string
+	Text        *string
+}
+
+// ArtistRow - артист для выгрузки с числом его песен.
+type ArtistRow struct {
+	ID      uint
+	Name    string
+	IsGroup bool
+	Songs   int
+}
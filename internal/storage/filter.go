@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"music-lib/internal/models"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SongSort - поле сортировки списка песен.
+type SongSort string
+
+const (
+	SongSortID          SongSort = "id"
+	SongSortName        SongSort = "name"
+	SongSortReleaseDate SongSort = "release_date"
+)
+
+// SongFilter описывает параметры фильтрации и пагинации списка песен.
+// Нулевые значения полей означают отсутствие соответствующего фильтра.
+type SongFilter struct {
+	Name         string     // подстрока названия песни
+	ArtistID     uint       // точное совпадение artist_id
+	Artist       string     // подстрока имени артиста
+	ReleasedFrom *time.Time // дата релиза не раньше
+	ReleasedTo   *time.Time // дата релиза не позже
+	HasLink      *bool      // наличие ссылки в деталях песни
+	Text         string     // подстрока текста песни
+
+	// CreditArtistID оставляет песни, в титрах которых есть артист, а CreditRole - песни
+	// с указанной ролью в титрах. Заданные вместе, требуют эту роль именно у этого артиста.
+	CreditArtistID uint
+	CreditRole     models.CreditRole
+
+	// WithDetails добавляет к песням SongDetail.
+	WithDetails bool
+	// WithCredits добавляет к песням титры с артистами.
+	WithCredits bool
+
+	// Sort по умолчанию - SongSortID. Песни без даты релиза идут в конце.
+	Sort SongSort
+	Desc bool
+
+	Limit  int
+	Offset int
+	// Cursor - id последней песни предыдущей страницы. Если задан, Offset игнорируется.
+	// Работает только с сортировкой по возрастанию id.
+	Cursor uint
+}
+
+// SongPage - страница результатов поиска песен.
+type SongPage struct {
+	Songs      []models.Song
+	Total      int64
+	NextCursor string
+}
+
+// AlbumFilter описывает параметры фильтрации и пагинации списка альбомов.
+type AlbumFilter struct {
+	ArtistID    uint
+	ReleaseType models.ReleaseType
+
+	Limit  int
+	Offset int
+}
+
+// AlbumPage - страница альбомов по убыванию даты релиза.
+type AlbumPage struct {
+	Albums []models.Album
+	Total  int64
+}
+
+// PlaylistFilter описывает параметры списка плейлистов.
+type PlaylistFilter struct {
+	// MemberID оставляет плейлисты, где пользователь владелец или соавтор.
+	MemberID   uint
+	Visibility models.Visibility
+
+	Limit  int
+	Offset int
+}
+
+// PlaylistPage - страница плейлистов, недавно изменённые первыми.
+type PlaylistPage struct {
+	Playlists []models.Playlist
+	Total     int64
+}
+
+// Placement - место элемента в упорядоченном списке: сразу перед Before или сразу после After.
+// Без обоих полей элемент попадает в конец списка.
+type Placement struct {
+	Before uint
+	After  uint
+}
+
+// UserFilter описывает параметры списка пользователей.
+type UserFilter struct {
+	Role models.Role
+
+	Limit  int
+	Offset int
+}
+
+// UserPage - страница пользователей по возрастанию id.
+type UserPage struct {
+	Users []models.User
+	Total int64
+}
+
+// AuditFilter описывает параметры журнала аудита. EntityID учитывается только вместе с Entity.
+type AuditFilter struct {
+	Entity   models.AuditEntity
+	EntityID uint
+
+	Limit  int
+	Offset int
+}
+
+// AuditPage - страница журнала аудита, последние записи первыми.
+type AuditPage struct {
+	Entries []models.AuditEntry
+	Total   int64
+}
+
+// RevisionPage - страница версий текста песни, последние первыми.
+type RevisionPage struct {
+	Revisions []models.LyricsRevision
+	Total     int64
+}
+
+// EncodeCursor упаковывает id записи в непрозрачный курсор.
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor распаковывает курсор, полученный из EncodeCursor.
+func DecodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"music-lib/internal/models"
+)
+
+// ImportStatus - что импорт сделал с сущностью.
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportUpdated ImportStatus = "updated"
+	ImportSkipped ImportStatus = "skipped"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportRecord - запись импорта: артист и, если задана Song, его песня с деталями.
+type ImportRecord struct {
+	Artist string
+	// IsGroup меняет признак группы у существующего артиста; nil - оставить как есть.
+	IsGroup *bool
+	Song    string
+	// Detail заменяет детали песни целиком; nil - детали не меняются. SongID заполняет хранилище.
+	Detail *models.SongDetail
+}
+
+// ImportResult - итог импорта записи по сущностям. Статус пуст, если сущности в записи нет.
+type ImportResult struct {
+	ArtistID uint         `json:"artist_id,omitempty"`
+	SongID   uint         `json:"song_id,omitempty"`
+	Artist   ImportStatus `json:"artist,omitempty"`
+	Song     ImportStatus `json:"song,omitempty"`
+	Detail   ImportStatus `json:"detail,omitempty"`
+	// Err - ошибка данных записи из числа ошибок пакета storage; изменения записи отменены.
+	Err error `json:"-"`
+}
+
+// recordErrors - ошибки данных записи: такая запись не сохраняется, а остальной пакет сохраняется.
+var recordErrors = []error{ErrArtistExists, ErrTrackTaken, ErrAlreadyExists, ErrForeignKey, ErrConstraint}
+
+// RecordError возвращает ошибку пакета storage, из-за которой не удалось импортировать запись,
+// или nil, если err не связана с данными записи и импорт нужно прервать.
+func RecordError(err error) error {
+	for _, target := range recordErrors {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+
+	return nil
+}
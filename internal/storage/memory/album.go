@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"time"
+)
+
+type albumRepository struct {
+	*Storage
+}
+
+func (r *albumRepository) List(_ context.Context, f storage.AlbumFilter) (storage.AlbumPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]models.Album, 0)
+	for _, album := range r.albums {
+		if f.ArtistID != 0 && (album.ArtistID == nil || *album.ArtistID != f.ArtistID) {
+			continue
+		}
+		if f.ReleaseType != "" && album.ReleaseType != f.ReleaseType {
+			continue
+		}
+		matched = append(matched, album)
+	}
+
+	// Как в pgsql: новые релизы первыми, альбомы без даты в конце
+	slices.SortFunc(matched, func(a, b models.Album) int {
+		switch {
+		case a.ReleaseDate == nil && b.ReleaseDate != nil:
+			return 1
+		case a.ReleaseDate != nil && b.ReleaseDate == nil:
+			return -1
+		case a.ReleaseDate != nil && b.ReleaseDate != nil:
+			if c := b.ReleaseDate.Compare(*a.ReleaseDate); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	from := min(f.Offset, len(matched))
+	return storage.AlbumPage{
+		Albums: matched[from:min(from+f.Limit, len(matched))],
+		Total:  int64(len(matched)),
+	}, nil
+}
+
+func (r *albumRepository) Get(_ context.Context, id uint) (models.Album, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	album, ok := r.albums[id]
+	if !ok {
+		return models.Album{}, storage.ErrAlbumNotFound
+	}
+
+	return album, nil
+}
+
+func (r *albumRepository) GetWithTracks(_ context.Context, id uint) (models.Album, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	album, ok := r.albums[id]
+	if !ok {
+		return models.Album{}, storage.ErrAlbumNotFound
+	}
+	if album.ArtistID != nil {
+		if artist, ok := r.artists[*album.ArtistID]; ok {
+			album.Artist = &artist
+		}
+	}
+
+	album.Tracks = make([]models.Song, 0)
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		if song.AlbumID != nil && *song.AlbumID == id {
+			album.Tracks = append(album.Tracks, song)
+		}
+	}
+	slices.SortStableFunc(album.Tracks, func(a, b models.Song) int {
+		return cmp.Or(cmp.Compare(*a.DiscNumber, *b.DiscNumber), cmp.Compare(*a.TrackNumber, *b.TrackNumber))
+	})
+
+	return album, nil
+}
+
+func (r *albumRepository) Create(_ context.Context, album *models.Album) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkAlbum(album); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	r.lastAlbumID++
+	album.ID = r.lastAlbumID
+	album.CreatedAt, album.UpdatedAt = now, now
+	r.albums[album.ID] = stripAlbum(*album)
+
+	return nil
+}
+
+func (r *albumRepository) Update(_ context.Context, album *models.Album) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.albums[album.ID]
+	if !ok {
+		return storage.ErrAlbumNotFound
+	}
+	if err := r.checkAlbum(album); err != nil {
+		return err
+	}
+
+	album.CreatedAt = stored.CreatedAt
+	album.UpdatedAt = time.Now().UTC()
+	r.albums[album.ID] = stripAlbum(*album)
+
+	return nil
+}
+
+func (r *albumRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.albums[id]; !ok {
+		return storage.ErrAlbumNotFound
+	}
+	for _, songs := range []map[uint]models.Song{r.songs, r.trashedSongs} {
+		for _, song := range songs {
+			if song.AlbumID == nil || *song.AlbumID != id {
+				continue
+			}
+			after := song
+			after.AlbumID, after.DiscNumber, after.TrackNumber = nil, nil, nil
+			if err := r.record(ctx, models.AuditSong, song.ID, models.AuditUpdate, song, after); err != nil {
+				return err
+			}
+		}
+	}
+	r.deleteAlbum(id)
+
+	return nil
+}
+
+func (r *albumRepository) SetTracks(ctx context.Context, albumID uint, tracks []storage.Track) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.albums[albumID]; !ok {
+		return storage.ErrAlbumNotFound
+	}
+
+	// Проверяем всё до изменений, чтобы ошибка не оставила треклист наполовину обновлённым
+	positions := make(map[[2]int]bool, len(tracks))
+	keep := make(map[uint]bool, len(tracks))
+	for _, t := range tracks {
+		if _, ok := r.songs[t.SongID]; !ok {
+			return storage.ErrSongNotFound
+		}
+		if t.DiscNumber < 1 || t.TrackNumber < 1 {
+			return storage.ErrConstraint
+		}
+		pos := [2]int{t.DiscNumber, t.TrackNumber}
+		if positions[pos] {
+			return storage.ErrTrackTaken
+		}
+		positions[pos] = true
+		keep[t.SongID] = true
+	}
+	// Песни в корзине не отвязываются и продолжают занимать свои позиции
+	for _, song := range r.trashedSongs {
+		if song.AlbumID != nil && *song.AlbumID == albumID && positions[[2]int{*song.DiscNumber, *song.TrackNumber}] {
+			return storage.ErrTrackTaken
+		}
+	}
+
+	changed := make(map[uint]models.Song)
+	for id, song := range r.songs {
+		if song.AlbumID != nil && *song.AlbumID == albumID && !keep[id] {
+			song.AlbumID, song.DiscNumber, song.TrackNumber = nil, nil, nil
+			changed[id] = song
+		}
+	}
+	for _, t := range tracks {
+		song := r.songs[t.SongID]
+		song.AlbumID = &albumID
+		song.DiscNumber, song.TrackNumber = &t.DiscNumber, &t.TrackNumber
+		changed[t.SongID] = song
+	}
+
+	for _, song := range sortedByID(changed, func(s models.Song) uint { return s.ID }) {
+		if err := r.record(ctx, models.AuditSong, song.ID, models.AuditUpdate, r.songs[song.ID], song); err != nil {
+			return err
+		}
+	}
+	maps.Copy(r.songs, changed)
+
+	return nil
+}
+
+// checkAlbum повторяет ограничения таблицы albums. Вызывается под блокировкой.
+func (r *albumRepository) checkAlbum(album *models.Album) error {
+	if album.ArtistID == nil {
+		if album.ReleaseType != models.ReleaseCompilation {
+			return storage.ErrConstraint
+		}
+		return nil
+	}
+	if _, ok := r.artists[*album.ArtistID]; !ok {
+		return storage.ErrForeignKey
+	}
+
+	return nil
+}
+
+// deleteAlbum удаляет альбом и отвязывает от него песни, в том числе песни в корзине.
+// Вызывается под блокировкой.
+func (s *Storage) deleteAlbum(id uint) {
+	delete(s.albums, id)
+	for _, songs := range []map[uint]models.Song{s.songs, s.trashedSongs} {
+		for songID, song := range songs {
+			if song.AlbumID != nil && *song.AlbumID == id {
+				song.AlbumID, song.DiscNumber, song.TrackNumber = nil, nil, nil
+				songs[songID] = song
+			}
+		}
+	}
+}
+
+// trackTaken проверяет уникальность позиции в альбоме. Песни в корзине сохраняют
+// свои позиции, как и в pgsql. Вызывается под блокировкой.
+func (s *Storage) trackTaken(albumID uint, disc, track int, exceptSongID uint) bool {
+	for _, songs := range []map[uint]models.Song{s.songs, s.trashedSongs} {
+		for id, song := range songs {
+			if id != exceptSongID && song.AlbumID != nil && *song.AlbumID == albumID &&
+				*song.DiscNumber == disc && *song.TrackNumber == track {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// stripAlbum убирает связанные сущности: они хранятся в собственных map.
+func stripAlbum(album models.Album) models.Album {
+	album.Artist = nil
+	album.Tracks = nil
+
+	return album
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"time"
+)
+
+type apiKeyRepository struct {
+	*Storage
+}
+
+func (r *apiKeyRepository) List(_ context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := sortedByID(r.apiKeys, func(k models.APIKey) uint { return k.ID })
+	for i := range keys {
+		keys[i] = cloneAPIKey(keys[i])
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Get(_ context.Context, id uint) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.apiKeys[id]
+	if !ok {
+		return models.APIKey{}, storage.ErrAPIKeyNotFound
+	}
+
+	return cloneAPIKey(key), nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(_ context.Context, prefix string) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.apiKeys {
+		if key.Prefix == prefix {
+			return cloneAPIKey(key), nil
+		}
+	}
+
+	return models.APIKey{}, storage.ErrAPIKeyNotFound
+}
+
+func (r *apiKeyRepository) Create(_ context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.createAPIKey(key)
+}
+
+func (r *apiKeyRepository) Rotate(_ context.Context, id uint, next *models.APIKey, oldExpiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.apiKeys[id]
+	if !ok {
+		return storage.ErrAPIKeyNotFound
+	}
+	if old.Expired(time.Now()) {
+		return storage.ErrAPIKeyExpired
+	}
+	if err := r.createAPIKey(next); err != nil {
+		return err
+	}
+
+	old.ExpiresAt = &oldExpiresAt
+	old.UpdatedAt = time.Now().UTC()
+	r.apiKeys[id] = old
+
+	return nil
+}
+
+func (r *apiKeyRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.apiKeys[id]; !ok {
+		return storage.ErrAPIKeyNotFound
+	}
+	delete(r.apiKeys, id)
+
+	return nil
+}
+
+func (r *apiKeyRepository) Touch(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.apiKeys[id] = key
+	}
+
+	return nil
+}
+
+// createAPIKey сохраняет ключ, проверяя уникальность префикса. Вызывается под блокировкой.
+func (r *apiKeyRepository) createAPIKey(key *models.APIKey) error {
+	for _, k := range r.apiKeys {
+		if k.Prefix == key.Prefix {
+			return storage.ErrAlreadyExists
+		}
+	}
+
+	now := time.Now().UTC()
+	r.lastAPIKeyID++
+	key.ID = r.lastAPIKeyID
+	key.CreatedAt, key.UpdatedAt = now, now
+	r.apiKeys[key.ID] = cloneAPIKey(*key)
+
+	return nil
+}
+
+// cloneAPIKey копирует Scopes, чтобы вызывающий код не менял данные хранилища.
+func cloneAPIKey(key models.APIKey) models.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	return key
+}
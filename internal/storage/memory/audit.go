@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"time"
+)
+
+type auditRepository struct {
+	*Storage
+}
+
+func (r *auditRepository) Append(_ context.Context, entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.appendEntry(entry)
+
+	return nil
+}
+
+func (r *auditRepository) List(_ context.Context, f storage.AuditFilter) (storage.AuditPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.AuditEntry
+	for _, entry := range slices.Backward(r.audit) {
+		if f.Entity != "" && (entry.Entity != f.Entity || (f.EntityID != 0 && entry.EntityID != f.EntityID)) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	page := storage.AuditPage{Total: int64(len(matched))}
+	matched = matched[min(f.Offset, len(matched)):]
+	page.Entries = append(make([]models.AuditEntry, 0, f.Limit), matched[:min(f.Limit, len(matched))]...)
+
+	return page, nil
+}
+
+// record добавляет запись журнала аудита, если хранилище его ведёт. Вызывается под
+// блокировкой до изменения, поэтому ошибка записи оставляет каталог нетронутым,
+// а запись и изменение другие вызовы видят одновременно.
+func (s *Storage) record(
+	ctx context.Context, entity models.AuditEntity, id uint, action models.AuditAction, before, after any,
+) error {
+	if s.rec == nil {
+		return nil
+	}
+
+	entry, err := s.rec.Entry(ctx, entity, id, action, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+	s.appendEntry(entry)
+
+	return nil
+}
+
+// appendEntry добавляет запись в журнал. Вызывается под блокировкой.
+func (s *Storage) appendEntry(entry *models.AuditEntry) {
+	entry.ID = uint(len(s.audit) + 1)
+	entry.CreatedAt = time.Now().UTC()
+	s.audit = append(s.audit, *entry)
+}
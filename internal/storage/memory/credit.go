@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+)
+
+func (r *songRepository) Credits(_ context.Context, songID uint) ([]models.SongCredit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.songCredits(songID), nil
+}
+
+func (r *songRepository) SetCredits(ctx context.Context, songID uint, credits []models.SongCredit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	song, ok := r.songs[songID]
+	if !ok {
+		return storage.ErrSongNotFound
+	}
+
+	primary := slices.IndexFunc(credits, func(c models.SongCredit) bool { return c.Role == models.CreditPrimary })
+	if primary < 0 {
+		return storage.ErrConstraint
+	}
+
+	type key struct {
+		artistID uint
+		role     models.CreditRole
+	}
+	seen := make(map[key]bool, len(credits))
+	rows := make([]models.SongCredit, len(credits))
+	for i, c := range credits {
+		if _, ok := r.artists[c.ArtistID]; !ok {
+			return storage.ErrForeignKey
+		}
+		if !slices.Contains(models.CreditRoles, c.Role) {
+			return storage.ErrConstraint
+		}
+		if seen[key{c.ArtistID, c.Role}] {
+			return storage.ErrAlreadyExists
+		}
+		seen[key{c.ArtistID, c.Role}] = true
+
+		rows[i] = models.SongCredit{SongID: songID, ArtistID: c.ArtistID, Role: c.Role, Position: i + 1}
+	}
+
+	before := song
+	song.ArtistID = credits[primary].ArtistID
+	if err := r.record(ctx, models.AuditSong, songID, models.AuditUpdate, before, song); err != nil {
+		return err
+	}
+	r.songs[songID] = song
+	r.credits[songID] = rows
+
+	return nil
+}
+
+// setPrimaryCredit повторяет триггер songs_primary_credit: основной артист песни
+// всегда указан в титрах с ролью primary. oldArtistID 0 - песня только что создана.
+// Вызывается под блокировкой.
+func (s *Storage) setPrimaryCredit(songID, oldArtistID, newArtistID uint) {
+	credits := s.credits[songID]
+	isPrimary := func(artistID uint) func(models.SongCredit) bool {
+		return func(c models.SongCredit) bool { return c.ArtistID == artistID && c.Role == models.CreditPrimary }
+	}
+
+	if oldArtistID != 0 {
+		if i := slices.IndexFunc(credits, isPrimary(oldArtistID)); i >= 0 {
+			credits = slices.DeleteFunc(credits, isPrimary(newArtistID))
+			i = slices.IndexFunc(credits, isPrimary(oldArtistID))
+			credits[i].ArtistID = newArtistID
+			s.credits[songID] = credits
+			return
+		}
+	}
+
+	if slices.ContainsFunc(credits, isPrimary(newArtistID)) {
+		return
+	}
+
+	position := 1
+	if len(credits) > 0 {
+		position = credits[len(credits)-1].Position + 1
+	}
+	s.credits[songID] = append(credits, models.SongCredit{
+		SongID:   songID,
+		ArtistID: newArtistID,
+		Role:     models.CreditPrimary,
+		Position: position,
+	})
+}
+
+// songCredits возвращает копию титров песни с артистами. Вызывается под блокировкой.
+func (s *Storage) songCredits(songID uint) []models.SongCredit {
+	credits := slices.Clone(s.credits[songID])
+	if credits == nil {
+		return []models.SongCredit{}
+	}
+	for i := range credits {
+		if artist, ok := s.artists[credits[i].ArtistID]; ok {
+			credits[i].Artist = &artist
+		}
+	}
+
+	return credits
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+)
+
+type exportRepository struct {
+	*Storage
+}
+
+// Songs собирает строки под блокировкой и отдаёт их в fn уже после неё,
+// чтобы медленный получатель не задерживал запись в хранилище.
+func (r *exportRepository) Songs(ctx context.Context, f storage.SongFilter, fn func(storage.SongRow) error) error {
+	r.mu.RLock()
+	songs := &songRepository{r.Storage}
+	var matched []models.Song
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		if songs.matches(song, f) {
+			matched = append(matched, song)
+		}
+	}
+	songs.sort(matched, f)
+
+	rows := make([]storage.SongRow, len(matched))
+	for i, song := range matched {
+		rows[i] = storage.SongRow{
+			ID:          song.ID,
+			Name:        song.Name,
+			ArtistID:    song.ArtistID,
+			ArtistName:  r.artists[song.ArtistID].Name,
+			AlbumID:     song.AlbumID,
+			DiscNumber:  song.DiscNumber,
+			TrackNumber: song.TrackNumber,
+		}
+		if song.AlbumID != nil {
+			if album, ok := r.albums[*song.AlbumID]; ok {
+				rows[i].AlbumTitle = &album.Title
+			}
+		}
+		if detail, ok := r.details[song.ID]; ok {
+			rows[i].ReleaseDate, rows[i].Link, rows[i].Text = &detail.ReleaseDate, &detail.Link, &detail.Text
+		}
+	}
+	r.mu.RUnlock()
+
+	return emit(ctx, rows, fn)
+}
+
+func (r *exportRepository) Artists(ctx context.Context, fn func(storage.ArtistRow) error) error {
+	r.mu.RLock()
+	counts := make(map[uint]int)
+	for _, song := range r.songs {
+		counts[song.ArtistID]++
+	}
+
+	artists := sortedByID(r.artists, func(a models.Artist) uint { return a.ID })
+	rows := make([]storage.ArtistRow, len(artists))
+	for i, artist := range artists {
+		rows[i] = storage.ArtistRow{ID: artist.ID, Name: artist.Name, IsGroup: artist.IsGroup, Songs: counts[artist.ID]}
+	}
+	r.mu.RUnlock()
+
+	return emit(ctx, rows, fn)
+}
+
+// emit передаёт строки в fn, пока не отменён ctx.
+func emit[T any](ctx context.Context, rows []T, fn func(T) error) error {
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"time"
+)
+
+type importRepository struct {
+	*Storage
+}
+
+// Import выполняет пробный импорт на копии каталога, которая затем отбрасывается.
+// Ошибок данных записи здесь не бывает: имена артистов ищутся среди тех же живых артистов,
+// по которым проверяется уникальность.
+func (r *importRepository) Import(ctx context.Context, records []storage.ImportRecord, dryRun bool) ([]storage.ImportResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := r.Storage
+	if dryRun {
+		target = r.catalogCopy()
+	}
+
+	now := time.Now().UTC()
+	results := make([]storage.ImportResult, 0, len(records))
+	for _, rec := range records {
+		res, err := target.importRecord(ctx, rec, now)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// importRecord сохраняет запись импорта и записывает её изменения в журнал аудита.
+// Вызывается под блокировкой.
+func (s *Storage) importRecord(ctx context.Context, rec storage.ImportRecord, now time.Time) (storage.ImportResult, error) {
+	var res storage.ImportResult
+
+	artist, ok := s.artistByName(rec.Artist)
+	switch {
+	case !ok:
+		s.lastArtistID++
+		artist = models.Artist{ID: s.lastArtistID, Name: rec.Artist, IsGroup: rec.IsGroup != nil && *rec.IsGroup}
+		if err := s.record(ctx, models.AuditArtist, artist.ID, models.AuditCreate, nil, artist); err != nil {
+			return res, err
+		}
+		s.artists[artist.ID] = artist
+		res.Artist = storage.ImportCreated
+	case rec.IsGroup != nil && *rec.IsGroup != artist.IsGroup:
+		before := artist
+		artist.IsGroup = *rec.IsGroup
+		if err := s.record(ctx, models.AuditArtist, artist.ID, models.AuditUpdate, before, artist); err != nil {
+			return res, err
+		}
+		s.artists[artist.ID] = artist
+		res.Artist = storage.ImportUpdated
+	default:
+		res.Artist = storage.ImportSkipped
+	}
+	res.ArtistID = artist.ID
+
+	if rec.Song == "" {
+		return res, nil
+	}
+
+	song, ok := s.songByName(artist.ID, rec.Song)
+	if !ok {
+		s.lastSongID++
+		song = models.Song{ID: s.lastSongID, Name: rec.Song, ArtistID: artist.ID, CreatedAt: now, UpdatedAt: now}
+		if err := s.record(ctx, models.AuditSong, song.ID, models.AuditCreate, nil, song); err != nil {
+			return res, err
+		}
+		s.songs[song.ID] = song
+		s.setPrimaryCredit(song.ID, 0, artist.ID)
+		res.Song = storage.ImportCreated
+	} else {
+		res.Song = storage.ImportSkipped
+	}
+	res.SongID = song.ID
+
+	if rec.Detail == nil {
+		return res, nil
+	}
+
+	detail := *rec.Detail
+	detail.SongID = song.ID
+	detail.UpdatedAt = now
+
+	action, before := models.AuditCreate, any(nil)
+	existing, ok := s.details[song.ID]
+	switch {
+	case !ok:
+		s.lastDetailID++
+		detail.ID = s.lastDetailID
+		detail.CreatedAt = now
+		res.Detail = storage.ImportCreated
+	case existing.Text == detail.Text && existing.ReleaseDate.Equal(detail.ReleaseDate) && existing.Link == detail.Link:
+		res.Detail = storage.ImportSkipped
+		return res, nil
+	default:
+		detail.ID = existing.ID
+		detail.CreatedAt = existing.CreatedAt
+		res.Detail = storage.ImportUpdated
+		action, before = models.AuditUpdate, existing
+	}
+	if err := s.record(ctx, models.AuditSongDetail, song.ID, action, before, detail); err != nil {
+		return res, err
+	}
+	s.details[song.ID] = detail
+
+	if !ok || existing.Text != detail.Text {
+		s.appendRevision(ctx, song.ID, detail.Text, now)
+	}
+
+	return res, nil
+}
+
+// artistByName ищет живого артиста по точному имени. Вызывается под блокировкой.
+func (s *Storage) artistByName(name string) (models.Artist, bool) {
+	for _, artist := range s.artists {
+		if artist.Name == name {
+			return artist, true
+		}
+	}
+
+	return models.Artist{}, false
+}
+
+// songByName ищет песню артиста по точному названию, при дублях - с меньшим ID.
+// Вызывается под блокировкой.
+func (s *Storage) songByName(artistID uint, name string) (models.Song, bool) {
+	var found models.Song
+	for _, song := range s.songs {
+		if song.ArtistID == artistID && song.Name == name && (found.ID == 0 || song.ID < found.ID) {
+			found = song
+		}
+	}
+
+	return found, found.ID != 0
+}
+
+// catalogCopy возвращает копию артистов, песен, деталей, титров и версий текстов,
+// которую импорт может менять, не затрагивая хранилище. Вызывается под блокировкой.
+func (s *Storage) catalogCopy() *Storage {
+	cp := &Storage{state: &state{
+		artists:   maps.Clone(s.artists),
+		songs:     maps.Clone(s.songs),
+		details:   maps.Clone(s.details),
+		credits:   maps.Clone(s.credits),
+		revisions: maps.Clone(s.revisions),
+
+		lastArtistID:   s.lastArtistID,
+		lastSongID:     s.lastSongID,
+		lastDetailID:   s.lastDetailID,
+		lastRevisionID: s.lastRevisionID,
+	}}
+	// Без запаса ёмкости append в копии не запишет в массивы хранилища
+	for songID, revisions := range cp.revisions {
+		cp.revisions[songID] = slices.Clip(revisions)
+	}
+
+	return cp
+}
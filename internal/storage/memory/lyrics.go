@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+)
+
+func (r *songDetailRepository) Revisions(_ context.Context, songID uint, limit, offset int) (storage.RevisionPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[songID]
+	page := storage.RevisionPage{
+		Revisions: make([]models.LyricsRevision, 0, limit),
+		Total:     int64(len(revisions)),
+	}
+	for i := len(revisions) - 1 - offset; i >= 0 && len(page.Revisions) < limit; i-- {
+		rev := revisions[i]
+		rev.Text = ""
+		page.Revisions = append(page.Revisions, rev)
+	}
+
+	return page, nil
+}
+
+func (r *songDetailRepository) Revision(_ context.Context, songID uint, number int) (models.LyricsRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[songID]
+	if number < 1 || number > len(revisions) {
+		return models.LyricsRevision{}, storage.ErrRevisionNotFound
+	}
+
+	return revisions[number-1], nil
+}
+
+// appendRevision добавляет следующую версию текста песни. Вызывается под блокировкой.
+func (s *Storage) appendRevision(ctx context.Context, songID uint, text string, at time.Time) {
+	s.lastRevisionID++
+	rev := models.LyricsRevision{
+		ID:        s.lastRevisionID,
+		SongID:    songID,
+		Number:    len(s.revisions[songID]) + 1,
+		Text:      text,
+		CreatedAt: at,
+	}
+	if a := actor.From(ctx); a.UserID != 0 {
+		rev.UserID = &a.UserID
+	} else if a.APIKeyID != 0 {
+		rev.APIKeyID = &a.APIKeyID
+	}
+
+	s.revisions[songID] = append(s.revisions[songID], rev)
+}
@@ -0,0 +1,700 @@
+// Package memory реализует хранилище в памяти процесса для тестов и локальных демо.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Storage хранит данные в map под общим мьютексом. Каскадное удаление
+// повторяет ограничения внешних ключей из миграций.
+type Storage struct {
+	*state
+
+	// rec записывает изменения каталога в журнал аудита; nil - журнал не ведётся.
+	rec storage.Recorder
+}
+
+// state - данные хранилища; хранилище из WithAudit разделяет их с исходным.
+type state struct {
+	mu sync.RWMutex
+
+	artists map[uint]models.Artist
+	songs   map[uint]models.Song
+	details map[uint]models.SongDetail // ключ - SongID
+	albums  map[uint]models.Album
+	credits map[uint][]models.SongCredit // ключ - SongID, по возрастанию Position
+	// revisions - версии текстов по возрастанию номера; ключ - SongID
+	revisions map[uint][]models.LyricsRevision
+
+	// Артисты и песни в корзине хранятся отдельно, поэтому остальные методы их не видят
+	trashedArtists map[uint]models.Artist
+	trashedSongs   map[uint]models.Song
+
+	playlists     map[uint]models.Playlist
+	items         map[uint]models.PlaylistItem           // ключ - ID элемента
+	collaborators map[uint][]models.PlaylistCollaborator // ключ - PlaylistID
+
+	users   map[uint]models.User
+	apiKeys map[uint]models.APIKey
+
+	// audit - журнал аудита по возрастанию ID; ID записи - её номер в срезе
+	audit []models.AuditEntry
+
+	lastArtistID   uint
+	lastSongID     uint
+	lastDetailID   uint
+	lastRevisionID uint
+	lastAlbumID    uint
+	lastPlaylistID uint
+	lastItemID     uint
+	lastUserID     uint
+	lastAPIKeyID   uint
+}
+
+// New создаёт пустое хранилище.
+func New() *Storage {
+	return &Storage{state: &state{
+		artists: make(map[uint]models.Artist),
+		songs:   make(map[uint]models.Song),
+		details: make(map[uint]models.SongDetail),
+		albums:  make(map[uint]models.Album),
+		credits: make(map[uint][]models.SongCredit),
+
+		revisions: make(map[uint][]models.LyricsRevision),
+
+		trashedArtists: make(map[uint]models.Artist),
+		trashedSongs:   make(map[uint]models.Song),
+
+		playlists:     make(map[uint]models.Playlist),
+		items:         make(map[uint]models.PlaylistItem),
+		collaborators: make(map[uint][]models.PlaylistCollaborator),
+
+		users:   make(map[uint]models.User),
+		apiKeys: make(map[uint]models.APIKey),
+	}}
+}
+
+// WithAudit возвращает хранилище с теми же данными, записывающее изменения каталога через rec.
+func (s *Storage) WithAudit(rec storage.Recorder) storage.Storage {
+	return &Storage{state: s.state, rec: rec}
+}
+
+// Artists возвращает репозиторий артистов.
+func (s *Storage) Artists() storage.ArtistRepository {
+	return &artistRepository{s}
+}
+
+// Songs возвращает репозиторий песен.
+func (s *Storage) Songs() storage.SongRepository {
+	return &songRepository{s}
+}
+
+// SongDetails возвращает репозиторий деталей песен.
+func (s *Storage) SongDetails() storage.SongDetailRepository {
+	return &songDetailRepository{s}
+}
+
+// Albums возвращает репозиторий альбомов.
+func (s *Storage) Albums() storage.AlbumRepository {
+	return &albumRepository{s}
+}
+
+// Playlists возвращает репозиторий плейлистов.
+func (s *Storage) Playlists() storage.PlaylistRepository {
+	return &playlistRepository{s}
+}
+
+// Users возвращает репозиторий пользователей.
+func (s *Storage) Users() storage.UserRepository {
+	return &userRepository{s}
+}
+
+// APIKeys возвращает репозиторий API-ключей.
+func (s *Storage) APIKeys() storage.APIKeyRepository {
+	return &apiKeyRepository{s}
+}
+
+// Trash возвращает репозиторий корзины.
+func (s *Storage) Trash() storage.TrashRepository {
+	return &trashRepository{s}
+}
+
+// Audit возвращает журнал аудита.
+func (s *Storage) Audit() storage.AuditRepository {
+	return &auditRepository{s}
+}
+
+// Import возвращает репозиторий массового импорта.
+func (s *Storage) Import() storage.ImportRepository {
+	return &importRepository{s}
+}
+
+// Export возвращает репозиторий выгрузки каталога.
+func (s *Storage) Export() storage.ExportRepository {
+	return &exportRepository{s}
+}
+
+// Search возвращает репозиторий полнотекстового поиска.
+func (s *Storage) Search() storage.SearchRepository {
+	return &searchRepository{s}
+}
+
+type artistRepository struct {
+	*Storage
+}
+
+func (r *artistRepository) List(_ context.Context) ([]models.Artist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return sortedByID(r.artists, func(a models.Artist) uint { return a.ID }), nil
+}
+
+func (r *artistRepository) Get(_ context.Context, id uint) (models.Artist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	artist, ok := r.artists[id]
+	if !ok {
+		return models.Artist{}, storage.ErrArtistNotFound
+	}
+
+	return artist, nil
+}
+
+func (r *artistRepository) GetWithSongs(_ context.Context, id uint, withDetails bool) (models.Artist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	artist, ok := r.artists[id]
+	if !ok {
+		return models.Artist{}, storage.ErrArtistNotFound
+	}
+	artist.Songs = r.artistSongs(id, withDetails)
+
+	return artist, nil
+}
+
+// artistSongs возвращает песни артиста по возрастанию id. Вызывается под блокировкой.
+func (r *artistRepository) artistSongs(artistID uint, withDetails bool) []models.Song {
+	var songs []models.Song
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		if song.ArtistID != artistID {
+			continue
+		}
+		if withDetails {
+			song.SongDetail = r.detail(song.ID)
+		}
+		songs = append(songs, song)
+	}
+
+	return songs
+}
+
+func (r *artistRepository) Create(ctx context.Context, artist *models.Artist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nameTaken(artist.Name, 0) {
+		return storage.ErrArtistExists
+	}
+
+	r.lastArtistID++
+	artist.ID = r.lastArtistID
+	artist.Songs = nil
+	if err := r.record(ctx, models.AuditArtist, artist.ID, models.AuditCreate, nil, *artist); err != nil {
+		return err
+	}
+	r.artists[artist.ID] = *artist
+
+	return nil
+}
+
+func (r *artistRepository) Update(ctx context.Context, artist *models.Artist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before, ok := r.artists[artist.ID]
+	if !ok {
+		return storage.ErrArtistNotFound
+	}
+	if r.nameTaken(artist.Name, artist.ID) {
+		return storage.ErrArtistExists
+	}
+	stored := *artist
+	stored.Songs = nil
+	stored.Cover = before.Cover
+	if err := r.record(ctx, models.AuditArtist, artist.ID, models.AuditUpdate, before, stored); err != nil {
+		return err
+	}
+	r.artists[artist.ID] = stored
+
+	return nil
+}
+
+func (r *artistRepository) SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before, ok := r.artists[id]
+	if !ok {
+		return nil, storage.ErrArtistNotFound
+	}
+	artist := before
+	artist.Cover = cover
+	if err := r.record(ctx, models.AuditArtist, id, models.AuditUpdate, before, artist); err != nil {
+		return nil, err
+	}
+	r.artists[id] = artist
+
+	return before.Cover, nil
+}
+
+// nameTaken проверяет уникальность имени, как ограничение unique на artists.name.
+func (r *artistRepository) nameTaken(name string, exceptID uint) bool {
+	for id, artist := range r.artists {
+		if id != exceptID && artist.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *artistRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	artist, ok := r.artists[id]
+	if !ok {
+		return storage.ErrArtistNotFound
+	}
+	if err := r.record(ctx, models.AuditArtist, id, models.AuditDelete, artist, nil); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	artist.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	delete(r.artists, id)
+	r.trashedArtists[id] = artist
+
+	for songID, song := range r.songs {
+		if song.ArtistID == id {
+			r.trashSong(songID, now)
+		}
+	}
+
+	return nil
+}
+
+// artistReferenced сообщает, ссылаются ли на артиста песни или альбомы: такого артиста
+// очистка корзины пропускает, как и в pgsql. Вызывается под блокировкой.
+func (s *Storage) artistReferenced(id uint) bool {
+	for _, songs := range []map[uint]models.Song{s.songs, s.trashedSongs} {
+		for _, song := range songs {
+			if song.ArtistID == id {
+				return true
+			}
+		}
+	}
+	for _, album := range s.albums {
+		if album.ArtistID != nil && *album.ArtistID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// purgeArtist окончательно удаляет артиста и отвязывает его титры. Вызывается под блокировкой.
+func (s *Storage) purgeArtist(id uint) {
+	delete(s.artists, id)
+	delete(s.trashedArtists, id)
+	for songID, credits := range s.credits {
+		s.credits[songID] = slices.DeleteFunc(credits, func(c models.SongCredit) bool { return c.ArtistID == id })
+	}
+}
+
+type songRepository struct {
+	*Storage
+}
+
+func (r *songRepository) List(_ context.Context, f storage.SongFilter) (storage.SongPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.Song
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		if r.matches(song, f) {
+			matched = append(matched, song)
+		}
+	}
+
+	page := storage.SongPage{Total: int64(len(matched))}
+	r.sort(matched, f)
+
+	if f.Cursor != 0 {
+		i, _ := slices.BinarySearchFunc(matched, f.Cursor+1, func(s models.Song, id uint) int {
+			return cmp.Compare(s.ID, id)
+		})
+		matched = matched[i:]
+	} else {
+		matched = matched[min(f.Offset, len(matched)):]
+	}
+
+	if len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+		if (f.Sort == "" || f.Sort == storage.SongSortID) && !f.Desc {
+			page.NextCursor = storage.EncodeCursor(matched[len(matched)-1].ID)
+		}
+	}
+	page.Songs = append(make([]models.Song, 0, len(matched)), matched...)
+	for i := range page.Songs {
+		if f.WithDetails {
+			page.Songs[i].SongDetail = r.detail(page.Songs[i].ID)
+		}
+		if f.WithCredits {
+			page.Songs[i].Credits = r.songCredits(page.Songs[i].ID)
+		}
+	}
+
+	return page, nil
+}
+
+// sort упорядочивает песни так же, как pgsql: песни без даты релиза идут в конце.
+func (r *songRepository) sort(songs []models.Song, f storage.SongFilter) {
+	slices.SortStableFunc(songs, func(a, b models.Song) int {
+		var c int
+		switch f.Sort {
+		case storage.SongSortName:
+			c = cmp.Compare(a.Name, b.Name)
+		case storage.SongSortReleaseDate:
+			da, okA := r.details[a.ID]
+			db, okB := r.details[b.ID]
+			switch {
+			case !okA && !okB:
+			case !okA:
+				return 1
+			case !okB:
+				return -1
+			default:
+				c = da.ReleaseDate.Compare(db.ReleaseDate)
+			}
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if f.Desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// matches проверяет песню на соответствие фильтру. Вызывается под блокировкой.
+func (r *songRepository) matches(song models.Song, f storage.SongFilter) bool {
+	if f.Name != "" && !containsFold(song.Name, f.Name) {
+		return false
+	}
+	if f.ArtistID != 0 && song.ArtistID != f.ArtistID {
+		return false
+	}
+	if f.Artist != "" && !containsFold(r.artists[song.ArtistID].Name, f.Artist) {
+		return false
+	}
+
+	detail, hasDetail := r.details[song.ID]
+	if (f.ReleasedFrom != nil || f.ReleasedTo != nil || f.Text != "") && !hasDetail {
+		return false
+	}
+	if f.ReleasedFrom != nil && detail.ReleaseDate.Before(*f.ReleasedFrom) {
+		return false
+	}
+	if f.ReleasedTo != nil && detail.ReleaseDate.After(*f.ReleasedTo) {
+		return false
+	}
+	if f.HasLink != nil && (detail.Link != "") != *f.HasLink {
+		return false
+	}
+	if f.Text != "" && !containsFold(detail.Text, f.Text) {
+		return false
+	}
+
+	if f.CreditArtistID != 0 || f.CreditRole != "" {
+		credited := slices.ContainsFunc(r.credits[song.ID], func(c models.SongCredit) bool {
+			return (f.CreditArtistID == 0 || c.ArtistID == f.CreditArtistID) &&
+				(f.CreditRole == "" || c.Role == f.CreditRole)
+		})
+		if !credited {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *songRepository) Get(_ context.Context, id uint) (models.Song, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	song, ok := r.songs[id]
+	if !ok {
+		return models.Song{}, storage.ErrSongNotFound
+	}
+
+	return song, nil
+}
+
+func (r *songRepository) GetWithDetails(_ context.Context, id uint) (models.Song, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	song, ok := r.songs[id]
+	if !ok {
+		return models.Song{}, storage.ErrSongNotFound
+	}
+	if artist, ok := r.artists[song.ArtistID]; ok {
+		song.Artist = &artist
+	}
+	song.SongDetail = r.detail(song.ID)
+
+	return song, nil
+}
+
+func (r *songRepository) Create(ctx context.Context, song *models.Song) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.artists[song.ArtistID]; !ok {
+		return storage.ErrForeignKey
+	}
+	if err := r.checkTrack(song); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	r.lastSongID++
+	song.ID = r.lastSongID
+	song.CreatedAt, song.UpdatedAt = now, now
+	if err := r.record(ctx, models.AuditSong, song.ID, models.AuditCreate, nil, stripSong(*song)); err != nil {
+		return err
+	}
+	r.songs[song.ID] = stripSong(*song)
+	r.setPrimaryCredit(song.ID, 0, song.ArtistID)
+
+	return nil
+}
+
+func (r *songRepository) Update(ctx context.Context, song *models.Song) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.songs[song.ID]
+	if !ok {
+		return storage.ErrSongNotFound
+	}
+	if _, ok := r.artists[song.ArtistID]; !ok {
+		return storage.ErrForeignKey
+	}
+	if err := r.checkTrack(song); err != nil {
+		return err
+	}
+
+	song.CreatedAt = stored.CreatedAt
+	song.UpdatedAt = time.Now().UTC()
+	song.Cover = stored.Cover
+	if err := r.record(ctx, models.AuditSong, song.ID, models.AuditUpdate, stored, stripSong(*song)); err != nil {
+		return err
+	}
+	r.songs[song.ID] = stripSong(*song)
+	if stored.ArtistID != song.ArtistID {
+		r.setPrimaryCredit(song.ID, stored.ArtistID, song.ArtistID)
+	}
+
+	return nil
+}
+
+func (r *songRepository) SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before, ok := r.songs[id]
+	if !ok {
+		return nil, storage.ErrSongNotFound
+	}
+	song := before
+	song.Cover = cover
+	song.UpdatedAt = time.Now().UTC()
+	if err := r.record(ctx, models.AuditSong, id, models.AuditUpdate, before, song); err != nil {
+		return nil, err
+	}
+	r.songs[id] = song
+
+	return before.Cover, nil
+}
+
+func (r *songRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	song, ok := r.songs[id]
+	if !ok {
+		return storage.ErrSongNotFound
+	}
+	if err := r.record(ctx, models.AuditSong, id, models.AuditDelete, song, nil); err != nil {
+		return err
+	}
+	r.trashSong(id, time.Now().UTC())
+
+	return nil
+}
+
+// checkTrack повторяет ограничения позиции песни в альбоме из миграции 4_albums.
+// Песня без альбома теряет номера диска и трека. Вызывается под блокировкой.
+func (r *songRepository) checkTrack(song *models.Song) error {
+	if song.AlbumID == nil {
+		song.DiscNumber, song.TrackNumber = nil, nil
+		return nil
+	}
+	if _, ok := r.albums[*song.AlbumID]; !ok {
+		return storage.ErrForeignKey
+	}
+	if song.DiscNumber == nil || song.TrackNumber == nil || *song.DiscNumber < 1 || *song.TrackNumber < 1 {
+		return storage.ErrConstraint
+	}
+	if r.trackTaken(*song.AlbumID, *song.DiscNumber, *song.TrackNumber, song.ID) {
+		return storage.ErrTrackTaken
+	}
+
+	return nil
+}
+
+type songDetailRepository struct {
+	*Storage
+}
+
+func (r *songDetailRepository) GetBySongID(_ context.Context, songID uint) (models.SongDetail, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	detail, ok := r.details[songID]
+	if !ok {
+		return models.SongDetail{}, storage.ErrSongDetailNotFound
+	}
+
+	return detail, nil
+}
+
+func (r *songDetailRepository) Save(ctx context.Context, detail *models.SongDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.songs[detail.SongID]; !ok {
+		return storage.ErrForeignKey
+	}
+
+	now := time.Now().UTC()
+	action, before := models.AuditCreate, any(nil)
+	existing, ok := r.details[detail.SongID]
+	if ok {
+		detail.ID = existing.ID
+		detail.CreatedAt = existing.CreatedAt
+		action, before = models.AuditUpdate, existing
+	} else {
+		r.lastDetailID++
+		detail.ID = r.lastDetailID
+		detail.CreatedAt = now
+	}
+	detail.UpdatedAt = now
+	if err := r.record(ctx, models.AuditSongDetail, detail.SongID, action, before, *detail); err != nil {
+		return err
+	}
+	r.details[detail.SongID] = *detail
+
+	if !ok || existing.Text != detail.Text {
+		r.appendRevision(ctx, detail.SongID, detail.Text, now)
+	}
+
+	return nil
+}
+
+func (r *songDetailRepository) DeleteBySongID(ctx context.Context, songID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	detail, ok := r.details[songID]
+	if !ok {
+		return storage.ErrSongDetailNotFound
+	}
+	if err := r.record(ctx, models.AuditSongDetail, songID, models.AuditDelete, detail, nil); err != nil {
+		return err
+	}
+	delete(r.details, songID)
+
+	return nil
+}
+
+// detail возвращает копию деталей песни или nil. Вызывается под блокировкой.
+func (s *Storage) detail(songID uint) *models.SongDetail {
+	detail, ok := s.details[songID]
+	if !ok {
+		return nil
+	}
+
+	return &detail
+}
+
+// trashSong переносит песню в корзину. Вызывается под блокировкой.
+func (s *Storage) trashSong(id uint, at time.Time) {
+	song := s.songs[id]
+	song.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	delete(s.songs, id)
+	s.trashedSongs[id] = song
+}
+
+// deleteSong окончательно удаляет песню вместе с деталями, титрами и элементами плейлистов.
+// Вызывается под блокировкой.
+func (s *Storage) deleteSong(id uint) {
+	delete(s.songs, id)
+	delete(s.trashedSongs, id)
+	delete(s.details, id)
+	delete(s.credits, id)
+	delete(s.revisions, id)
+	for itemID, item := range s.items {
+		if item.SongID == id {
+			delete(s.items, itemID)
+		}
+	}
+}
+
+// stripSong убирает связанные сущности: они хранятся в собственных map.
+func stripSong(song models.Song) models.Song {
+	song.Artist = nil
+	song.SongDetail = nil
+	song.Credits = nil
+
+	return song
+}
+
+func sortedByID[T any](m map[uint]T, id func(T) uint) []T {
+	items := slices.AppendSeq(make([]T, 0, len(m)), maps.Values(m))
+	slices.SortFunc(items, func(a, b T) int {
+		return cmp.Compare(id(a), id(b))
+	})
+
+	return items
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,344 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"music-lib/internal/lib/rank"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+type playlistRepository struct {
+	*Storage
+}
+
+func (r *playlistRepository) List(_ context.Context, f storage.PlaylistFilter) (storage.PlaylistPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]models.Playlist, 0)
+	for _, playlist := range r.playlists {
+		if f.MemberID != 0 && !r.isMember(playlist, f.MemberID) {
+			continue
+		}
+		if f.Visibility != "" && playlist.Visibility != f.Visibility {
+			continue
+		}
+		matched = append(matched, playlist)
+	}
+
+	// Как в pgsql: недавно изменённые первыми
+	slices.SortFunc(matched, func(a, b models.Playlist) int {
+		if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	from := min(f.Offset, len(matched))
+	return storage.PlaylistPage{
+		Playlists: matched[from:min(from+f.Limit, len(matched))],
+		Total:     int64(len(matched)),
+	}, nil
+}
+
+func (r *playlistRepository) Get(_ context.Context, id uint) (models.Playlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	playlist, ok := r.playlists[id]
+	if !ok {
+		return models.Playlist{}, storage.ErrPlaylistNotFound
+	}
+	playlist.Collaborators = slices.Clone(r.collaborators[id])
+
+	return playlist, nil
+}
+
+func (r *playlistRepository) GetWithItems(_ context.Context, id uint) (models.Playlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	playlist, ok := r.playlists[id]
+	if !ok {
+		return models.Playlist{}, storage.ErrPlaylistNotFound
+	}
+	playlist.Collaborators = slices.Clone(r.collaborators[id])
+
+	playlist.Items = r.playlistItems(id, 0)
+	for i := range playlist.Items {
+		if song, ok := r.songs[playlist.Items[i].SongID]; ok {
+			playlist.Items[i].Song = &song
+		}
+	}
+
+	return playlist, nil
+}
+
+func (r *playlistRepository) Create(_ context.Context, playlist *models.Playlist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	r.lastPlaylistID++
+	playlist.ID = r.lastPlaylistID
+	playlist.Version = 1
+	playlist.CreatedAt, playlist.UpdatedAt = now, now
+	r.playlists[playlist.ID] = stripPlaylist(*playlist)
+
+	return nil
+}
+
+func (r *playlistRepository) Update(_ context.Context, playlist *models.Playlist, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.bumpVersion(playlist.ID, version)
+	if err != nil {
+		return err
+	}
+
+	stored.Name, stored.Description, stored.Visibility = playlist.Name, playlist.Description, playlist.Visibility
+	r.playlists[stored.ID] = stored
+	playlist.Version, playlist.UpdatedAt = stored.Version, stored.UpdatedAt
+
+	return nil
+}
+
+func (r *playlistRepository) Delete(_ context.Context, id uint, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.bumpVersion(id, version); err != nil {
+		return err
+	}
+
+	delete(r.playlists, id)
+	delete(r.collaborators, id)
+	for itemID, item := range r.items {
+		if item.PlaylistID == id {
+			delete(r.items, itemID)
+		}
+	}
+
+	return nil
+}
+
+func (r *playlistRepository) AddItem(
+	_ context.Context, playlistID uint, version int, item *models.PlaylistItem, at storage.Placement,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkVersion(playlistID, version); err != nil {
+		return err
+	}
+	if _, ok := r.songs[item.SongID]; !ok {
+		return storage.ErrSongNotFound
+	}
+
+	position, err := r.itemPosition(playlistID, 0, at)
+	if err != nil {
+		return err
+	}
+	if _, err := r.bumpVersion(playlistID, version); err != nil {
+		return err
+	}
+
+	r.lastItemID++
+	item.ID = r.lastItemID
+	item.PlaylistID = playlistID
+	item.Position = position
+	item.CreatedAt = time.Now().UTC()
+	stored := *item
+	stored.Song = nil
+	r.items[item.ID] = stored
+
+	return nil
+}
+
+func (r *playlistRepository) MoveItem(
+	_ context.Context, playlistID uint, version int, itemID uint, at storage.Placement,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkVersion(playlistID, version); err != nil {
+		return err
+	}
+	item, ok := r.items[itemID]
+	if !ok || item.PlaylistID != playlistID {
+		return storage.ErrPlaylistItemNotFound
+	}
+
+	position, err := r.itemPosition(playlistID, itemID, at)
+	if err != nil {
+		return err
+	}
+	if _, err := r.bumpVersion(playlistID, version); err != nil {
+		return err
+	}
+
+	item.Position = position
+	r.items[itemID] = item
+
+	return nil
+}
+
+func (r *playlistRepository) RemoveItem(_ context.Context, playlistID uint, version int, itemID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkVersion(playlistID, version); err != nil {
+		return err
+	}
+	if item, ok := r.items[itemID]; !ok || item.PlaylistID != playlistID {
+		return storage.ErrPlaylistItemNotFound
+	}
+	if _, err := r.bumpVersion(playlistID, version); err != nil {
+		return err
+	}
+
+	delete(r.items, itemID)
+
+	return nil
+}
+
+func (r *playlistRepository) AddCollaborator(_ context.Context, playlistID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.playlists[playlistID]; !ok {
+		return storage.ErrPlaylistNotFound
+	}
+	if _, ok := r.users[userID]; !ok {
+		return storage.ErrUserNotFound
+	}
+	if r.isCollaborator(playlistID, userID) {
+		return nil
+	}
+
+	r.collaborators[playlistID] = append(r.collaborators[playlistID], models.PlaylistCollaborator{
+		PlaylistID: playlistID,
+		UserID:     userID,
+		CreatedAt:  time.Now().UTC(),
+	})
+
+	return nil
+}
+
+func (r *playlistRepository) RemoveCollaborator(_ context.Context, playlistID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.isCollaborator(playlistID, userID) {
+		return storage.ErrCollaboratorNotFound
+	}
+	r.collaborators[playlistID] = slices.DeleteFunc(r.collaborators[playlistID], func(c models.PlaylistCollaborator) bool {
+		return c.UserID == userID
+	})
+
+	return nil
+}
+
+// checkVersion сверяет версию плейлиста. Вызывается под блокировкой.
+func (r *playlistRepository) checkVersion(id uint, version int) error {
+	playlist, ok := r.playlists[id]
+	if !ok {
+		return storage.ErrPlaylistNotFound
+	}
+	if playlist.Version != version {
+		return storage.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// bumpVersion сверяет версию плейлиста и увеличивает её. Вызывается под блокировкой.
+func (r *playlistRepository) bumpVersion(id uint, version int) (models.Playlist, error) {
+	if err := r.checkVersion(id, version); err != nil {
+		return models.Playlist{}, err
+	}
+
+	playlist := r.playlists[id]
+	playlist.Version++
+	playlist.UpdatedAt = time.Now().UTC()
+	r.playlists[id] = playlist
+
+	return playlist, nil
+}
+
+// itemPosition вычисляет позицию для элемента по месту at, не учитывая элемент exclude.
+// Вызывается под блокировкой.
+func (r *playlistRepository) itemPosition(playlistID, exclude uint, at storage.Placement) (string, error) {
+	items := r.playlistItems(playlistID, exclude)
+
+	anchor := at.Before
+	if at.After != 0 {
+		anchor = at.After
+	}
+	if anchor == 0 {
+		if len(items) == 0 {
+			return rank.Between("", "")
+		}
+		return rank.Between(items[len(items)-1].Position, "")
+	}
+
+	i := slices.IndexFunc(items, func(item models.PlaylistItem) bool { return item.ID == anchor })
+	if i < 0 {
+		return "", storage.ErrPlaylistItemNotFound
+	}
+
+	var lower, upper string
+	if at.Before != 0 {
+		upper = items[i].Position
+		if i > 0 {
+			lower = items[i-1].Position
+		}
+	} else {
+		lower = items[i].Position
+		if i+1 < len(items) {
+			upper = items[i+1].Position
+		}
+	}
+
+	return rank.Between(lower, upper)
+}
+
+// playlistItems возвращает элементы плейлиста по возрастанию позиции, кроме exclude.
+// Вызывается под блокировкой.
+func (s *Storage) playlistItems(playlistID, exclude uint) []models.PlaylistItem {
+	items := make([]models.PlaylistItem, 0)
+	for _, item := range s.items {
+		if item.PlaylistID == playlistID && item.ID != exclude {
+			items = append(items, item)
+		}
+	}
+	// Позиции сравниваются побайтно, как COLLATE "C" в pgsql
+	slices.SortFunc(items, func(a, b models.PlaylistItem) int {
+		return strings.Compare(a.Position, b.Position)
+	})
+
+	return items
+}
+
+func (s *Storage) isMember(playlist models.Playlist, userID uint) bool {
+	return playlist.OwnerID == userID || s.isCollaborator(playlist.ID, userID)
+}
+
+func (s *Storage) isCollaborator(playlistID, userID uint) bool {
+	return slices.ContainsFunc(s.collaborators[playlistID], func(c models.PlaylistCollaborator) bool {
+		return c.UserID == userID
+	})
+}
+
+// stripPlaylist убирает связанные сущности: они хранятся в собственных map.
+func stripPlaylist(playlist models.Playlist) models.Playlist {
+	playlist.Items = nil
+	playlist.Collaborators = nil
+
+	return playlist
+}
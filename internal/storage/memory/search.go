@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"music-lib/internal/lib/lyrics"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Веса полей повторяют веса A, B и C ts_rank_cd.
+const (
+	weightSong   = 1.0
+	weightArtist = 0.4
+	weightLyrics = 0.2
+)
+
+type searchRepository struct {
+	*Storage
+}
+
+// Search приближённо повторяет поиск Postgres. В полнотекстовом режиме вместо стемминга
+// слово документа совпадает с термином запроса, если начинается с него. Песня найдена,
+// если каждый термин встречается в названии, имени артиста или тексте.
+func (r *searchRepository) Search(_ context.Context, q storage.SearchQuery) (storage.SearchPage, error) {
+	terms := searchTokens(q.Query)
+	hits := make([]storage.SearchHit, 0)
+
+	r.mu.RLock()
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		artist := r.artists[song.ArtistID].Name
+
+		if q.Mode == storage.SearchModeFuzzy {
+			rank := max(wordSimilarity(q.Query, song.Name), wordSimilarity(q.Query, artist))
+			if rank >= q.Threshold {
+				hits = append(hits, storage.SearchHit{
+					SongID: song.ID, Song: song.Name, ArtistID: song.ArtistID, Artist: artist, Rank: rank,
+				})
+			}
+			continue
+		}
+
+		var text string
+		if detail, ok := r.details[song.ID]; ok {
+			text = detail.Text
+		}
+		if hit, ok := searchSong(song, artist, text, terms); ok {
+			hits = append(hits, hit)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortStableFunc(hits, func(a, b storage.SearchHit) int {
+		return cmp.Compare(b.Rank, a.Rank)
+	})
+
+	page := storage.SearchPage{Total: int64(len(hits))}
+	from := min(q.Offset, len(hits))
+	page.Hits = hits[from:min(from+q.Limit, len(hits))]
+
+	return page, nil
+}
+
+func (r *searchRepository) Suggest(_ context.Context, q storage.SuggestQuery) (storage.Suggestions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := storage.Suggestions{Artists: make([]storage.Suggestion, 0), Songs: make([]storage.Suggestion, 0)}
+	for _, artist := range sortedByID(r.artists, func(a models.Artist) uint { return a.ID }) {
+		if sim := wordSimilarity(q.Query, artist.Name); sim >= q.Threshold {
+			res.Artists = append(res.Artists, storage.Suggestion{ID: artist.ID, Name: artist.Name, Similarity: sim})
+		}
+	}
+	for _, song := range sortedByID(r.songs, func(s models.Song) uint { return s.ID }) {
+		if sim := wordSimilarity(q.Query, song.Name); sim >= q.Threshold {
+			res.Songs = append(res.Songs, storage.Suggestion{
+				ID: song.ID, Name: song.Name, ArtistID: song.ArtistID, Artist: r.artists[song.ArtistID].Name, Similarity: sim,
+			})
+		}
+	}
+
+	for _, list := range []*[]storage.Suggestion{&res.Artists, &res.Songs} {
+		slices.SortStableFunc(*list, func(a, b storage.Suggestion) int {
+			return cmp.Compare(b.Similarity, a.Similarity)
+		})
+		*list = (*list)[:min(q.Limit, len(*list))]
+	}
+
+	return res, nil
+}
+
+func searchSong(song models.Song, artist, text string, terms []string) (storage.SearchHit, bool) {
+	if len(terms) == 0 {
+		return storage.SearchHit{}, false
+	}
+
+	songTokens, artistTokens, lyricsTokens := searchTokens(song.Name), searchTokens(artist), searchTokens(text)
+
+	var rank float64
+	for _, term := range terms {
+		n, a, l := countMatches(songTokens, term), countMatches(artistTokens, term), countMatches(lyricsTokens, term)
+		if n+a+l == 0 {
+			return storage.SearchHit{}, false
+		}
+		rank += weightSong*float64(n) + weightArtist*float64(a) + weightLyrics*float64(l)
+	}
+
+	hit := storage.SearchHit{
+		SongID:   song.ID,
+		Song:     song.Name,
+		ArtistID: song.ArtistID,
+		Artist:   artist,
+		Rank:     rank,
+	}
+
+	best := 0
+	for i, verse := range lyrics.Verses(text) {
+		snippet, matches := highlight(verse, terms)
+		if matches > best {
+			best = matches
+			hit.Verse = i + 1
+			hit.Snippet = snippet
+		}
+	}
+
+	return hit, true
+}
+
+// highlight оборачивает совпавшие слова в <mark></mark> и возвращает число совпадений.
+func highlight(text string, terms []string) (string, int) {
+	var b strings.Builder
+	matches := 0
+
+	word := func(w string) {
+		lower := strings.ToLower(w)
+		if slices.ContainsFunc(terms, func(t string) bool { return strings.HasPrefix(lower, t) }) {
+			matches++
+			b.WriteString("<mark>" + w + "</mark>")
+			return
+		}
+		b.WriteString(w)
+	}
+
+	start := -1
+	for i, c := range text {
+		if isWordRune(c) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			word(text[start:i])
+			start = -1
+		}
+		b.WriteRune(c)
+	}
+	if start >= 0 {
+		word(text[start:])
+	}
+
+	return b.String(), matches
+}
+
+func searchTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(c rune) bool { return !isWordRune(c) })
+}
+
+func countMatches(tokens []string, term string) int {
+	n := 0
+	for _, token := range tokens {
+		if strings.HasPrefix(token, term) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// wordSimilarity приближает word_similarity из pg_trgm: доля триграмм запроса,
+// которые встречаются в строке.
+func wordSimilarity(query, s string) float64 {
+	q := trigrams(query)
+	if len(q) == 0 {
+		return 0
+	}
+
+	t := trigrams(s)
+	common := 0
+	for trgm := range q {
+		if t[trgm] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(q))
+}
+
+// trigrams строит множество триграмм, как pg_trgm: каждое слово в нижнем регистре
+// дополняется двумя пробелами в начале и одним в конце.
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range searchTokens(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *artistRepository) Restore(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	artist, ok := r.trashedArtists[id]
+	if !ok {
+		return storage.ErrArtistNotFound
+	}
+	if r.nameTaken(artist.Name, 0) {
+		return storage.ErrArtistExists
+	}
+
+	deletedAt := artist.DeletedAt.Time
+	artist.DeletedAt = gorm.DeletedAt{}
+	if err := r.record(ctx, models.AuditArtist, id, models.AuditRestore, nil, artist); err != nil {
+		return err
+	}
+	delete(r.trashedArtists, id)
+	r.artists[id] = artist
+
+	for songID, song := range r.trashedSongs {
+		if song.ArtistID == id && song.DeletedAt.Time.Equal(deletedAt) {
+			r.restoreSong(songID)
+		}
+	}
+
+	return nil
+}
+
+func (r *songRepository) Restore(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	song, ok := r.trashedSongs[id]
+	if !ok {
+		return storage.ErrSongNotFound
+	}
+	if _, ok := r.artists[song.ArtistID]; !ok {
+		return storage.ErrArtistDeleted
+	}
+	if err := r.record(ctx, models.AuditSong, id, models.AuditRestore, nil, song); err != nil {
+		return err
+	}
+	r.restoreSong(id)
+
+	return nil
+}
+
+// restoreSong возвращает песню из корзины. Вызывается под блокировкой.
+func (s *Storage) restoreSong(id uint) {
+	song := s.trashedSongs[id]
+	song.DeletedAt = gorm.DeletedAt{}
+	song.UpdatedAt = time.Now().UTC()
+	delete(s.trashedSongs, id)
+	s.songs[id] = song
+}
+
+type trashRepository struct {
+	*Storage
+}
+
+func (r *trashRepository) List(_ context.Context, f storage.TrashFilter) (storage.TrashPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []storage.TrashItem
+	if f.Type == "" || f.Type == storage.TrashArtist {
+		for _, artist := range r.trashedArtists {
+			items = append(items, storage.TrashItem{
+				Type: storage.TrashArtist, ID: artist.ID, Name: artist.Name, DeletedAt: artist.DeletedAt.Time,
+			})
+		}
+	}
+	if f.Type == "" || f.Type == storage.TrashSong {
+		for _, song := range r.trashedSongs {
+			artistID := song.ArtistID
+			items = append(items, storage.TrashItem{
+				Type: storage.TrashSong, ID: song.ID, Name: song.Name, ArtistID: &artistID, DeletedAt: song.DeletedAt.Time,
+			})
+		}
+	}
+
+	// Тот же порядок, что и в pgsql: deleted_at DESC, type, id
+	slices.SortFunc(items, func(a, b storage.TrashItem) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+	})
+
+	page := storage.TrashPage{Total: int64(len(items))}
+	items = items[min(f.Offset, len(items)):]
+	page.Items = append(make([]storage.TrashItem, 0, f.Limit), items[:min(f.Limit, len(items))]...)
+
+	return page, nil
+}
+
+func (r *trashRepository) Purge(ctx context.Context, before time.Time) (storage.PurgeResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var res storage.PurgeResult
+	for _, song := range sortedByID(r.trashedSongs, func(s models.Song) uint { return s.ID }) {
+		if !song.DeletedAt.Time.Before(before) {
+			continue
+		}
+		if err := r.record(ctx, models.AuditSong, song.ID, models.AuditPurge, song, nil); err != nil {
+			return res, err
+		}
+		r.deleteSong(song.ID)
+		res.Songs++
+		if song.Cover != nil {
+			res.Covers = append(res.Covers, *song.Cover)
+		}
+	}
+	for _, artist := range sortedByID(r.trashedArtists, func(a models.Artist) uint { return a.ID }) {
+		if !artist.DeletedAt.Time.Before(before) || r.artistReferenced(artist.ID) {
+			continue
+		}
+		if err := r.record(ctx, models.AuditArtist, artist.ID, models.AuditPurge, artist, nil); err != nil {
+			return res, err
+		}
+		r.purgeArtist(artist.ID)
+		res.Artists++
+		if artist.Cover != nil {
+			res.Covers = append(res.Covers, *artist.Cover)
+		}
+	}
+
+	return res, nil
+}
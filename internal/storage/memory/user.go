@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+)
+
+type userRepository struct {
+	*Storage
+}
+
+func (r *userRepository) List(_ context.Context, f storage.UserFilter) (storage.UserPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]models.User, 0)
+	for _, user := range sortedByID(r.users, func(u models.User) uint { return u.ID }) {
+		if f.Role == "" || user.Role == f.Role {
+			matched = append(matched, user)
+		}
+	}
+
+	from := min(f.Offset, len(matched))
+	return storage.UserPage{
+		Users: matched[from:min(from+f.Limit, len(matched))],
+		Total: int64(len(matched)),
+	}, nil
+}
+
+func (r *userRepository) Get(_ context.Context, id uint) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) GetByEmail(_ context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return models.User{}, storage.ErrUserNotFound
+}
+
+func (r *userRepository) Create(_ context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Email == user.Email {
+			return storage.ErrUserExists
+		}
+	}
+
+	now := time.Now().UTC()
+	r.lastUserID++
+	user.ID = r.lastUserID
+	user.CreatedAt, user.UpdatedAt = now, now
+	r.users[user.ID] = *user
+
+	return nil
+}
+
+func (r *userRepository) SetRole(_ context.Context, id uint, role models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.Role = role
+	user.UpdatedAt = time.Now().UTC()
+	r.users[id] = user
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AlbumRepository реализует storage.AlbumRepository поверх GORM.
+type AlbumRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+func (r *AlbumRepository) List(ctx context.Context, f storage.AlbumFilter) (storage.AlbumPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.Album{})
+	if f.ArtistID != 0 {
+		q = q.Where("artist_id = ?", f.ArtistID)
+	}
+	if f.ReleaseType != "" {
+		q = q.Where("release_type = ?", f.ReleaseType)
+	}
+	q = q.Session(&gorm.Session{})
+
+	var page storage.AlbumPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.AlbumPage{}, fmt.Errorf("count albums: %w", err)
+	}
+
+	page.Albums = make([]models.Album, 0, f.Limit)
+	err := q.Order("release_date DESC NULLS LAST, id DESC").Limit(f.Limit).Offset(f.Offset).Find(&page.Albums).Error
+	if err != nil {
+		return storage.AlbumPage{}, fmt.Errorf("list albums: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *AlbumRepository) Get(ctx context.Context, id uint) (models.Album, error) {
+	var album models.Album
+	if err := r.db.WithContext(ctx).First(&album, id).Error; err != nil {
+		return models.Album{}, albumError(id, err)
+	}
+
+	return album, nil
+}
+
+func (r *AlbumRepository) GetWithTracks(ctx context.Context, id uint) (models.Album, error) {
+	var album models.Album
+	err := r.db.WithContext(ctx).
+		Preload("Artist").
+		Preload("Tracks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("disc_number, track_number, id")
+		}).
+		First(&album, id).Error
+	if err != nil {
+		return models.Album{}, albumError(id, err)
+	}
+
+	return album, nil
+}
+
+func (r *AlbumRepository) Create(ctx context.Context, album *models.Album) error {
+	if err := r.db.WithContext(ctx).Omit("Artist", "Tracks").Create(album).Error; err != nil {
+		return fmt.Errorf("create album: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *AlbumRepository) Update(ctx context.Context, album *models.Album) error {
+	res := r.db.WithContext(ctx).Model(album).
+		Select("title", "artist_id", "release_type", "release_date", "cover_url", "updated_at").
+		Updates(album)
+	if res.Error != nil {
+		return fmt.Errorf("update album %d: %w", album.ID, translateError(res.Error))
+	}
+	if res.RowsAffected == 0 {
+		return storage.ErrAlbumNotFound
+	}
+
+	return nil
+}
+
+// Delete отвязывает песни альбома, в том числе песни в корзине, внешним ключом
+// ON DELETE SET NULL и записывает в журнал изменение каждой из них.
+func (r *AlbumRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Album{}, id).Error; err != nil {
+			return err
+		}
+
+		var before []models.Song
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("album_id = ?", id).Order("id").Find(&before).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.Album{}, id).Error; err != nil {
+			return err
+		}
+
+		for _, song := range before {
+			after := song
+			after.AlbumID, after.DiscNumber, after.TrackNumber = nil, nil, nil
+			if err := record(ctx, tx, r.rec, models.AuditSong, song.ID, models.AuditUpdate, song, after); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return storage.ErrAlbumNotFound
+		}
+		return fmt.Errorf("delete album %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// SetTracks меняет позиции в одной транзакции. Уникальность позиции проверяется
+// отложенным ограничением при коммите, поэтому треки можно менять местами. Затронутые
+// песни читаются под блокировкой до изменения и перечитываются после, чтобы записать
+// в журнал изменение позиции каждой из них.
+func (r *AlbumRepository) SetTracks(ctx context.Context, albumID uint, tracks []storage.Track) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var album models.Album
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&album, albumID).Error; err != nil {
+			return albumError(albumID, err)
+		}
+
+		keep := make([]uint, len(tracks))
+		for i, t := range tracks {
+			keep[i] = t.SongID
+		}
+
+		var before []models.Song
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("album_id = ? OR id IN ?", albumID, keep).Order("id").Find(&before).Error
+		if err != nil {
+			return err
+		}
+
+		unlink := tx.Model(&models.Song{}).Where("album_id = ?", albumID)
+		if len(keep) > 0 {
+			unlink = unlink.Where("id NOT IN ?", keep)
+		}
+		err = unlink.Updates(map[string]any{"album_id": nil, "disc_number": nil, "track_number": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, t := range tracks {
+			res := tx.Model(&models.Song{}).Where("id = ?", t.SongID).Updates(map[string]any{
+				"album_id":     albumID,
+				"disc_number":  t.DiscNumber,
+				"track_number": t.TrackNumber,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("song %d: %w", t.SongID, storage.ErrSongNotFound)
+			}
+		}
+
+		return recordSongs(ctx, tx, r.rec, before)
+	})
+	if err != nil {
+		return fmt.Errorf("set album %d tracks: %w", albumID, translateError(err))
+	}
+
+	return nil
+}
+
+// recordSongs перечитывает песни, прочитанные до изменения под блокировкой, и записывает
+// в журнал изменение каждой; песни без изменений не записываются.
+func recordSongs(ctx context.Context, tx *gorm.DB, rec storage.Recorder, before []models.Song) error {
+	if rec == nil || len(before) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(before))
+	for i, song := range before {
+		ids[i] = song.ID
+	}
+
+	var after []models.Song
+	if err := tx.Where("id IN ?", ids).Order("id").Find(&after).Error; err != nil {
+		return err
+	}
+	for i := range after {
+		if err := record(ctx, tx, rec, models.AuditSong, after[i].ID, models.AuditUpdate, before[i], after[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func albumError(id uint, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrAlbumNotFound
+	}
+
+	return fmt.Errorf("get album %d: %w", id, err)
+}
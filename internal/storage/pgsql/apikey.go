@@ -0,0 +1,109 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// APIKeyRepository реализует storage.APIKeyRepository поверх GORM.
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	if err := r.db.WithContext(ctx).Order("id").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("list API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Get(ctx context.Context, id uint) (models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		return models.APIKey{}, apiKeyError(fmt.Sprintf("get API key %d", id), err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return models.APIKey{}, apiKeyError("get API key by prefix", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		return fmt.Errorf("create API key: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) Rotate(ctx context.Context, id uint, next *models.APIKey, oldExpiresAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old models.APIKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrAPIKeyNotFound
+			}
+			return err
+		}
+		if old.Expired(time.Now()) {
+			return storage.ErrAPIKeyExpired
+		}
+
+		if err := tx.Model(&old).Update("expires_at", oldExpiresAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rotate API key %d: %w", id, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.APIKey{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("delete API key %d: %w", id, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Touch не меняет updated_at: использование ключа не считается его изменением.
+func (r *APIKeyRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("touch API key %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func apiKeyError(op string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
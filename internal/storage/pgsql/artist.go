@@ -0,0 +1,223 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArtistRepository реализует storage.ArtistRepository поверх GORM.
+type ArtistRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+func (r *ArtistRepository) List(ctx context.Context) ([]models.Artist, error) {
+	artists := make([]models.Artist, 0)
+	if err := r.db.WithContext(ctx).Order("id").Find(&artists).Error; err != nil {
+		return nil, fmt.Errorf("list artists: %w", err)
+	}
+
+	return artists, nil
+}
+
+func (r *ArtistRepository) Get(ctx context.Context, id uint) (models.Artist, error) {
+	var artist models.Artist
+	if err := r.db.WithContext(ctx).First(&artist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Artist{}, storage.ErrArtistNotFound
+		}
+		return models.Artist{}, fmt.Errorf("get artist %d: %w", id, err)
+	}
+
+	return artist, nil
+}
+
+func (r *ArtistRepository) GetWithSongs(ctx context.Context, id uint, withDetails bool) (models.Artist, error) {
+	var artist models.Artist
+	if err := preloadSongs(r.db.WithContext(ctx), withDetails).First(&artist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Artist{}, storage.ErrArtistNotFound
+		}
+		return models.Artist{}, fmt.Errorf("get artist %d with songs: %w", id, err)
+	}
+
+	return artist, nil
+}
+
+func (r *ArtistRepository) Create(ctx context.Context, artist *models.Artist) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(artist).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditArtist, artist.ID, models.AuditCreate, nil, *artist)
+	})
+	if err != nil {
+		return artistWriteError("create artist", err)
+	}
+
+	return nil
+}
+
+func (r *ArtistRepository) Update(ctx context.Context, artist *models.Artist) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockArtist(tx, artist.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(artist).Select("name", "is_group").Updates(artist).Error; err != nil {
+			return err
+		}
+
+		after := before
+		after.Name, after.IsGroup = artist.Name, artist.IsGroup
+
+		return record(ctx, tx, r.rec, models.AuditArtist, artist.ID, models.AuditUpdate, before, after)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrArtistNotFound) {
+			return err
+		}
+		return artistWriteError(fmt.Sprintf("update artist %d", artist.ID), err)
+	}
+
+	return nil
+}
+
+// SetCover читает прежнюю обложку под блокировкой строки, поэтому одновременные замены
+// выполняются по очереди и каждая получает обложку, которую заменила именно она.
+func (r *ArtistRepository) SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error) {
+	var before models.Artist
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if before, err = lockArtist(tx, id); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Artist{ID: id}).Select("cover").Updates(&models.Artist{Cover: cover}).Error; err != nil {
+			return err
+		}
+
+		after := before
+		after.Cover = cover
+
+		return record(ctx, tx, r.rec, models.AuditArtist, id, models.AuditUpdate, before, after)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrArtistNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("set cover of artist %d: %w", id, translateError(err))
+	}
+
+	return before.Cover, nil
+}
+
+// Delete ставит артисту и его песням одно и то же время удаления: по нему Restore
+// отличает песни, удалённые вместе с артистом, от удалённых раньше. В журнал попадает
+// только удаление артиста: его песни уходят в корзину и возвращаются вместе с ним.
+func (r *ArtistRepository) Delete(ctx context.Context, id uint) error {
+	now := time.Now().UTC()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockArtist(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Artist{}).Where("id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Song{}).Where("artist_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditArtist, id, models.AuditDelete, before, nil)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrArtistNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete artist %d: %w", id, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *ArtistRepository) Restore(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var artist models.Artist
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").First(&artist, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrArtistNotFound
+			}
+			return err
+		}
+
+		deletedAt := artist.DeletedAt.Time
+		if err := tx.Unscoped().Model(&artist).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&models.Song{}).
+			Where("artist_id = ? AND deleted_at = ?", id, deletedAt).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditArtist, id, models.AuditRestore, nil, artist)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrArtistNotFound) {
+			return err
+		}
+		return artistWriteError(fmt.Sprintf("restore artist %d", id), err)
+	}
+
+	return nil
+}
+
+// lockArtist читает живого артиста под блокировкой строки до конца транзакции.
+func lockArtist(tx *gorm.DB, id uint) (models.Artist, error) {
+	var artist models.Artist
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&artist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Artist{}, storage.ErrArtistNotFound
+		}
+		return models.Artist{}, err
+	}
+
+	return artist, nil
+}
+
+// artistWriteError переводит ошибку записи артиста в ошибки пакета storage.
+func artistWriteError(op string, err error) error {
+	err = translateError(err)
+	if errors.Is(err, storage.ErrAlreadyExists) {
+		return fmt.Errorf("%w: %w", storage.ErrArtistExists, err)
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// preloadSongs подгружает песни (и детали) отдельными запросами с IN по всем артистам сразу.
+func preloadSongs(db *gorm.DB, withDetails bool) *gorm.DB {
+	db = db.Preload("Songs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("songs.id")
+	})
+	if withDetails {
+		db = db.Preload("Songs.SongDetail")
+	}
+
+	return db
+}
@@ -0,0 +1,64 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository реализует storage.AuditRepository поверх GORM.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func (r *AuditRepository) Append(ctx context.Context, entry *models.AuditEntry) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("append audit entry: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, f storage.AuditFilter) (storage.AuditPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.AuditEntry{})
+	if f.Entity != "" {
+		q = q.Where("entity = ?", f.Entity)
+		if f.EntityID != 0 {
+			q = q.Where("entity_id = ?", f.EntityID)
+		}
+	}
+	q = q.Session(&gorm.Session{})
+
+	var page storage.AuditPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.AuditPage{}, fmt.Errorf("count audit entries: %w", err)
+	}
+
+	page.Entries = make([]models.AuditEntry, 0, f.Limit)
+	if err := q.Order("id DESC").Limit(f.Limit).Offset(f.Offset).Find(&page.Entries).Error; err != nil {
+		return storage.AuditPage{}, fmt.Errorf("list audit entries: %w", err)
+	}
+
+	return page, nil
+}
+
+// record добавляет запись журнала аудита в транзакции изменения, если хранилище ведёт журнал.
+// Ошибка записи откатывает транзакцию вместе с изменением.
+func record(
+	ctx context.Context, tx *gorm.DB, rec storage.Recorder,
+	entity models.AuditEntity, id uint, action models.AuditAction, before, after any,
+) error {
+	if rec == nil {
+		return nil
+	}
+
+	entry, err := rec.Entry(ctx, entity, id, action, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	return tx.Create(entry).Error
+}
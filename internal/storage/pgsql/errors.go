@@ -0,0 +1,54 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"music-lib/internal/storage"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок PostgreSQL, см. https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	codeUniqueViolation     = "23505"
+	codeForeignKeyViolation = "23503"
+	codeNotNullViolation    = "23502"
+	codeCheckViolation      = "23514"
+	codeStringTooLong       = "22001"
+	codeInvalidDatetime     = "22007"
+	codeDatetimeOverflow    = "22008"
+)
+
+// constraintErrors уточняет ошибку по имени нарушенного ограничения из миграций.
+var constraintErrors = map[string]error{
+	"uq_artist_name":      storage.ErrArtistExists,
+	"uq_song_album_track": storage.ErrTrackTaken,
+	"uq_user_email":       storage.ErrUserExists,
+	// Соавтор добавляется без предварительной проверки плейлиста и пользователя
+	"fk_collaborator_playlist": storage.ErrPlaylistNotFound,
+	"fk_collaborator_user":     storage.ErrUserNotFound,
+}
+
+// translateError оборачивает ошибку PostgreSQL в соответствующую ошибку пакета storage.
+// Исходная ошибка остаётся в цепочке для логирования.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if sentinel, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+
+	switch pgErr.Code {
+	case codeUniqueViolation:
+		return fmt.Errorf("%w: %w", storage.ErrAlreadyExists, err)
+	case codeForeignKeyViolation:
+		return fmt.Errorf("%w: %w", storage.ErrForeignKey, err)
+	case codeNotNullViolation, codeCheckViolation, codeStringTooLong, codeInvalidDatetime, codeDatetimeOverflow:
+		return fmt.Errorf("%w: %w", storage.ErrConstraint, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,82 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// exportFetchSize - строк, которые выгрузка забирает из курсора за раз.
+const exportFetchSize = 500
+
+// ExportRepository реализует storage.ExportRepository серверным курсором PostgreSQL.
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func (r *ExportRepository) Songs(ctx context.Context, f storage.SongFilter, fn func(storage.SongRow) error) error {
+	q := r.db.Model(&models.Song{}).
+		Select(`songs.id, songs.name, songs.artist_id, artists.name AS artist_name,
+			songs.album_id, albums.title AS album_title, songs.disc_number, songs.track_number,
+			song_details.release_date, song_details.link, song_details.text`).
+		Joins("JOIN artists ON artists.id = songs.artist_id").
+		Joins("LEFT JOIN albums ON albums.id = songs.album_id").
+		Joins("LEFT JOIN song_details ON song_details.song_id = songs.id")
+	q = filterSongs(q, r.db, f).Order(songOrder(f))
+
+	if err := export(ctx, r.db, q, fn); err != nil {
+		return fmt.Errorf("export songs: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExportRepository) Artists(ctx context.Context, fn func(storage.ArtistRow) error) error {
+	songs := r.db.Model(&models.Song{}).Select("COUNT(*)").Where("songs.artist_id = artists.id")
+	q := r.db.Model(&models.Artist{}).
+		Select("artists.id, artists.name, artists.is_group, (?) AS songs", songs).
+		Order("artists.id")
+
+	if err := export(ctx, r.db, q, fn); err != nil {
+		return fmt.Errorf("export artists: %w", err)
+	}
+
+	return nil
+}
+
+// export открывает на запрос q курсор в читающей транзакции и передаёт строки в fn,
+// забирая их из курсора по exportFetchSize. Транзакция REPEATABLE READ держит один
+// снимок данных на всю выгрузку.
+func export[T any](ctx context.Context, db *gorm.DB, q *gorm.DB, fn func(T) error) error {
+	// DryRun только собирает SQL и параметры, не выполняя запрос
+	stmt := q.Session(&gorm.Session{DryRun: true}).Find(&[]T{}).Statement
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Параметры в SQL уже пронумерованы как $1, $2..., поэтому запрос уходит в
+		// соединение транзакции напрямую, минуя подстановку GORM
+		declare := "DECLARE export_cursor NO SCROLL CURSOR FOR " + stmt.SQL.String()
+		if _, err := tx.Statement.ConnPool.ExecContext(ctx, declare, stmt.Vars...); err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH %d FROM export_cursor", exportFetchSize)
+		for {
+			rows := make([]T, 0, exportFetchSize)
+			if err := tx.Raw(fetch).Scan(&rows).Error; err != nil {
+				return err
+			}
+			for _, row := range rows {
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			if len(rows) < exportFetchSize {
+				return nil
+			}
+		}
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
@@ -0,0 +1,154 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDryRun откатывает транзакцию пробного импорта.
+var errDryRun = errors.New("dry run")
+
+// ImportRepository реализует storage.ImportRepository поверх GORM.
+type ImportRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+// Import сохраняет каждую запись во вложенной транзакции: GORM делает её точкой сохранения,
+// и ошибка записи откатывает только её вместе с её записями журнала аудита.
+func (r *ImportRepository) Import(ctx context.Context, records []storage.ImportRecord, dryRun bool) ([]storage.ImportResult, error) {
+	results := make([]storage.ImportResult, 0, len(records))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rec := range records {
+			var res storage.ImportResult
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return r.importRecord(ctx, tx, rec, &res)
+			})
+			if err != nil {
+				recErr := storage.RecordError(translateError(err))
+				if recErr == nil {
+					return err
+				}
+				res = storage.ImportResult{Err: recErr}
+			}
+			results = append(results, res)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, fmt.Errorf("import %d records: %w", len(records), err)
+	}
+
+	return results, nil
+}
+
+func (r *ImportRepository) importRecord(ctx context.Context, tx *gorm.DB, rec storage.ImportRecord, res *storage.ImportResult) error {
+	var artist models.Artist
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", rec.Artist).Limit(1).Find(&artist).Error
+	if err != nil {
+		return err
+	}
+	switch {
+	case artist.ID == 0:
+		artist = models.Artist{Name: rec.Artist, IsGroup: rec.IsGroup != nil && *rec.IsGroup}
+		if err := tx.Omit("Songs").Create(&artist).Error; err != nil {
+			return err
+		}
+		if err := record(ctx, tx, r.rec, models.AuditArtist, artist.ID, models.AuditCreate, nil, artist); err != nil {
+			return err
+		}
+		res.Artist = storage.ImportCreated
+	case rec.IsGroup != nil && *rec.IsGroup != artist.IsGroup:
+		before := artist
+		if err := tx.Model(&artist).Update("is_group", *rec.IsGroup).Error; err != nil {
+			return err
+		}
+		if err := record(ctx, tx, r.rec, models.AuditArtist, artist.ID, models.AuditUpdate, before, artist); err != nil {
+			return err
+		}
+		res.Artist = storage.ImportUpdated
+	default:
+		res.Artist = storage.ImportSkipped
+	}
+	res.ArtistID = artist.ID
+
+	if rec.Song == "" {
+		return nil
+	}
+
+	// Блокировка песни упорядочивает импорт с одновременными сохранениями деталей и нумерацию версий
+	var song models.Song
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("artist_id = ? AND name = ?", artist.ID, rec.Song).
+		Order("id").Limit(1).Find(&song).Error
+	if err != nil {
+		return err
+	}
+	if song.ID == 0 {
+		song = models.Song{Name: rec.Song, ArtistID: artist.ID}
+		if err := tx.Omit("Artist", "SongDetail", "Credits").Create(&song).Error; err != nil {
+			return err
+		}
+		if err := record(ctx, tx, r.rec, models.AuditSong, song.ID, models.AuditCreate, nil, song); err != nil {
+			return err
+		}
+		res.Song = storage.ImportCreated
+	} else {
+		res.Song = storage.ImportSkipped
+	}
+	res.SongID = song.ID
+
+	if rec.Detail == nil {
+		return nil
+	}
+
+	var existing models.SongDetail
+	if err := tx.Where("song_id = ?", song.ID).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	detail := *rec.Detail
+	detail.SongID = song.ID
+	action, before := models.AuditCreate, any(nil)
+	if existing.ID != 0 {
+		if sameDetail(existing, detail) {
+			res.Detail = storage.ImportSkipped
+			return nil
+		}
+		detail.ID = existing.ID
+		detail.CreatedAt = existing.CreatedAt
+		action, before = models.AuditUpdate, existing
+	}
+
+	if err := tx.Save(&detail).Error; err != nil {
+		return err
+	}
+	if err := record(ctx, tx, r.rec, models.AuditSongDetail, song.ID, action, before, detail); err != nil {
+		return err
+	}
+	res.Detail = storage.ImportCreated
+	if existing.ID != 0 {
+		res.Detail = storage.ImportUpdated
+		if existing.Text == detail.Text {
+			return nil
+		}
+	}
+
+	return appendRevision(ctx, tx, song.ID, detail.Text)
+}
+
+// sameDetail сравнивает поля деталей, которые задаёт импорт.
+func sameDetail(a, b models.SongDetail) bool {
+	return a.Text == b.Text && a.ReleaseDate.Equal(b.ReleaseDate) && a.Link == b.Link
+}
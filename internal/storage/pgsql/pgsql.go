@@ -5,30 +5,25 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"music-lib/internal/config"
+	"music-lib/internal/storage"
 )
 
 // Storage содержит экземпляр *gorm.DB для взаимодействия с базой данных.
 type Storage struct {
 	DB *gorm.DB
+
+	// rec записывает изменения каталога в журнал аудита; nil - журнал не ведётся.
+	rec storage.Recorder
 }
 
 // New инициализирует новое подключение к базе данных с использованием GORM.
 // Принимает структуру Config, содержащую параметры подключения.
 func New(cfg *config.Config) *Storage {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
-		cfg.DBHost,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-		cfg.DBPort,
-		cfg.DBSSLMode,
-	)
-
 	// Конфигурация GORM
 	gormConfig := &gorm.Config{}
 
 	// Подключение к базе данных с использованием GORM
-	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL()), gormConfig)
 	if err != nil {
 		panic("failed to connect to database using GORM:" + err.Error())
 	}
@@ -58,3 +53,68 @@ func (s *Storage) Close() error {
 	}
 	return sqlDB.Close()
 }
+
+// WithAudit возвращает хранилище на том же подключении, записывающее изменения каталога через rec.
+func (s *Storage) WithAudit(rec storage.Recorder) storage.Storage {
+	return &Storage{DB: s.DB, rec: rec}
+}
+
+// Artists возвращает репозиторий артистов.
+func (s *Storage) Artists() storage.ArtistRepository {
+	return &ArtistRepository{db: s.DB, rec: s.rec}
+}
+
+// Songs возвращает репозиторий песен.
+func (s *Storage) Songs() storage.SongRepository {
+	return &SongRepository{db: s.DB, rec: s.rec}
+}
+
+// SongDetails возвращает репозиторий деталей песен.
+func (s *Storage) SongDetails() storage.SongDetailRepository {
+	return &SongDetailRepository{db: s.DB, rec: s.rec}
+}
+
+// Albums возвращает репозиторий альбомов.
+func (s *Storage) Albums() storage.AlbumRepository {
+	return &AlbumRepository{db: s.DB, rec: s.rec}
+}
+
+// Playlists возвращает репозиторий плейлистов.
+func (s *Storage) Playlists() storage.PlaylistRepository {
+	return &PlaylistRepository{db: s.DB}
+}
+
+// Users возвращает репозиторий пользователей.
+func (s *Storage) Users() storage.UserRepository {
+	return &UserRepository{db: s.DB}
+}
+
+// APIKeys возвращает репозиторий API-ключей.
+func (s *Storage) APIKeys() storage.APIKeyRepository {
+	return &APIKeyRepository{db: s.DB}
+}
+
+// Trash возвращает репозиторий корзины.
+func (s *Storage) Trash() storage.TrashRepository {
+	return &TrashRepository{db: s.DB, rec: s.rec}
+}
+
+// Audit возвращает журнал аудита.
+func (s *Storage) Audit() storage.AuditRepository {
+	return &AuditRepository{db: s.DB}
+}
+
+// Import возвращает репозиторий массового импорта.
+func (s *Storage) Import() storage.ImportRepository {
+	return &ImportRepository{db: s.DB, rec: s.rec}
+}
+
+// Export возвращает репозиторий выгрузки каталога.
+func (s *Storage) Export() storage.ExportRepository {
+	return &ExportRepository{db: s.DB}
+}
+
+// Search возвращает репозиторий полнотекстового поиска.
+func (s *Storage) Search() storage.SearchRepository {
+	return &SearchRepository{db: s.DB}
+}
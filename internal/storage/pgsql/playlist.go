@@ -0,0 +1,285 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/lib/rank"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlaylistRepository реализует storage.PlaylistRepository поверх GORM.
+type PlaylistRepository struct {
+	db *gorm.DB
+}
+
+func (r *PlaylistRepository) List(ctx context.Context, f storage.PlaylistFilter) (storage.PlaylistPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.Playlist{})
+	if f.MemberID != 0 {
+		q = q.Where(
+			"owner_id = ? OR EXISTS (SELECT 1 FROM playlist_collaborators pc WHERE pc.playlist_id = playlists.id AND pc.user_id = ?)",
+			f.MemberID, f.MemberID,
+		)
+	}
+	if f.Visibility != "" {
+		q = q.Where("visibility = ?", f.Visibility)
+	}
+	q = q.Session(&gorm.Session{})
+
+	var page storage.PlaylistPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.PlaylistPage{}, fmt.Errorf("count playlists: %w", err)
+	}
+
+	page.Playlists = make([]models.Playlist, 0, f.Limit)
+	err := q.Order("updated_at DESC, id DESC").Limit(f.Limit).Offset(f.Offset).Find(&page.Playlists).Error
+	if err != nil {
+		return storage.PlaylistPage{}, fmt.Errorf("list playlists: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *PlaylistRepository) Get(ctx context.Context, id uint) (models.Playlist, error) {
+	var playlist models.Playlist
+	if err := r.db.WithContext(ctx).Preload("Collaborators").First(&playlist, id).Error; err != nil {
+		return models.Playlist{}, playlistError(id, err)
+	}
+
+	return playlist, nil
+}
+
+func (r *PlaylistRepository) GetWithItems(ctx context.Context, id uint) (models.Playlist, error) {
+	var playlist models.Playlist
+	err := r.db.WithContext(ctx).
+		Preload("Collaborators").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Items.Song").
+		First(&playlist, id).Error
+	if err != nil {
+		return models.Playlist{}, playlistError(id, err)
+	}
+
+	return playlist, nil
+}
+
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *models.Playlist) error {
+	playlist.Version = 1
+	if err := r.db.WithContext(ctx).Omit("Items", "Collaborators").Create(playlist).Error; err != nil {
+		return fmt.Errorf("create playlist: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) Update(ctx context.Context, playlist *models.Playlist, version int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, playlist.ID, version); err != nil {
+			return err
+		}
+
+		return tx.Model(playlist).Select("name", "description", "visibility").Updates(playlist).Error
+	})
+	if err != nil {
+		return fmt.Errorf("update playlist %d: %w", playlist.ID, translateError(err))
+	}
+	playlist.Version = version + 1
+
+	return nil
+}
+
+func (r *PlaylistRepository) Delete(ctx context.Context, id uint, version int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, id, version); err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Playlist{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("delete playlist %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) AddItem(
+	ctx context.Context, playlistID uint, version int, item *models.PlaylistItem, at storage.Placement,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, playlistID, version); err != nil {
+			return err
+		}
+		// Внешний ключ пропустил бы песню из корзины; блокировка не даёт удалить её до конца транзакции
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").First(&models.Song{}, item.SongID).Error
+		if err != nil {
+			return songError(item.SongID, err)
+		}
+
+		position, err := itemPosition(tx, playlistID, 0, at)
+		if err != nil {
+			return err
+		}
+
+		item.ID = 0
+		item.PlaylistID = playlistID
+		item.Position = position
+		return tx.Omit("Song").Create(item).Error
+	})
+	if err != nil {
+		return fmt.Errorf("add item to playlist %d: %w", playlistID, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) MoveItem(
+	ctx context.Context, playlistID uint, version int, itemID uint, at storage.Placement,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, playlistID, version); err != nil {
+			return err
+		}
+		if _, err := findItem(tx, playlistID, itemID); err != nil {
+			return err
+		}
+
+		position, err := itemPosition(tx, playlistID, itemID, at)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.PlaylistItem{}).Where("id = ?", itemID).Update("position", position).Error
+	})
+	if err != nil {
+		return fmt.Errorf("move item %d in playlist %d: %w", itemID, playlistID, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) RemoveItem(ctx context.Context, playlistID uint, version int, itemID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, playlistID, version); err != nil {
+			return err
+		}
+
+		res := tx.Where("playlist_id = ?", playlistID).Delete(&models.PlaylistItem{}, itemID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return storage.ErrPlaylistItemNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("remove item %d from playlist %d: %w", itemID, playlistID, err)
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) AddCollaborator(ctx context.Context, playlistID, userID uint) error {
+	collaborator := models.PlaylistCollaborator{PlaylistID: playlistID, UserID: userID}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&collaborator).Error
+	if err != nil {
+		return fmt.Errorf("add collaborator %d to playlist %d: %w", userID, playlistID, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) RemoveCollaborator(ctx context.Context, playlistID, userID uint) error {
+	res := r.db.WithContext(ctx).
+		Where("playlist_id = ? AND user_id = ?", playlistID, userID).
+		Delete(&models.PlaylistCollaborator{})
+	if res.Error != nil {
+		return fmt.Errorf("remove collaborator %d from playlist %d: %w", userID, playlistID, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return storage.ErrCollaboratorNotFound
+	}
+
+	return nil
+}
+
+// bumpVersion блокирует плейлист до конца транзакции, сверяет версию и увеличивает её.
+func bumpVersion(tx *gorm.DB, id uint, version int) error {
+	var playlist models.Playlist
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").First(&playlist, id).Error
+	if err != nil {
+		return playlistError(id, err)
+	}
+	if playlist.Version != version {
+		return storage.ErrVersionMismatch
+	}
+
+	return tx.Model(&playlist).UpdateColumns(map[string]any{
+		"version":    version + 1,
+		"updated_at": time.Now().UTC(),
+	}).Error
+}
+
+// itemPosition вычисляет позицию для элемента по месту at. Перемещаемый элемент exclude
+// не учитывается при поиске соседей.
+func itemPosition(tx *gorm.DB, playlistID, exclude uint, at storage.Placement) (string, error) {
+	items := tx.Model(&models.PlaylistItem{}).Where("playlist_id = ? AND id <> ?", playlistID, exclude)
+
+	var lower, upper string
+	switch {
+	case at.Before != 0:
+		anchor, err := findItem(tx, playlistID, at.Before)
+		if err != nil {
+			return "", err
+		}
+		upper = anchor.Position
+		err = items.Where("position < ?", upper).Select("COALESCE(MAX(position), '')").Scan(&lower).Error
+		if err != nil {
+			return "", err
+		}
+	case at.After != 0:
+		anchor, err := findItem(tx, playlistID, at.After)
+		if err != nil {
+			return "", err
+		}
+		lower = anchor.Position
+		err = items.Where("position > ?", lower).Select("COALESCE(MIN(position), '')").Scan(&upper).Error
+		if err != nil {
+			return "", err
+		}
+	default:
+		if err := items.Select("COALESCE(MAX(position), '')").Scan(&lower).Error; err != nil {
+			return "", err
+		}
+	}
+
+	return rank.Between(lower, upper)
+}
+
+func findItem(tx *gorm.DB, playlistID, itemID uint) (models.PlaylistItem, error) {
+	var item models.PlaylistItem
+	err := tx.Where("playlist_id = ?", playlistID).First(&item, itemID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.PlaylistItem{}, storage.ErrPlaylistItemNotFound
+		}
+		return models.PlaylistItem{}, err
+	}
+
+	return item, nil
+}
+
+func playlistError(id uint, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrPlaylistNotFound
+	}
+
+	return fmt.Errorf("get playlist %d: %w", id, err)
+}
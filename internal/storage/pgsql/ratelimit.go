@@ -0,0 +1,47 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"music-lib/internal/lib/ratelimit"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RateLimitStore хранит корзины ограничителя в PostgreSQL, чтобы лимит был общим
+// для всех реплик. Сам алгоритм выполняет функция rate_limit_take из миграций.
+type RateLimitStore struct {
+	db *gorm.DB
+}
+
+// RateLimits возвращает хранилище корзин ограничителя частоты запросов.
+func (s *Storage) RateLimits() *RateLimitStore {
+	return &RateLimitStore{db: s.DB}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var row struct {
+		OutAllowed bool
+		OutTokens  float64
+	}
+	err := s.db.WithContext(ctx).
+		Raw("SELECT out_allowed, out_tokens FROM rate_limit_take(?, ?, ?)", key, limit.Rate, float64(limit.Burst)).
+		Scan(&row).Error
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("take rate limit token: %w", err)
+	}
+
+	return ratelimit.NewResult(row.OutAllowed, row.OutTokens, limit), nil
+}
+
+// Purge удаляет корзины, к которым не обращались дольше idle. После этого срока
+// корзина в любом случае полна, так что её удаление ничего не меняет.
+func (s *RateLimitStore) Purge(ctx context.Context, idle time.Duration) (int64, error) {
+	res := s.db.WithContext(ctx).Exec("DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => ?)", idle.Seconds())
+	if res.Error != nil {
+		return 0, fmt.Errorf("purge rate limit buckets: %w", res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
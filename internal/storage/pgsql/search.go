@@ -0,0 +1,183 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"music-lib/internal/storage"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Запросы поиска написаны на SQL, поэтому записи из корзины (deleted_at) исключаются явно.
+// Песни удалённого артиста тоже в корзине, так что для JOIN с artists достаточно условия по songs.
+
+// searchConfigs - конфигурации текстового поиска Postgres для каждого языка запроса.
+// Колонка songs.search_vector построена сразу в russian и english (см. миграцию 2_search).
+var searchConfigs = map[storage.SearchLang][]string{
+	storage.SearchLangAny:     {"russian", "english"},
+	storage.SearchLangRussian: {"russian"},
+	storage.SearchLangEnglish: {"english"},
+}
+
+// headlineOptions подсвечивает все совпадения в куплете целиком.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+
+// SearchRepository реализует storage.SearchRepository на tsvector/GIN.
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func (r *SearchRepository) Search(ctx context.Context, q storage.SearchQuery) (storage.SearchPage, error) {
+	if q.Mode == storage.SearchModeFuzzy {
+		return r.fuzzy(ctx, q)
+	}
+
+	return r.fullText(ctx, q)
+}
+
+// fullText ранжирует песни по ts_rank_cd и подсвечивает куплет, лучше всего совпавший с запросом.
+// Куплеты нумеруются так же, как в GET /songs/{id}/lyrics.
+func (r *SearchRepository) fullText(ctx context.Context, q storage.SearchQuery) (storage.SearchPage, error) {
+	configs, ok := searchConfigs[q.Lang]
+	if !ok {
+		return storage.SearchPage{}, fmt.Errorf("search: unsupported language %q", q.Lang)
+	}
+
+	tsquery := joinConfigs(configs, "websearch_to_tsquery('%s', @q)")
+	verseVector := joinConfigs(configs, "to_tsvector('%s', p.verse)")
+
+	args := map[string]any{"q": q.Query, "limit": q.Limit, "offset": q.Offset}
+	db := r.db.WithContext(ctx)
+
+	var page storage.SearchPage
+	countSQL := fmt.Sprintf(`SELECT COUNT(*) FROM songs WHERE search_vector @@ (%s) AND deleted_at IS NULL`, tsquery)
+	if err := db.Raw(countSQL, args).Scan(&page.Total).Error; err != nil {
+		return storage.SearchPage{}, fmt.Errorf("count search results: %w", err)
+	}
+
+	searchSQL := fmt.Sprintf(`
+WITH sq AS (SELECT %[1]s AS q)
+SELECT s.id AS song_id,
+       s.name AS song,
+       a.id AS artist_id,
+       a.name AS artist,
+       ts_rank_cd(s.search_vector, sq.q) AS rank,
+       COALESCE(v.n, 0) AS verse,
+       COALESCE(ts_headline('%[3]s', v.verse, sq.q, '%[4]s'), '') AS snippet
+FROM songs s
+         CROSS JOIN sq
+         JOIN artists a ON a.id = s.artist_id
+         LEFT JOIN song_details d ON d.song_id = s.id
+         LEFT JOIN LATERAL (
+    SELECT p.n, p.verse
+    FROM (SELECT row_number() OVER (ORDER BY t.pos) AS n, btrim(t.part, E' \t\r\n') AS verse
+          FROM regexp_split_to_table(btrim(replace(d.text, E'\r\n', E'\n'), E' \t\r\n'), '\n[ \t]*\n\s*')
+                   WITH ORDINALITY AS t(part, pos)
+          WHERE btrim(t.part, E' \t\r\n') <> '') p
+    WHERE (%[2]s) @@ sq.q
+    ORDER BY ts_rank_cd(%[2]s, sq.q) DESC, p.n
+    LIMIT 1
+    ) v ON TRUE
+WHERE s.search_vector @@ sq.q
+  AND s.deleted_at IS NULL
+ORDER BY rank DESC, s.id
+LIMIT @limit OFFSET @offset`, tsquery, verseVector, configs[0], headlineOptions)
+
+	page.Hits = make([]storage.SearchHit, 0, q.Limit)
+	if err := db.Raw(searchSQL, args).Scan(&page.Hits).Error; err != nil {
+		return storage.SearchPage{}, fmt.Errorf("search songs: %w", err)
+	}
+
+	return page, nil
+}
+
+// fuzzy ищет песни, название или артист которых похожи на запрос по триграммам.
+func (r *SearchRepository) fuzzy(ctx context.Context, q storage.SearchQuery) (storage.SearchPage, error) {
+	const where = `FROM songs s JOIN artists a ON a.id = s.artist_id WHERE (@q <% s.name OR @q <% a.name) AND s.deleted_at IS NULL`
+	args := map[string]any{"q": q.Query, "limit": q.Limit, "offset": q.Offset}
+
+	page := storage.SearchPage{Hits: make([]storage.SearchHit, 0, q.Limit)}
+	err := r.withThreshold(ctx, q.Threshold, func(tx *gorm.DB) error {
+		if err := tx.Raw(`SELECT COUNT(*) `+where, args).Scan(&page.Total).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+SELECT s.id AS song_id,
+       s.name AS song,
+       a.id AS artist_id,
+       a.name AS artist,
+       GREATEST(word_similarity(@q, s.name), word_similarity(@q, a.name)) AS rank
+`+where+`
+ORDER BY rank DESC, s.id
+LIMIT @limit OFFSET @offset`, args).Scan(&page.Hits).Error
+	})
+	if err != nil {
+		return storage.SearchPage{}, fmt.Errorf("fuzzy search songs: %w", err)
+	}
+
+	return page, nil
+}
+
+// Suggest сравнивает запрос с частями названий (word_similarity), поэтому подходит
+// и для недописанного слова при автодополнении.
+func (r *SearchRepository) Suggest(ctx context.Context, q storage.SuggestQuery) (storage.Suggestions, error) {
+	args := map[string]any{"q": q.Query, "limit": q.Limit}
+	res := storage.Suggestions{
+		Artists: make([]storage.Suggestion, 0, q.Limit),
+		Songs:   make([]storage.Suggestion, 0, q.Limit),
+	}
+
+	err := r.withThreshold(ctx, q.Threshold, func(tx *gorm.DB) error {
+		err := tx.Raw(`
+SELECT id, name, word_similarity(@q, name) AS similarity
+FROM artists
+WHERE @q <% name
+  AND deleted_at IS NULL
+ORDER BY similarity DESC, id
+LIMIT @limit`, args).Scan(&res.Artists).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+SELECT s.id, s.name, a.id AS artist_id, a.name AS artist, word_similarity(@q, s.name) AS similarity
+FROM songs s
+         JOIN artists a ON a.id = s.artist_id
+WHERE @q <% s.name
+  AND s.deleted_at IS NULL
+ORDER BY similarity DESC, s.id
+LIMIT @limit`, args).Scan(&res.Songs).Error
+	})
+	if err != nil {
+		return storage.Suggestions{}, fmt.Errorf("suggest: %w", err)
+	}
+
+	return res, nil
+}
+
+// withThreshold выполняет fn в транзакции с заданным порогом оператора <%.
+// Порог задаётся через настройку pg_trgm, чтобы запросы использовали GIN-индексы по name.
+func (r *SearchRepository) withThreshold(ctx context.Context, threshold float64, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)`,
+			strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+		if err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
+
+// joinConfigs подставляет каждую конфигурацию в шаблон и объединяет результаты через ||.
+func joinConfigs(configs []string, format string) string {
+	parts := make([]string, len(configs))
+	for i, cfg := range configs {
+		parts[i] = fmt.Sprintf(format, cfg)
+	}
+
+	return "(" + strings.Join(parts, " || ") + ")"
+}
@@ -0,0 +1,396 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SongRepository реализует storage.SongRepository поверх GORM.
+type SongRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+// List возвращает страницу песен, удовлетворяющих фильтру, и общее количество совпадений.
+func (r *SongRepository) List(ctx context.Context, f storage.SongFilter) (storage.SongPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.Song{})
+	if f.Artist != "" {
+		q = q.Joins("JOIN artists ON artists.id = songs.artist_id")
+	}
+	if f.ReleasedFrom != nil || f.ReleasedTo != nil || f.HasLink != nil || f.Text != "" || f.Sort == storage.SongSortReleaseDate {
+		q = q.Joins("LEFT JOIN song_details ON song_details.song_id = songs.id")
+	}
+	q = filterSongs(q, r.db, f)
+
+	// Дальнейшие вызовы не должны менять общий statement
+	q = q.Session(&gorm.Session{})
+
+	var page storage.SongPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.SongPage{}, fmt.Errorf("count songs: %w", err)
+	}
+
+	q = q.Select("songs.*").Order(songOrder(f))
+	if f.WithDetails {
+		q = q.Preload("SongDetail")
+	}
+	if f.WithCredits {
+		q = preloadCredits(q)
+	}
+	if f.Cursor != 0 {
+		q = q.Where("songs.id > ?", f.Cursor)
+	} else if f.Offset > 0 {
+		q = q.Offset(f.Offset)
+	}
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	songs := make([]models.Song, 0, f.Limit+1)
+	if err := q.Limit(f.Limit + 1).Find(&songs).Error; err != nil {
+		return storage.SongPage{}, fmt.Errorf("list songs: %w", err)
+	}
+
+	if len(songs) > f.Limit {
+		songs = songs[:f.Limit]
+		if keyset(f) {
+			page.NextCursor = storage.EncodeCursor(songs[len(songs)-1].ID)
+		}
+	}
+	page.Songs = songs
+
+	return page, nil
+}
+
+func (r *SongRepository) Get(ctx context.Context, id uint) (models.Song, error) {
+	var song models.Song
+	if err := r.db.WithContext(ctx).First(&song, id).Error; err != nil {
+		return models.Song{}, songError(id, err)
+	}
+
+	return song, nil
+}
+
+func (r *SongRepository) GetWithDetails(ctx context.Context, id uint) (models.Song, error) {
+	var song models.Song
+	if err := r.db.WithContext(ctx).Preload("Artist").Preload("SongDetail").First(&song, id).Error; err != nil {
+		return models.Song{}, songError(id, err)
+	}
+
+	return song, nil
+}
+
+func (r *SongRepository) Create(ctx context.Context, song *models.Song) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Artist", "SongDetail", "Credits").Create(song).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditSong, song.ID, models.AuditCreate, nil, *song)
+	})
+	if err != nil {
+		return fmt.Errorf("create song: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *SongRepository) Update(ctx context.Context, song *models.Song) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockSong(tx, song.ID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(song).
+			Select("name", "artist_id", "album_id", "disc_number", "track_number", "updated_at").
+			Updates(song).Error
+		if err != nil {
+			return err
+		}
+
+		after := before
+		after.Name, after.ArtistID = song.Name, song.ArtistID
+		after.AlbumID, after.DiscNumber, after.TrackNumber = song.AlbumID, song.DiscNumber, song.TrackNumber
+
+		return record(ctx, tx, r.rec, models.AuditSong, song.ID, models.AuditUpdate, before, after)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSongNotFound) {
+			return err
+		}
+		return fmt.Errorf("update song %d: %w", song.ID, translateError(err))
+	}
+
+	return nil
+}
+
+// SetCover читает прежнюю обложку под блокировкой строки, как и ArtistRepository.SetCover.
+func (r *SongRepository) SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error) {
+	var before models.Song
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if before, err = lockSong(tx, id); err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Song{ID: id}).Select("cover", "updated_at").Updates(&models.Song{Cover: cover}).Error
+		if err != nil {
+			return err
+		}
+
+		after := before
+		after.Cover = cover
+
+		return record(ctx, tx, r.rec, models.AuditSong, id, models.AuditUpdate, before, after)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSongNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("set cover of song %d: %w", id, translateError(err))
+	}
+
+	return before.Cover, nil
+}
+
+func (r *SongRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockSong(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.Song{}, id).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditSong, id, models.AuditDelete, before, nil)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSongNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete song %d: %w", id, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *SongRepository) Restore(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var song models.Song
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").First(&song, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrSongNotFound
+			}
+			return err
+		}
+
+		var alive int64
+		if err := tx.Model(&models.Artist{}).Where("id = ?", song.ArtistID).Count(&alive).Error; err != nil {
+			return err
+		}
+		if alive == 0 {
+			return storage.ErrArtistDeleted
+		}
+
+		if err := tx.Unscoped().Model(&song).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditSong, id, models.AuditRestore, nil, song)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSongNotFound) || errors.Is(err, storage.ErrArtistDeleted) {
+			return err
+		}
+		return fmt.Errorf("restore song %d: %w", id, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *SongRepository) Credits(ctx context.Context, songID uint) ([]models.SongCredit, error) {
+	credits := make([]models.SongCredit, 0)
+	err := r.db.WithContext(ctx).Preload("Artist").
+		Where("song_id = ?", songID).
+		Order("position, artist_id, role").
+		Find(&credits).Error
+	if err != nil {
+		return nil, fmt.Errorf("list song %d credits: %w", songID, err)
+	}
+
+	return credits, nil
+}
+
+// SetCredits сначала меняет основного артиста песни: триггер songs_primary_credit
+// правит при этом титры, которые затем полностью заменяются. Внешний ключ не отличает
+// артиста в корзине от живого, поэтому артисты титров проверяются под блокировкой FOR SHARE:
+// до конца транзакции их нельзя удалить в корзину.
+func (r *SongRepository) SetCredits(ctx context.Context, songID uint, credits []models.SongCredit) error {
+	primary := slices.IndexFunc(credits, func(c models.SongCredit) bool { return c.Role == models.CreditPrimary })
+	if primary < 0 {
+		return fmt.Errorf("set song %d credits: no primary artist: %w", songID, storage.ErrConstraint)
+	}
+
+	artistIDs := make([]uint, 0, len(credits))
+	for _, c := range credits {
+		if !slices.Contains(artistIDs, c.ArtistID) {
+			artistIDs = append(artistIDs, c.ArtistID)
+		}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var live []uint
+		err := tx.Model(&models.Artist{}).Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id IN ?", artistIDs).Pluck("id", &live).Error
+		if err != nil {
+			return err
+		}
+		if len(live) != len(artistIDs) {
+			return storage.ErrForeignKey
+		}
+
+		before, err := lockSong(tx, songID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Song{}).Where("id = ?", songID).Update("artist_id", credits[primary].ArtistID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("song_id = ?", songID).Delete(&models.SongCredit{}).Error; err != nil {
+			return err
+		}
+
+		rows := make([]models.SongCredit, len(credits))
+		for i, c := range credits {
+			rows[i] = models.SongCredit{SongID: songID, ArtistID: c.ArtistID, Role: c.Role, Position: i + 1}
+		}
+
+		if err := tx.Omit("Artist").Create(&rows).Error; err != nil {
+			return err
+		}
+
+		after := before
+		after.ArtistID = credits[primary].ArtistID
+
+		return record(ctx, tx, r.rec, models.AuditSong, songID, models.AuditUpdate, before, after)
+	})
+	if err != nil {
+		return fmt.Errorf("set song %d credits: %w", songID, translateError(err))
+	}
+
+	return nil
+}
+
+// filterSongs добавляет условия фильтра песен. Условия по имени артиста и деталям ссылаются
+// на таблицы artists и song_details: их присоединяет вызывающий.
+func filterSongs(q, db *gorm.DB, f storage.SongFilter) *gorm.DB {
+	if f.Name != "" {
+		q = q.Where("songs.name ILIKE ?", likePattern(f.Name))
+	}
+	if f.ArtistID != 0 {
+		q = q.Where("songs.artist_id = ?", f.ArtistID)
+	}
+	if f.Artist != "" {
+		q = q.Where("artists.name ILIKE ?", likePattern(f.Artist))
+	}
+
+	if f.ReleasedFrom != nil {
+		q = q.Where("song_details.release_date >= ?", *f.ReleasedFrom)
+	}
+	if f.ReleasedTo != nil {
+		q = q.Where("song_details.release_date <= ?", *f.ReleasedTo)
+	}
+	if f.HasLink != nil {
+		if *f.HasLink {
+			q = q.Where("COALESCE(song_details.link, '') <> ''")
+		} else {
+			q = q.Where("COALESCE(song_details.link, '') = ''")
+		}
+	}
+	if f.Text != "" {
+		q = q.Where("song_details.text ILIKE ?", likePattern(f.Text))
+	}
+
+	if f.CreditArtistID != 0 || f.CreditRole != "" {
+		credits := db.Table("song_credits").Select("1").Where("song_credits.song_id = songs.id")
+		if f.CreditArtistID != 0 {
+			credits = credits.Where("song_credits.artist_id = ?", f.CreditArtistID)
+		}
+		if f.CreditRole != "" {
+			credits = credits.Where("song_credits.role = ?", f.CreditRole)
+		}
+		q = q.Where("EXISTS (?)", credits)
+	}
+
+	return q
+}
+
+// preloadCredits добавляет к песням титры с артистами.
+func preloadCredits(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Credits", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, artist_id, role")
+		}).
+		Preload("Credits.Artist")
+}
+
+// songOrder строит ORDER BY; songs.id в конце делает порядок детерминированным.
+func songOrder(f storage.SongFilter) string {
+	dir := "ASC"
+	if f.Desc {
+		dir = "DESC"
+	}
+
+	switch f.Sort {
+	case storage.SongSortName:
+		return fmt.Sprintf("songs.name %s, songs.id %s", dir, dir)
+	case storage.SongSortReleaseDate:
+		return fmt.Sprintf("song_details.release_date %s NULLS LAST, songs.id %s", dir, dir)
+	default:
+		return "songs.id " + dir
+	}
+}
+
+// keyset сообщает, можно ли продолжить выборку курсором по id.
+func keyset(f storage.SongFilter) bool {
+	return (f.Sort == "" || f.Sort == storage.SongSortID) && !f.Desc
+}
+
+// lockSong читает живую песню под блокировкой строки до конца транзакции.
+func lockSong(tx *gorm.DB, id uint) (models.Song, error) {
+	var song models.Song
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&song, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Song{}, storage.ErrSongNotFound
+		}
+		return models.Song{}, err
+	}
+
+	return song, nil
+}
+
+func songError(id uint, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrSongNotFound
+	}
+
+	return fmt.Errorf("get song %d: %w", id, err)
+}
+
+// likePattern экранирует спецсимволы LIKE и оборачивает строку для поиска подстроки.
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
@@ -0,0 +1,152 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/lib/actor"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// SongDetailRepository реализует storage.SongDetailRepository поверх GORM.
+type SongDetailRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+func (r *SongDetailRepository) GetBySongID(ctx context.Context, songID uint) (models.SongDetail, error) {
+	var detail models.SongDetail
+	if err := r.db.WithContext(ctx).Where("song_id = ?", songID).First(&detail).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.SongDetail{}, storage.ErrSongDetailNotFound
+		}
+		return models.SongDetail{}, fmt.Errorf("get song %d detail: %w", songID, err)
+	}
+
+	return detail, nil
+}
+
+func (r *SongDetailRepository) Save(ctx context.Context, detail *models.SongDetail) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Блокировка песни упорядочивает одновременные изменения деталей и нумерацию версий
+		if _, err := lockSong(tx, detail.SongID); err != nil {
+			if errors.Is(err, storage.ErrSongNotFound) {
+				return storage.ErrForeignKey
+			}
+			return err
+		}
+
+		var existing models.SongDetail
+		if err := tx.Where("song_id = ?", detail.SongID).Limit(1).Find(&existing).Error; err != nil {
+			return err
+		}
+		action, before := models.AuditCreate, any(nil)
+		if existing.ID != 0 {
+			detail.ID = existing.ID
+			detail.CreatedAt = existing.CreatedAt
+			action, before = models.AuditUpdate, existing
+		}
+
+		if err := tx.Save(detail).Error; err != nil {
+			return err
+		}
+		if err := record(ctx, tx, r.rec, models.AuditSongDetail, detail.SongID, action, before, *detail); err != nil {
+			return err
+		}
+		if existing.ID != 0 && existing.Text == detail.Text {
+			return nil
+		}
+
+		return appendRevision(ctx, tx, detail.SongID, detail.Text)
+	})
+	if err != nil {
+		return fmt.Errorf("save song %d detail: %w", detail.SongID, translateError(err))
+	}
+
+	return nil
+}
+
+func (r *SongDetailRepository) DeleteBySongID(ctx context.Context, songID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockSong(tx, songID); err != nil {
+			if errors.Is(err, storage.ErrSongNotFound) {
+				return storage.ErrSongDetailNotFound
+			}
+			return err
+		}
+
+		var before models.SongDetail
+		if err := tx.Where("song_id = ?", songID).Limit(1).Find(&before).Error; err != nil {
+			return err
+		}
+		if before.ID == 0 {
+			return storage.ErrSongDetailNotFound
+		}
+
+		if err := tx.Delete(&before).Error; err != nil {
+			return err
+		}
+
+		return record(ctx, tx, r.rec, models.AuditSongDetail, songID, models.AuditDelete, before, nil)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSongDetailNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete song %d detail: %w", songID, err)
+	}
+
+	return nil
+}
+
+func (r *SongDetailRepository) Revisions(ctx context.Context, songID uint, limit, offset int) (storage.RevisionPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.LyricsRevision{}).Where("song_id = ?", songID).Session(&gorm.Session{})
+
+	var page storage.RevisionPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.RevisionPage{}, fmt.Errorf("count song %d revisions: %w", songID, err)
+	}
+
+	page.Revisions = make([]models.LyricsRevision, 0, limit)
+	err := q.Omit("text").Order("number DESC").Limit(limit).Offset(offset).Find(&page.Revisions).Error
+	if err != nil {
+		return storage.RevisionPage{}, fmt.Errorf("list song %d revisions: %w", songID, err)
+	}
+
+	return page, nil
+}
+
+func (r *SongDetailRepository) Revision(ctx context.Context, songID uint, number int) (models.LyricsRevision, error) {
+	var rev models.LyricsRevision
+	err := r.db.WithContext(ctx).Where("song_id = ? AND number = ?", songID, number).First(&rev).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.LyricsRevision{}, storage.ErrRevisionNotFound
+		}
+		return models.LyricsRevision{}, fmt.Errorf("get song %d revision %d: %w", songID, number, err)
+	}
+
+	return rev, nil
+}
+
+// appendRevision добавляет следующую версию текста песни. Вызывается в транзакции,
+// заблокировавшей песню.
+func appendRevision(ctx context.Context, tx *gorm.DB, songID uint, text string) error {
+	rev := models.LyricsRevision{SongID: songID, Text: text}
+	err := tx.Model(&models.LyricsRevision{}).Select("COALESCE(MAX(number), 0) + 1").
+		Where("song_id = ?", songID).Scan(&rev.Number).Error
+	if err != nil {
+		return err
+	}
+
+	if a := actor.From(ctx); a.UserID != 0 {
+		rev.UserID = &a.UserID
+	} else if a.APIKeyID != 0 {
+		rev.APIKeyID = &a.APIKeyID
+	}
+
+	return tx.Create(&rev).Error
+}
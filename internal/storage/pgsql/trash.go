@@ -0,0 +1,118 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TrashRepository реализует storage.TrashRepository поверх GORM.
+type TrashRepository struct {
+	db  *gorm.DB
+	rec storage.Recorder
+}
+
+// trashSQL объединяет удалённых артистов и песни; фильтр по виду применяется снаружи.
+const trashSQL = `
+SELECT 'artist' AS type, id, name, NULL::BIGINT AS artist_id, deleted_at
+FROM artists
+WHERE deleted_at IS NOT NULL
+UNION ALL
+SELECT 'song' AS type, id, name, artist_id, deleted_at
+FROM songs
+WHERE deleted_at IS NOT NULL`
+
+func (r *TrashRepository) List(ctx context.Context, f storage.TrashFilter) (storage.TrashPage, error) {
+	q := r.db.WithContext(ctx).Table("(?) AS trash", gorm.Expr(trashSQL))
+	if f.Type != "" {
+		q = q.Where("type = ?", f.Type)
+	}
+	q = q.Session(&gorm.Session{})
+
+	var page storage.TrashPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.TrashPage{}, fmt.Errorf("count trash: %w", err)
+	}
+
+	page.Items = make([]storage.TrashItem, 0, f.Limit)
+	err := q.Order("deleted_at DESC, type, id").Limit(f.Limit).Offset(f.Offset).Scan(&page.Items).Error
+	if err != nil {
+		return storage.TrashPage{}, fmt.Errorf("list trash: %w", err)
+	}
+
+	return page, nil
+}
+
+// Purge сначала удаляет песни, затем артистов. Артист, на которого ещё ссылается
+// оставшаяся песня (например, удалённая в корзину позже него) или альбом, остаётся
+// в корзине до следующей очистки: каскад внешних ключей удалил бы живые записи.
+// Титры чужих песен с таким артистом отвязываются явно. Удаляемые записи читаются
+// под блокировкой, и каждая попадает в журнал аудита.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (storage.PurgeResult, error) {
+	var res storage.PurgeResult
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var songs []models.Song
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at < ?", before).Order("id").Find(&songs).Error
+		if err != nil {
+			return err
+		}
+		if len(songs) > 0 {
+			if err := tx.Unscoped().Delete(&songs).Error; err != nil {
+				return err
+			}
+		}
+		for _, song := range songs {
+			if err := record(ctx, tx, r.rec, models.AuditSong, song.ID, models.AuditPurge, song, nil); err != nil {
+				return err
+			}
+			if song.Cover != nil {
+				res.Covers = append(res.Covers, *song.Cover)
+			}
+		}
+		res.Songs = int64(len(songs))
+
+		var artists []models.Artist
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM songs WHERE songs.artist_id = artists.id)").
+			Where("NOT EXISTS (SELECT 1 FROM albums WHERE albums.artist_id = artists.id)").
+			Order("id").Find(&artists).Error
+		if err != nil || len(artists) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(artists))
+		for i, artist := range artists {
+			ids[i] = artist.ID
+		}
+		if err := tx.Where("artist_id IN ?", ids).Delete(&models.SongCredit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&artists).Error; err != nil {
+			return err
+		}
+		for _, artist := range artists {
+			if err := record(ctx, tx, r.rec, models.AuditArtist, artist.ID, models.AuditPurge, artist, nil); err != nil {
+				return err
+			}
+			if artist.Cover != nil {
+				res.Covers = append(res.Covers, *artist.Cover)
+			}
+		}
+		res.Artists = int64(len(artists))
+
+		return nil
+	})
+	if err != nil {
+		return storage.PurgeResult{}, fmt.Errorf("purge trash: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-lib/internal/models"
+	"music-lib/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository реализует storage.UserRepository поверх GORM.
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func (r *UserRepository) List(ctx context.Context, f storage.UserFilter) (storage.UserPage, error) {
+	q := r.db.WithContext(ctx).Model(&models.User{})
+	if f.Role != "" {
+		q = q.Where("role = ?", f.Role)
+	}
+	q = q.Session(&gorm.Session{})
+
+	var page storage.UserPage
+	if err := q.Count(&page.Total).Error; err != nil {
+		return storage.UserPage{}, fmt.Errorf("count users: %w", err)
+	}
+
+	page.Users = make([]models.User, 0, f.Limit)
+	if err := q.Order("id").Limit(f.Limit).Offset(f.Offset).Find(&page.Users).Error; err != nil {
+		return storage.UserPage{}, fmt.Errorf("list users: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *UserRepository) Get(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return models.User{}, userError(fmt.Sprintf("get user %d", id), err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return models.User{}, userError("get user by email", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		return fmt.Errorf("create user: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id uint, role models.Role) error {
+	res := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("role", role)
+	if res.Error != nil {
+		return fmt.Errorf("set user %d role: %w", id, translateError(res.Error))
+	}
+	if res.RowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func userError(op string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrUserNotFound
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,95 @@
+package storage
+
+// SearchLang - язык запроса полнотекстового поиска. Определяет правила стемминга.
+type SearchLang string
+
+const (
+	// SearchLangAny ищет одновременно по русским и английским словоформам.
+	SearchLangAny     SearchLang = ""
+	SearchLangRussian SearchLang = "ru"
+	SearchLangEnglish SearchLang = "en"
+)
+
+// SearchMode - способ сопоставления запроса с песнями.
+type SearchMode string
+
+const (
+	// SearchModeFullText ищет по словоформам в названии, имени артиста и тексте.
+	SearchModeFullText SearchMode = "fulltext"
+	// SearchModeFuzzy сравнивает запрос с названием песни и именем артиста по триграммам
+	// и находит их даже с опечатками.
+	SearchModeFuzzy SearchMode = "fuzzy"
+)
+
+// DefaultSimilarityThreshold - порог сходства нечёткого поиска по умолчанию, как в pg_trgm.
+const DefaultSimilarityThreshold = 0.3
+
+// SearchQuery - параметры поиска песен.
+type SearchQuery struct {
+	// Query - строка запроса. В полнотекстовом режиме поддерживаются "фразы в кавычках", or и -исключение.
+	Query string
+	// Mode по умолчанию - SearchModeFullText.
+	Mode SearchMode
+	// Lang учитывается только в полнотекстовом режиме.
+	Lang SearchLang
+	// Threshold - минимальное сходство от 0 до 1 для SearchModeFuzzy.
+	Threshold float64
+	Limit     int
+	Offset    int
+}
+
+// SearchHit - найденная песня. В полнотекстовом режиме совпадение в названии весит больше,
+// чем в имени артиста, а совпадение в имени артиста - больше, чем в тексте.
+// В нечётком режиме Rank - сходство запроса с названием песни или именем артиста.
+type SearchHit struct {
+	SongID   uint    `json:"song_id"`
+	Song     string  `json:"song"`
+	ArtistID uint    `json:"artist_id"`
+	Artist   string  `json:"artist"`
+	Rank     float64 `json:"rank"`
+	// Verse - номер куплета с лучшим совпадением, 0 если текст песни не совпал.
+	Verse int `json:"verse,omitempty"`
+	// Snippet - текст этого куплета, найденные слова обёрнуты в <mark></mark>.
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// SearchPage - страница результатов поиска по убыванию релевантности.
+type SearchPage struct {
+	Hits  []SearchHit
+	Total int64
+}
+
+// SuggestQuery - параметры подсказок для автодополнения.
+type SuggestQuery struct {
+	Query     string
+	Threshold float64
+	// Limit ограничивает отдельно артистов и песни.
+	Limit int
+}
+
+// Suggestion - артист или песня, похожие на запрос. Для артиста ArtistID и Artist не заполняются.
+type Suggestion struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	ArtistID   uint    `json:"artist_id,omitempty"`
+	Artist     string  `json:"artist,omitempty"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Suggestions - подсказки по убыванию сходства.
+type Suggestions struct {
+	Artists []Suggestion
+	Songs   []Suggestion
+}
+
+// Best возвращает самую похожую подсказку среди артистов и песен.
+func (s Suggestions) Best() (Suggestion, bool) {
+	var best Suggestion
+	for _, list := range [][]Suggestion{s.Artists, s.Songs} {
+		if len(list) > 0 && list[0].Similarity > best.Similarity {
+			best = list[0]
+		}
+	}
+
+	return best, best.ID != 0
+}
@@ -1,9 +1,242 @@
 package storage
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"music-lib/internal/models"
+	"time"
+)
 
 var (
-	ErrUserNotFound = errors.New("user not found")
-	ErrAppNotFound  = errors.New("app not found")
-	ErrUserExists   = errors.New("user already exists")
+	ErrArtistNotFound     = errors.New("artist not found")
+	ErrSongNotFound       = errors.New("song not found")
+	ErrSongDetailNotFound = errors.New("song detail not found")
+	ErrRevisionNotFound   = errors.New("lyrics revision not found")
+	ErrAlbumNotFound      = errors.New("album not found")
+	// ErrArtistDeleted - песню нельзя восстановить, пока её артист в корзине.
+	ErrArtistDeleted = errors.New("artist is deleted")
+
+	ErrPlaylistNotFound     = errors.New("playlist not found")
+	ErrPlaylistItemNotFound = errors.New("playlist item not found")
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	// ErrVersionMismatch - запись изменилась после того, как клиент её прочитал.
+	ErrVersionMismatch = errors.New("version mismatch")
+
+	ErrUserNotFound   = errors.New("user not found")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyExpired - истёкший ключ нельзя ротировать: замена получила бы тот же истёкший срок.
+	ErrAPIKeyExpired = errors.New("API key has expired")
+
+	ErrArtistExists  = errors.New("artist already exists")
+	ErrUserExists    = errors.New("user with this email already exists")
+	ErrAlreadyExists = errors.New("record already exists")
+	// ErrTrackTaken - на этом диске альбома уже есть трек с таким номером.
+	ErrTrackTaken = errors.New("album track position already taken")
+
+	// ErrForeignKey - запись ссылается на несуществующую сущность.
+	ErrForeignKey = errors.New("referenced record does not exist")
+	// ErrConstraint - значение нарушает ограничение схемы (NOT NULL, CHECK, длина поля).
+	ErrConstraint = errors.New("value violates constraint")
 )
+
+// ArtistRepository управляет артистами.
+type ArtistRepository interface {
+	List(ctx context.Context) ([]models.Artist, error)
+	Get(ctx context.Context, id uint) (models.Artist, error)
+	// GetWithSongs возвращает артиста с песнями, а при withDetails - и с их деталями.
+	GetWithSongs(ctx context.Context, id uint, withDetails bool) (models.Artist, error)
+	Create(ctx context.Context, artist *models.Artist) error
+	Update(ctx context.Context, artist *models.Artist) error
+	// SetCover заменяет обложку артиста и возвращает прежнюю; nil убирает обложку или
+	// означает, что её не было. Одновременные замены упорядочены, поэтому каждая прежняя
+	// обложка возвращается ровно одному вызову. Файлы обложек репозиторий не трогает.
+	SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error)
+	// Delete переносит в корзину артиста вместе со всеми его песнями.
+	Delete(ctx context.Context, id uint) error
+	// Restore возвращает артиста из корзины вместе с песнями, удалёнными вместе с ним.
+	// Песни, удалённые раньше артиста, остаются в корзине.
+	Restore(ctx context.Context, id uint) error
+}
+
+// SongRepository управляет песнями.
+type SongRepository interface {
+	List(ctx context.Context, filter SongFilter) (SongPage, error)
+	Get(ctx context.Context, id uint) (models.Song, error)
+	// GetWithDetails возвращает песню с заполненными Artist и SongDetail.
+	GetWithDetails(ctx context.Context, id uint) (models.Song, error)
+	Create(ctx context.Context, song *models.Song) error
+	Update(ctx context.Context, song *models.Song) error
+	// SetCover заменяет обложку песни и возвращает прежнюю, как ArtistRepository.SetCover.
+	SetCover(ctx context.Context, id uint, cover *models.Cover) (*models.Cover, error)
+	// Delete переносит песню в корзину. Детали и титры остаются до окончательного удаления.
+	Delete(ctx context.Context, id uint) error
+	// Restore возвращает песню из корзины. Если её артист тоже в корзине, возвращает ErrArtistDeleted.
+	Restore(ctx context.Context, id uint) error
+	// Credits возвращает титры песни с артистами в порядке Position.
+	Credits(ctx context.Context, songID uint) ([]models.SongCredit, error)
+	// SetCredits заменяет титры песни; порядок среди credits становится порядком в титрах.
+	// Первый артист с ролью primary становится Song.ArtistID.
+	SetCredits(ctx context.Context, songID uint, credits []models.SongCredit) error
+}
+
+// SongDetailRepository управляет деталями песен. У песни может быть не больше одной записи деталей.
+// Каждый новый текст песни сохраняется как версия; версии переживают удаление деталей.
+type SongDetailRepository interface {
+	GetBySongID(ctx context.Context, songID uint) (models.SongDetail, error)
+	// Save создаёт детали песни или обновляет существующие по SongID. Если текст изменился,
+	// в той же транзакции добавляется версия текста от имени исполнителя из actor.From.
+	Save(ctx context.Context, detail *models.SongDetail) error
+	DeleteBySongID(ctx context.Context, songID uint) error
+	// Revisions возвращает версии текста песни без самих текстов, последние первыми.
+	Revisions(ctx context.Context, songID uint, limit, offset int) (RevisionPage, error)
+	// Revision возвращает версию текста по номеру.
+	Revision(ctx context.Context, songID uint, number int) (models.LyricsRevision, error)
+}
+
+// SearchRepository выполняет поиск по названиям песен, именам артистов и текстам.
+type SearchRepository interface {
+	Search(ctx context.Context, q SearchQuery) (SearchPage, error)
+	// Suggest подбирает артистов и песни с названием, похожим на запрос, в том числе с опечатками.
+	Suggest(ctx context.Context, q SuggestQuery) (Suggestions, error)
+}
+
+// Track - позиция песни в треклисте альбома.
+type Track struct {
+	SongID      uint
+	DiscNumber  int
+	TrackNumber int
+}
+
+// AlbumRepository управляет альбомами и их треклистами.
+type AlbumRepository interface {
+	List(ctx context.Context, filter AlbumFilter) (AlbumPage, error)
+	Get(ctx context.Context, id uint) (models.Album, error)
+	// GetWithTracks возвращает альбом с артистом и треками по порядку дисков и номеров.
+	GetWithTracks(ctx context.Context, id uint) (models.Album, error)
+	Create(ctx context.Context, album *models.Album) error
+	Update(ctx context.Context, album *models.Album) error
+	// Delete удаляет альбом. Его песни остаются в каталоге без альбома.
+	Delete(ctx context.Context, id uint) error
+	// SetTracks заменяет треклист альбома: перечисленные песни получают новые позиции,
+	// остальные песни альбома отвязываются от него.
+	SetTracks(ctx context.Context, albumID uint, tracks []Track) error
+}
+
+// PlaylistRepository управляет плейлистами. Изменяющие методы принимают версию,
+// которую видел клиент, и возвращают ErrVersionMismatch, если плейлист с тех пор изменился.
+// Каждое изменение плейлиста или его треков увеличивает версию на единицу.
+type PlaylistRepository interface {
+	List(ctx context.Context, filter PlaylistFilter) (PlaylistPage, error)
+	// Get возвращает плейлист с соавторами.
+	Get(ctx context.Context, id uint) (models.Playlist, error)
+	// GetWithItems возвращает плейлист с соавторами и треками по порядку.
+	GetWithItems(ctx context.Context, id uint) (models.Playlist, error)
+	Create(ctx context.Context, playlist *models.Playlist) error
+	// Update сохраняет название, описание и видимость и записывает в playlist новую версию.
+	Update(ctx context.Context, playlist *models.Playlist, version int) error
+	Delete(ctx context.Context, id uint, version int) error
+
+	// AddItem добавляет трек в указанное место и заполняет item.ID и item.Position.
+	// Песни нет или она в корзине - ErrSongNotFound.
+	AddItem(ctx context.Context, playlistID uint, version int, item *models.PlaylistItem, at Placement) error
+	// MoveItem переносит трек, меняя только его собственную позицию.
+	MoveItem(ctx context.Context, playlistID uint, version int, itemID uint, at Placement) error
+	RemoveItem(ctx context.Context, playlistID uint, version int, itemID uint) error
+
+	// AddCollaborator разрешает пользователю редактировать треки. Повторный вызов ничего не меняет,
+	// неизвестный пользователь - ErrUserNotFound.
+	AddCollaborator(ctx context.Context, playlistID, userID uint) error
+	RemoveCollaborator(ctx context.Context, playlistID, userID uint) error
+}
+
+// UserRepository управляет учётными записями. Email передаётся уже в нижнем регистре.
+type UserRepository interface {
+	List(ctx context.Context, filter UserFilter) (UserPage, error)
+	Get(ctx context.Context, id uint) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	SetRole(ctx context.Context, id uint, role models.Role) error
+}
+
+// APIKeyRepository управляет API-ключами машинных клиентов.
+type APIKeyRepository interface {
+	// List возвращает все ключи по возрастанию id, включая истёкшие.
+	List(ctx context.Context) ([]models.APIKey, error)
+	Get(ctx context.Context, id uint) (models.APIKey, error)
+	// GetByPrefix ищет ключ по открытой части для проверки при аутентификации.
+	GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	// Rotate создаёт ключ next на замену ключа id, а старому ставит срок действия
+	// oldExpiresAt, чтобы клиенты успели перейти на новый ключ. Истёкший ключ -
+	// ErrAPIKeyExpired.
+	Rotate(ctx context.Context, id uint, next *models.APIKey, oldExpiresAt time.Time) error
+	Delete(ctx context.Context, id uint) error
+	// Touch записывает время последнего использования ключа.
+	Touch(ctx context.Context, id uint, at time.Time) error
+}
+
+// TrashRepository показывает и очищает корзину удалённых артистов и песен.
+// Удалённые записи не видны остальным репозиториям.
+type TrashRepository interface {
+	List(ctx context.Context, filter TrashFilter) (TrashPage, error)
+	// Purge окончательно удаляет записи, попавшие в корзину раньше before,
+	// вместе с деталями, титрами и треками плейлистов. Артист, на которого ещё
+	// ссылаются песни или альбомы, остаётся в корзине. В журнал аудита удалённые
+	// артисты и песни попадают с действием purge.
+	Purge(ctx context.Context, before time.Time) (PurgeResult, error)
+}
+
+// Recorder составляет записи журнала аудита. Хранилище, получившее его через WithAudit,
+// добавляет запись в той же транзакции, что и изменение, а состояние до изменения читает
+// под блокировкой строки. Если запись не удалась, изменение отменяется.
+type Recorder interface {
+	// Entry возвращает запись об операции над сущностью; before и after - её состояние
+	// до и после, nil - сущности нет. Если записывать нечего, возвращает nil.
+	Entry(ctx context.Context, entity models.AuditEntity, id uint, action models.AuditAction, before, after any) (*models.AuditEntry, error)
+}
+
+// AuditRepository хранит журнал аудита. Записи только добавляются.
+type AuditRepository interface {
+	// Append добавляет запись и заполняет entry.ID и entry.CreatedAt.
+	Append(ctx context.Context, entry *models.AuditEntry) error
+	// List возвращает записи, последние первыми.
+	List(ctx context.Context, filter AuditFilter) (AuditPage, error)
+}
+
+// ImportRepository сохраняет записи массового импорта каталога. Артисты ищутся по имени,
+// песни - по артисту и названию; чего нет, то создаётся.
+type ImportRepository interface {
+	// Import сохраняет пакет записей в одной транзакции и возвращает итог по каждой записи
+	// в том же порядке. Запись с ошибкой данных откатывается отдельно и получает Err,
+	// остальные сохраняются. При dryRun вся транзакция откатывается.
+	Import(ctx context.Context, records []ImportRecord, dryRun bool) ([]ImportResult, error)
+}
+
+// ExportRepository выгружает каталог построчно, не загружая его в память целиком.
+// Выгрузка видит каталог на момент начала. Ошибка fn прерывает выгрузку.
+type ExportRepository interface {
+	// Songs передаёт в fn песни, подходящие под фильтр, в порядке filter.Sort.
+	// Limit, Offset, Cursor, WithDetails и WithCredits не учитываются.
+	Songs(ctx context.Context, filter SongFilter, fn func(SongRow) error) error
+	// Artists передаёт в fn всех артистов по возрастанию id.
+	Artists(ctx context.Context, fn func(ArtistRow) error) error
+}
+
+// Storage предоставляет доступ ко всем репозиториям хранилища.
+type Storage interface {
+	Artists() ArtistRepository
+	Songs() SongRepository
+	SongDetails() SongDetailRepository
+	Albums() AlbumRepository
+	Playlists() PlaylistRepository
+	Users() UserRepository
+	APIKeys() APIKeyRepository
+	Trash() TrashRepository
+	Audit() AuditRepository
+	Import() ImportRepository
+	Export() ExportRepository
+	Search() SearchRepository
+	// WithAudit возвращает хранилище с теми же данными, которое записывает через rec
+	// изменения артистов, песен и деталей песен, включая треклисты альбомов и очистку корзины.
+	WithAudit(rec Recorder) Storage
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"music-lib/internal/models"
+	"time"
+)
+
+// TrashType - вид удалённой записи в корзине.
+type TrashType string
+
+const (
+	TrashArtist TrashType = "artist"
+	TrashSong   TrashType = "song"
+)
+
+// TrashFilter описывает параметры списка корзины. Пустой Type - записи всех видов.
+type TrashFilter struct {
+	Type TrashType
+
+	Limit  int
+	Offset int
+}
+
+// TrashItem - удалённый артист или песня.
+type TrashItem struct {
+	Type TrashType `json:"type"`
+	ID   uint      `json:"id"`
+	Name string    `json:"name"`
+	// ArtistID - артист песни; у артистов не заполняется.
+	ArtistID  *uint     `json:"artist_id,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashPage - страница корзины, последние удалённые записи первыми.
+type TrashPage struct {
+	Items []TrashItem
+	Total int64
+}
+
+// PurgeResult - сколько записей окончательно удалено из корзины.
+type PurgeResult struct {
+	Artists int64
+	Songs   int64
+	// Covers - обложки удалённых записей. Хранилище не трогает файлы: их удаляет
+	// вызывающий после того, как Purge вернул результат.
+	Covers []models.Cover
+}
@@ -0,0 +1,12 @@
+-- Записи из корзины удаляются окончательно: без deleted_at их не отличить от живых
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+DELETE FROM artists WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+DROP INDEX IF EXISTS idx_artists_deleted_at;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE artists
+    DROP COLUMN IF EXISTS deleted_at;
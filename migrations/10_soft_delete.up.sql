@@ -0,0 +1,11 @@
+-- Удалённые артисты и песни попадают в корзину и окончательно удаляются после срока хранения.
+-- Внешние ключи ON DELETE CASCADE срабатывают только при окончательном удалении.
+ALTER TABLE artists
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Частичные индексы нужны корзине и очистке; обычные запросы ищут deleted_at IS NULL
+CREATE INDEX IF NOT EXISTS idx_artists_deleted_at ON artists (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
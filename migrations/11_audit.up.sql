@@ -0,0 +1,41 @@
+-- Журнал аудита изменений каталога. Исполнитель хранится без внешних ключей,
+-- чтобы записи переживали удаление пользователей и ключей.
+CREATE TABLE IF NOT EXISTS audit_entries
+(
+    id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    entity      VARCHAR(32) NOT NULL,
+    entity_id   BIGINT      NOT NULL,
+    action      VARCHAR(16) NOT NULL,
+    user_id     BIGINT,
+    api_key_id  BIGINT,
+    request_id  VARCHAR(64),
+    changes     JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_audit_entity CHECK (entity IN ('artist', 'song', 'song_detail')),
+    CONSTRAINT chk_audit_action CHECK (action IN ('create', 'update', 'delete', 'restore'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_entries_entity ON audit_entries (entity, entity_id, id);
+
+-- Журнал только дополняется: изменение и удаление записей запрещены
+CREATE OR REPLACE FUNCTION audit_entries_append_only_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_entries is append-only';
+END
+$$;
+
+CREATE TRIGGER audit_entries_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_entries
+    FOR EACH ROW
+EXECUTE FUNCTION audit_entries_append_only_trigger();
+
+CREATE TRIGGER audit_entries_no_truncate
+    BEFORE TRUNCATE
+    ON audit_entries
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_entries_append_only_trigger();
@@ -0,0 +1,20 @@
+-- Версии текстов песен. Привязаны к песне, а не к деталям, чтобы история
+-- сохранялась при удалении и повторном создании деталей.
+CREATE TABLE IF NOT EXISTS lyrics_revisions
+(
+    id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    song_id    BIGINT      NOT NULL,
+    number     INT         NOT NULL,
+    text       TEXT        NOT NULL,
+    user_id    BIGINT,
+    api_key_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_lyrics_revision_song FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT uq_lyrics_revision_number UNIQUE (song_id, number),
+    CONSTRAINT chk_lyrics_revision_number CHECK (number >= 1)
+);
+
+-- Текущие тексты становятся первой версией без автора
+INSERT INTO lyrics_revisions (song_id, number, text)
+SELECT song_id, 1, text
+FROM song_details;
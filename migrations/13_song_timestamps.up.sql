@@ -0,0 +1,9 @@
+-- Модели Song и SongDetail пишут created_at и updated_at, но 1_init их не создаёт,
+-- и любая запись песни или деталей падала. Существующим строкам ставится время миграции.
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE song_details
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -1,3 +1,3 @@
-DROP TABLE IF EXISTS artists;
+DROP TABLE IF EXISTS song_details;
 DROP TABLE IF EXISTS songs;
-DROP TABLE IF EXISTS song_details;
\ No newline at end of file
+DROP TABLE IF EXISTS artists;
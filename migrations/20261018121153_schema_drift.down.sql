@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_songs_artist_id;
+
+ALTER TABLE song_details
+    DROP CONSTRAINT IF EXISTS uq_song_detail_song;
+
+DROP INDEX IF EXISTS uq_artist_name;
@@ -0,0 +1,13 @@
+-- Ограничения и индексы, которые модели GORM ожидают с 1_init, но SQL их не создавал
+-- (их находит migrator check).
+
+-- Имя артиста уникально среди неудалённых: артист в корзине не мешает создать нового
+-- с тем же именем, а его восстановление тогда вернёт конфликт. Если в базе уже есть
+-- артисты с одинаковыми именами, миграция упадёт: дубликаты нужно объединить вручную.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_artist_name ON artists (name) WHERE deleted_at IS NULL;
+
+-- У песни не больше одной записи деталей
+ALTER TABLE song_details
+    ADD CONSTRAINT uq_song_detail_song UNIQUE (song_id);
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_id ON songs (artist_id);
@@ -0,0 +1,5 @@
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS cover;
+
+ALTER TABLE artists
+    DROP COLUMN IF EXISTS cover;
@@ -0,0 +1,7 @@
+-- Обложки артистов и песен: ключи и адреса файлов в хранилище blob, см. models.Cover.
+-- Файлы удаляет приложение: при замене обложки и при окончательном удалении записи из корзины.
+ALTER TABLE artists
+    ADD COLUMN IF NOT EXISTS cover JSONB;
+
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS cover JSONB;
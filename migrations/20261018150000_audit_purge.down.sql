@@ -0,0 +1,4 @@
+-- Журнал только дополняется, поэтому записи purge остаются и ограничение проверяет только новые строки
+ALTER TABLE audit_entries
+    DROP CONSTRAINT IF EXISTS chk_audit_action,
+    ADD CONSTRAINT chk_audit_action CHECK (action IN ('create', 'update', 'delete', 'restore')) NOT VALID;
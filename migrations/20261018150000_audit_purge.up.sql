@@ -0,0 +1,4 @@
+-- Окончательное удаление из корзины записывается в журнал аудита отдельным действием
+ALTER TABLE audit_entries
+    DROP CONSTRAINT IF EXISTS chk_audit_action,
+    ADD CONSTRAINT chk_audit_action CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'));
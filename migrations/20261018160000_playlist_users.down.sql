@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_playlist_items_added_by;
+
+ALTER TABLE playlist_items
+    DROP CONSTRAINT IF EXISTS fk_item_added_by;
+
+ALTER TABLE playlist_collaborators
+    DROP CONSTRAINT IF EXISTS fk_collaborator_user;
+
+ALTER TABLE playlists
+    DROP CONSTRAINT IF EXISTS fk_playlist_owner;
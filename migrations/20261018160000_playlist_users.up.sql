@@ -0,0 +1,26 @@
+-- Владелец, соавторы и автор трека плейлиста ссылаются на пользователей.
+-- Плейлисты и соавторы без пользователя удаляются, треки переходят к владельцу плейлиста.
+DELETE
+FROM playlists
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE users.id = playlists.owner_id);
+
+DELETE
+FROM playlist_collaborators
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE users.id = playlist_collaborators.user_id);
+
+UPDATE playlist_items
+SET added_by = playlists.owner_id
+FROM playlists
+WHERE playlists.id = playlist_items.playlist_id
+  AND NOT EXISTS (SELECT 1 FROM users WHERE users.id = playlist_items.added_by);
+
+ALTER TABLE playlists
+    ADD CONSTRAINT fk_playlist_owner FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE playlist_collaborators
+    ADD CONSTRAINT fk_collaborator_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE playlist_items
+    ADD CONSTRAINT fk_item_added_by FOREIGN KEY (added_by) REFERENCES users (id);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_items_added_by ON playlist_items (added_by);
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS song_details_search_vector_update ON song_details;
+DROP TRIGGER IF EXISTS artists_search_vector_update ON artists;
+DROP TRIGGER IF EXISTS songs_search_vector_update ON songs;
+
+DROP FUNCTION IF EXISTS song_details_search_vector_trigger();
+DROP FUNCTION IF EXISTS artists_search_vector_trigger();
+DROP FUNCTION IF EXISTS songs_search_vector_trigger();
+
+DROP INDEX IF EXISTS idx_songs_search_vector;
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS song_search_vector(TEXT, TEXT, TEXT);
@@ -0,0 +1,108 @@
+-- Полнотекстовый поиск по названию песни (вес A), имени артиста (B) и тексту (C).
+-- Документ индексируется и русской, и английской конфигурацией, чтобы работал стемминг обоих языков.
+
+CREATE OR REPLACE FUNCTION song_search_vector(song_name TEXT, artist_name TEXT, lyrics TEXT)
+    RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE
+AS
+$$
+SELECT setweight(to_tsvector('russian', COALESCE(song_name, '')), 'A')
+           || setweight(to_tsvector('english', COALESCE(song_name, '')), 'A')
+           || setweight(to_tsvector('russian', COALESCE(artist_name, '')), 'B')
+           || setweight(to_tsvector('english', COALESCE(artist_name, '')), 'B')
+           || setweight(to_tsvector('russian', COALESCE(lyrics, '')), 'C')
+           || setweight(to_tsvector('english', COALESCE(lyrics, '')), 'C')
+$$;
+
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
+
+-- Новая или изменённая песня: пересчитываем вектор до записи строки
+CREATE OR REPLACE FUNCTION songs_search_vector_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    NEW.search_vector := song_search_vector(
+            NEW.name,
+            (SELECT name FROM artists WHERE id = NEW.artist_id),
+            (SELECT text FROM song_details WHERE song_id = NEW.id));
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER songs_search_vector_update
+    BEFORE INSERT OR UPDATE OF name, artist_id
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_search_vector_trigger();
+
+-- Переименование артиста меняет документы всех его песен
+CREATE OR REPLACE FUNCTION artists_search_vector_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    UPDATE songs s
+    SET search_vector = song_search_vector(s.name, NEW.name, d.text)
+    FROM songs s2
+             LEFT JOIN song_details d ON d.song_id = s2.id
+    WHERE s.id = s2.id
+      AND s.artist_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER artists_search_vector_update
+    AFTER UPDATE OF name
+    ON artists
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION artists_search_vector_trigger();
+
+-- Изменение текста песни
+CREATE OR REPLACE FUNCTION song_details_search_vector_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+DECLARE
+    target_song_id BIGINT;
+    lyrics         TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target_song_id := OLD.song_id;
+        lyrics := NULL;
+    ELSE
+        target_song_id := NEW.song_id;
+        lyrics := NEW.text;
+    END IF;
+
+    UPDATE songs s
+    SET search_vector = song_search_vector(s.name, a.name, lyrics)
+    FROM artists a
+    WHERE s.id = target_song_id
+      AND a.id = s.artist_id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER song_details_search_vector_update
+    AFTER INSERT OR DELETE OR UPDATE OF text, song_id
+    ON song_details
+    FOR EACH ROW
+EXECUTE FUNCTION song_details_search_vector_trigger();
+
+-- Заполняем вектор для уже существующих песен
+UPDATE songs s
+SET search_vector = song_search_vector(s.name, a.name, d.text)
+FROM artists a,
+     songs s2
+         LEFT JOIN song_details d ON d.song_id = s2.id
+WHERE a.id = s.artist_id
+  AND s2.id = s.id;
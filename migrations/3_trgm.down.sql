@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_songs_name_trgm;
+DROP INDEX IF EXISTS idx_artists_name_trgm;
+
+-- Расширение могут использовать другие объекты базы, поэтому не удаляем его
@@ -0,0 +1,5 @@
+-- Нечёткий поиск по названиям песен и именам артистов
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_artists_name_trgm ON artists USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_songs_name_trgm ON songs USING GIN (name gin_trgm_ops);
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS songs_clear_track ON songs;
+DROP FUNCTION IF EXISTS songs_clear_track_trigger();
+
+DROP INDEX IF EXISTS idx_songs_album_id;
+
+ALTER TABLE songs
+    DROP CONSTRAINT IF EXISTS uq_song_album_track,
+    DROP CONSTRAINT IF EXISTS chk_song_track,
+    DROP CONSTRAINT IF EXISTS fk_song_album,
+    DROP COLUMN IF EXISTS track_number,
+    DROP COLUMN IF EXISTS disc_number,
+    DROP COLUMN IF EXISTS album_id;
+
+DROP TABLE IF EXISTS albums;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS albums
+(
+    id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    title        VARCHAR(255) NOT NULL,
+    artist_id    BIGINT,
+    release_type VARCHAR(16)  NOT NULL,
+    release_date DATE,
+    cover_url    VARCHAR(1024),
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT fk_album_artist FOREIGN KEY (artist_id) REFERENCES artists (id) ON DELETE CASCADE,
+    CONSTRAINT chk_album_release_type CHECK (release_type IN ('lp', 'ep', 'single', 'compilation')),
+    -- Без артиста может быть только сборник
+    CONSTRAINT chk_album_artist CHECK (artist_id IS NOT NULL OR release_type = 'compilation')
+);
+
+CREATE INDEX IF NOT EXISTS idx_albums_artist_id ON albums (artist_id);
+
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS album_id     BIGINT,
+    ADD COLUMN IF NOT EXISTS disc_number  SMALLINT,
+    ADD COLUMN IF NOT EXISTS track_number SMALLINT,
+    ADD CONSTRAINT fk_song_album FOREIGN KEY (album_id) REFERENCES albums (id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_song_track CHECK (
+        (album_id IS NULL AND disc_number IS NULL AND track_number IS NULL)
+        OR (album_id IS NOT NULL AND disc_number >= 1 AND track_number >= 1)
+    ),
+    -- Отложенная проверка позволяет переставлять треки одной транзакцией
+    ADD CONSTRAINT uq_song_album_track UNIQUE (album_id, disc_number, track_number) DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_album_id ON songs (album_id);
+
+-- Песня, отвязанная от альбома (в том числе при его удалении), теряет и позицию в треклисте
+CREATE OR REPLACE FUNCTION songs_clear_track_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF NEW.album_id IS NULL THEN
+        NEW.disc_number := NULL;
+        NEW.track_number := NULL;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER songs_clear_track
+    BEFORE INSERT OR UPDATE OF album_id
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_clear_track_trigger();
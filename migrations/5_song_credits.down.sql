@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS songs_primary_credit ON songs;
+DROP FUNCTION IF EXISTS songs_primary_credit_trigger();
+
+DROP TABLE IF EXISTS song_credits;
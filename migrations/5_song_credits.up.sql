@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS song_credits
+(
+    song_id   BIGINT      NOT NULL,
+    artist_id BIGINT      NOT NULL,
+    role      VARCHAR(16) NOT NULL,
+    position  INT         NOT NULL,
+    PRIMARY KEY (song_id, artist_id, role),
+    CONSTRAINT fk_credit_song FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT fk_credit_artist FOREIGN KEY (artist_id) REFERENCES artists (id) ON DELETE CASCADE,
+    CONSTRAINT chk_credit_role CHECK (role IN ('primary', 'featured', 'composer', 'lyricist', 'producer'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_credits_artist_id ON song_credits (artist_id, role);
+
+-- songs.artist_id остаётся основным артистом песни и всегда указан в титрах с ролью primary
+CREATE OR REPLACE FUNCTION songs_primary_credit_trigger()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.artist_id = NEW.artist_id THEN
+            RETURN NULL;
+        END IF;
+
+        -- Новый основной артист мог уже быть в титрах, тогда берём позицию прежнего
+        DELETE FROM song_credits WHERE song_id = NEW.id AND artist_id = NEW.artist_id AND role = 'primary';
+        UPDATE song_credits
+        SET artist_id = NEW.artist_id
+        WHERE song_id = NEW.id
+          AND artist_id = OLD.artist_id
+          AND role = 'primary';
+        IF FOUND THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    INSERT INTO song_credits (song_id, artist_id, role, position)
+    VALUES (NEW.id, NEW.artist_id, 'primary',
+            (SELECT COALESCE(MAX(position), 0) + 1 FROM song_credits WHERE song_id = NEW.id))
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER songs_primary_credit
+    AFTER INSERT OR UPDATE OF artist_id
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_primary_credit_trigger();
+
+-- Существующие песни получают основного артиста в титрах
+INSERT INTO song_credits (song_id, artist_id, role, position)
+SELECT id, artist_id, 'primary', 1
+FROM songs
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS playlists
+(
+    id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    owner_id    BIGINT       NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    visibility  VARCHAR(16)  NOT NULL,
+    version     INT          NOT NULL DEFAULT 1,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT chk_playlist_visibility CHECK (visibility IN ('private', 'unlisted', 'public'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_owner_id ON playlists (owner_id);
+CREATE INDEX IF NOT EXISTS idx_playlists_public ON playlists (id) WHERE visibility = 'public';
+
+CREATE TABLE IF NOT EXISTS playlist_items
+(
+    id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    playlist_id BIGINT                   NOT NULL,
+    song_id     BIGINT                   NOT NULL,
+    -- Ключи из пакета rank сравниваются побайтово
+    position    VARCHAR(255) COLLATE "C" NOT NULL,
+    added_by    BIGINT                   NOT NULL,
+    created_at  TIMESTAMPTZ              NOT NULL DEFAULT now(),
+    CONSTRAINT fk_item_playlist FOREIGN KEY (playlist_id) REFERENCES playlists (id) ON DELETE CASCADE,
+    CONSTRAINT fk_item_song FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT uq_playlist_item_position UNIQUE (playlist_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_items_song_id ON playlist_items (song_id);
+
+CREATE TABLE IF NOT EXISTS playlist_collaborators
+(
+    playlist_id BIGINT      NOT NULL,
+    user_id     BIGINT      NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (playlist_id, user_id),
+    CONSTRAINT fk_collaborator_playlist FOREIGN KEY (playlist_id) REFERENCES playlists (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_collaborators_user_id ON playlist_collaborators (user_id);
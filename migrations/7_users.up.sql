@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id            BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    email         VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role          VARCHAR(16)  NOT NULL DEFAULT 'viewer',
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uq_user_email UNIQUE (email),
+    -- Приложение приводит email к нижнему регистру, ограничение страхует от обхода
+    CONSTRAINT chk_user_email_lower CHECK (email = lower(email)),
+    CONSTRAINT chk_user_role CHECK (role IN ('viewer', 'editor', 'admin'))
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    prefix       VARCHAR(32)  NOT NULL,
+    -- SHA-256 ключа в hex
+    hash         CHAR(64)     NOT NULL,
+    -- scope через пробел
+    scopes       TEXT         NOT NULL,
+    created_by   BIGINT,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uq_api_key_prefix UNIQUE (prefix),
+    CONSTRAINT fk_api_key_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
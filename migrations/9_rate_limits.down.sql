@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS rate_limit_take(VARCHAR, DOUBLE PRECISION, DOUBLE PRECISION);
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,52 @@
+-- Корзины ограничителя частоты запросов, общие для всех реплик. Таблица не журналируется:
+-- после сбоя корзины просто начинаются заново полными.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets
+(
+    key        VARCHAR(255)     NOT NULL PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+-- rate_limit_take повторяет ratelimit.Take: пополняет корзину и забирает токен под блокировкой
+-- строки, поэтому одновременные запросы разных реплик не расходуют один токен дважды.
+CREATE OR REPLACE FUNCTION rate_limit_take(p_key VARCHAR, p_rate DOUBLE PRECISION, p_burst DOUBLE PRECISION)
+    RETURNS TABLE
+            (
+                out_allowed BOOLEAN,
+                out_tokens  DOUBLE PRECISION
+            )
+    LANGUAGE plpgsql
+AS
+$$
+DECLARE
+    v_now    TIMESTAMPTZ := clock_timestamp();
+    v_tokens DOUBLE PRECISION;
+    v_at     TIMESTAMPTZ;
+BEGIN
+    INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+    VALUES (p_key, p_burst, v_now)
+    ON CONFLICT (key) DO NOTHING;
+
+    SELECT b.tokens, b.updated_at
+    INTO v_tokens, v_at
+    FROM rate_limit_buckets b
+    WHERE b.key = p_key
+        FOR UPDATE;
+
+    v_tokens := LEAST(p_burst, v_tokens + GREATEST(EXTRACT(EPOCH FROM v_now - v_at), 0) * p_rate);
+    out_allowed := v_tokens >= 1;
+    IF out_allowed THEN
+        v_tokens := v_tokens - 1;
+    END IF;
+
+    UPDATE rate_limit_buckets b
+    SET tokens     = v_tokens,
+        updated_at = v_now
+    WHERE b.key = p_key;
+
+    out_tokens := v_tokens;
+    RETURN NEXT;
+END
+$$;
@@ -0,0 +1,133 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAlbums(t *testing.T) {
+	reset(t)
+	seedArtists(t)
+
+	runSteps(t, []step{
+		{name: "seed first song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Take a Bow", "artist_id": 1}, status: http.StatusCreated},
+		{name: "seed second song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Starlight", "artist_id": 1}, status: http.StatusCreated},
+		{
+			name: "create", method: http.MethodPost, path: "/albums", as: editor,
+			body:   map[string]any{"title": "Black Holes and Revelations", "artist_id": 1, "release_type": "lp", "release_date": "03.07.2006"},
+			status: http.StatusCreated,
+			want:   map[string]any{"album.ID": 1, "album.release_type": "lp", "album.release_date": "2006-07-03T00:00:00Z"},
+		},
+		{
+			name: "create compilation without artist", method: http.MethodPost, path: "/albums", as: editor,
+			body:   map[string]any{"title": "Now 64", "release_type": "compilation"},
+			status: http.StatusCreated,
+			want:   map[string]any{"album.ID": 2, "album.artist_id": nil},
+		},
+		{
+			name: "create lp without artist", method: http.MethodPost, path: "/albums", as: editor,
+			body:   map[string]any{"title": "Untitled", "release_type": "lp"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create with unknown release type", method: http.MethodPost, path: "/albums", as: editor,
+			body:   map[string]any{"title": "Untitled", "artist_id": 1, "release_type": "mixtape"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create for missing artist", method: http.MethodPost, path: "/albums", as: editor,
+			body:   map[string]any{"title": "Untitled", "artist_id": 404, "release_type": "ep"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/albums",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 2},
+		},
+		{
+			name: "list by artist", method: http.MethodGet, path: "/albums?artist_id=1&release_type=lp",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1, "albums.0.title": "Black Holes and Revelations"},
+		},
+		{
+			name: "set tracks", method: http.MethodPut, path: "/albums/1/tracks", as: editor,
+			body: map[string]any{"tracks": []map[string]any{
+				{"song_id": 1, "track_number": 1},
+				{"song_id": 2, "track_number": 2},
+			}},
+			status: http.StatusOK,
+			want:   map[string]any{"album.tracks.0.name": "Take a Bow", "album.tracks.1.track_number": 2},
+		},
+		{
+			name: "set tracks with repeated position", method: http.MethodPut, path: "/albums/1/tracks", as: editor,
+			body: map[string]any{"tracks": []map[string]any{
+				{"song_id": 1, "track_number": 1},
+				{"song_id": 2, "track_number": 1},
+			}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "set tracks with missing song", method: http.MethodPut, path: "/albums/1/tracks", as: editor,
+			body:   map[string]any{"tracks": []map[string]any{{"song_id": 404, "track_number": 1}}},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "song shows track", method: http.MethodGet, path: "/songs/2",
+			status: http.StatusOK,
+			want:   map[string]any{"song.album_id": 1, "song.disc_number": 1, "song.track_number": 2},
+		},
+		{
+			name: "track change is audited", method: http.MethodGet, path: "/audit?entity=song&id=2", as: admin,
+			status: http.StatusOK,
+			want: map[string]any{
+				"entries.0.action": "update", "entries.0.changes.album_id.after": 1, "entries.0.changes.track_number.after": 2,
+			},
+		},
+		{
+			name: "move song to taken position", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Exo-Politics", "artist_id": 1, "album_id": 1, "track_number": 2},
+			status: http.StatusConflict,
+		},
+		{
+			name: "get with tracks", method: http.MethodGet, path: "/albums/1?include=tracks",
+			status: http.StatusOK,
+			want:   map[string]any{"album.artist.name": "Muse", "album.tracks.1.name": "Starlight"},
+		},
+		{
+			name: "get missing", method: http.MethodGet, path: "/albums/404",
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "album not found"},
+		},
+		{
+			name: "update", method: http.MethodPut, path: "/albums/1", as: editor,
+			body:   map[string]any{"release_type": "ep", "cover_url": "https://example.com/cover.jpg"},
+			status: http.StatusOK,
+			want:   map[string]any{"album.title": "Black Holes and Revelations", "album.release_type": "ep", "album.cover_url": "https://example.com/cover.jpg"},
+		},
+		{
+			name: "update with invalid cover", method: http.MethodPut, path: "/albums/1", as: editor,
+			body:   map[string]any{"cover_url": "cover.jpg"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "update missing", method: http.MethodPut, path: "/albums/404", as: editor,
+			body:   map[string]any{"title": "Nothing"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/albums/1", as: editor,
+			status: http.StatusOK,
+		},
+		{
+			name: "songs leave deleted album", method: http.MethodGet, path: "/songs/2",
+			status: http.StatusOK,
+			want:   map[string]any{"song.album_id": nil, "song.track_number": nil},
+		},
+		{
+			name: "delete missing", method: http.MethodDelete, path: "/albums/1", as: editor,
+			status: http.StatusNotFound,
+		},
+	})
+}
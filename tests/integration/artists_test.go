@@ -0,0 +1,129 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestArtists(t *testing.T) {
+	reset(t)
+
+	runSteps(t, []step{
+		{
+			name: "create", method: http.MethodPost, path: "/artists", as: editor,
+			body:   map[string]any{"name": "Muse", "is_group": true},
+			status: http.StatusCreated,
+			want:   map[string]any{"status": "OK", "artist.ID": 1, "artist.name": "Muse", "artist.is_group": true},
+		},
+		{
+			name: "create second", method: http.MethodPost, path: "/artists", as: editor,
+			body:   map[string]any{"name": "Radiohead"},
+			status: http.StatusCreated,
+			want:   map[string]any{"artist.ID": 2, "artist.is_group": false},
+		},
+		{
+			name: "create without name", method: http.MethodPost, path: "/artists", as: editor,
+			body:   map[string]any{"is_group": true},
+			status: http.StatusBadRequest,
+			want:   map[string]any{"status": "Error"},
+		},
+		{
+			name: "create with malformed JSON", method: http.MethodPost, path: "/artists", as: editor,
+			body:   `{"name":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create without token", method: http.MethodPost, path: "/artists",
+			body:   map[string]any{"name": "Blur"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "create as viewer", method: http.MethodPost, path: "/artists", as: viewer,
+			body:   map[string]any{"name": "Blur"},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/artists",
+			status: http.StatusOK,
+			want:   map[string]any{"artists.0.name": "Muse", "artists.1.name": "Radiohead"},
+		},
+		{
+			name: "list with songs", method: http.MethodGet, path: "/artists?include=songs",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/artists/1",
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Muse"},
+		},
+		{
+			name: "get with invalid id", method: http.MethodGet, path: "/artists/abc",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "get missing", method: http.MethodGet, path: "/artists/404",
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "artist not found"},
+		},
+		{
+			name: "update", method: http.MethodPut, path: "/artists/2", as: editor,
+			body:   map[string]any{"name": "Radiohead UK", "is_group": true},
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Radiohead UK", "artist.is_group": true},
+		},
+		{
+			name: "update keeps omitted fields", method: http.MethodPut, path: "/artists/2", as: editor,
+			body:   map[string]any{"name": "Radiohead"},
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Radiohead", "artist.is_group": true},
+		},
+		{
+			name: "update with too long name", method: http.MethodPut, path: "/artists/2", as: editor,
+			body:   map[string]any{"name": strings.Repeat("x", 256)},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "update missing", method: http.MethodPut, path: "/artists/404", as: editor,
+			body:   map[string]any{"name": "Nobody"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "songs of artist without songs", method: http.MethodGet, path: "/artists/1/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Muse", "total": 0},
+		},
+		{
+			name: "songs of missing artist", method: http.MethodGet, path: "/artists/404/songs",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete as editor", method: http.MethodDelete, path: "/artists/2", as: editor,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/artists/2", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"status": "OK"},
+		},
+		{
+			name: "get deleted", method: http.MethodGet, path: "/artists/2",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete missing", method: http.MethodDelete, path: "/artists/2", as: admin,
+			status: http.StatusNotFound,
+		},
+		{
+			name: "restore", method: http.MethodPost, path: "/artists/2/restore", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Radiohead"},
+		},
+		{
+			name: "restore not deleted", method: http.MethodPost, path: "/artists/2/restore", as: admin,
+			status: http.StatusNotFound,
+		},
+	})
+}
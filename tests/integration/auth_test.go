@@ -0,0 +1,248 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAuth(t *testing.T) {
+	reset(t)
+
+	runSteps(t, []step{
+		{
+			name: "register", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "New.User@Example.com", "password": "correct horse"},
+			status: http.StatusCreated,
+			want:   map[string]any{"token_type": "Bearer"},
+			check: func(t *testing.T, res *result) {
+				token, _ := res.get(t, "access_token").(string)
+				me := do(t, http.MethodGet, "/auth/me", "", nil, map[string]string{"Authorization": "Bearer " + token})
+				if me.status != http.StatusOK || me.get(t, "user.email") != "new.user@example.com" || me.get(t, "user.role") != "viewer" {
+					t.Errorf("GET /auth/me = %d %s, want the new viewer", me.status, me.body)
+				}
+			},
+		},
+		{
+			name: "register taken email", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "new.user@example.com", "password": "correct horse"},
+			status: http.StatusConflict,
+		},
+		{
+			name: "register short password", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "short@example.com", "password": "1234"},
+			status: http.StatusBadRequest,
+		},
+		{
+			// 40 кириллических символов - 80 байт, больше предела bcrypt
+			name: "register too long multibyte password", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "long@example.com", "password": strings.Repeat("п", 40)},
+			status: http.StatusBadRequest,
+			want:   map[string]any{"error": "password must be at most 72 bytes"},
+		},
+		{
+			name: "register multibyte password", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "cyrillic@example.com", "password": strings.Repeat("п", 36)},
+			status: http.StatusCreated,
+		},
+		{
+			name: "login with multibyte password", method: http.MethodPost, path: "/auth/login",
+			body:   map[string]any{"email": "cyrillic@example.com", "password": strings.Repeat("п", 36)},
+			status: http.StatusOK,
+		},
+		{
+			name: "register invalid email", method: http.MethodPost, path: "/auth/register",
+			body:   map[string]any{"email": "not an email", "password": "correct horse"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "login", method: http.MethodPost, path: "/auth/login",
+			body:   map[string]any{"email": "editor@example.com", "password": password},
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				refresh, _ := res.get(t, "refresh_token").(string)
+				next := do(t, http.MethodPost, "/auth/refresh", "", map[string]any{"refresh_token": refresh}, nil)
+				if next.status != http.StatusOK || next.get(t, "access_token") == nil {
+					t.Errorf("POST /auth/refresh = %d %s, want new tokens", next.status, next.body)
+				}
+
+				access, _ := res.get(t, "access_token").(string)
+				wrong := do(t, http.MethodPost, "/auth/refresh", "", map[string]any{"refresh_token": access}, nil)
+				if wrong.status != http.StatusUnauthorized {
+					t.Errorf("refresh with access token = %d, want 401", wrong.status)
+				}
+			},
+		},
+		{
+			name: "login wrong password", method: http.MethodPost, path: "/auth/login",
+			body:   map[string]any{"email": "editor@example.com", "password": "wrong password"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "login unknown user", method: http.MethodPost, path: "/auth/login",
+			body:   map[string]any{"email": "nobody@example.com", "password": password},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "refresh with garbage", method: http.MethodPost, path: "/auth/refresh",
+			body:   map[string]any{"refresh_token": "garbage"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "me", method: http.MethodGet, path: "/auth/me", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"user.ID": 1, "user.role": "admin"},
+		},
+		{
+			name: "me without token", method: http.MethodGet, path: "/auth/me",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "me with invalid token", method: http.MethodGet, path: "/auth/me",
+			header: map[string]string{"Authorization": "Bearer garbage"},
+			status: http.StatusUnauthorized,
+		},
+	})
+}
+
+func TestUsers(t *testing.T) {
+	reset(t)
+
+	runSteps(t, []step{
+		{
+			name: "list", method: http.MethodGet, path: "/users", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3, "users.0.email": "admin@example.com"},
+		},
+		{
+			name: "list as editor", method: http.MethodGet, path: "/users", as: editor,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "promote", method: http.MethodPut, path: "/users/3/role", as: admin,
+			body:   map[string]any{"role": "editor"},
+			status: http.StatusOK,
+			want:   map[string]any{"user.role": "editor"},
+		},
+		{
+			// Роль берётся из токена, поэтому новая роль действует после повторного входа
+			name: "promoted viewer logs in as editor", method: http.MethodPost, path: "/auth/login",
+			body:   map[string]any{"email": "viewer@example.com", "password": password},
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				token, _ := res.get(t, "access_token").(string)
+				created := do(t, http.MethodPost, "/artists", "", map[string]any{"name": "Muse"}, map[string]string{"Authorization": "Bearer " + token})
+				if created.status != http.StatusCreated {
+					t.Errorf("POST /artists after promotion = %d %s, want 201", created.status, created.body)
+				}
+			},
+		},
+		{
+			name: "unknown role", method: http.MethodPut, path: "/users/3/role", as: admin,
+			body:   map[string]any{"role": "owner"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "demote yourself", method: http.MethodPut, path: "/users/1/role", as: admin,
+			body:   map[string]any{"role": "viewer"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "missing user", method: http.MethodPut, path: "/users/404/role", as: admin,
+			body:   map[string]any{"role": "editor"},
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "user not found"},
+		},
+	})
+}
+
+func TestAPIKeys(t *testing.T) {
+	reset(t)
+
+	var key string
+	runSteps(t, []step{
+		{
+			name: "create", method: http.MethodPost, path: "/api-keys", as: admin,
+			body:   map[string]any{"name": "label feed", "scopes": []string{"songs:read", "songs:write"}},
+			status: http.StatusCreated,
+			want:   map[string]any{"api_key.ID": 1, "api_key.name": "label feed", "api_key.created_by": 1},
+			check: func(t *testing.T, res *result) {
+				key, _ = res.get(t, "key").(string)
+				created := do(t, http.MethodPost, "/artists", "", map[string]any{"name": "Muse"}, map[string]string{"X-API-Key": key})
+				if created.status != http.StatusCreated {
+					t.Errorf("POST /artists with API key = %d %s, want 201", created.status, created.body)
+				}
+				denied := do(t, http.MethodGet, "/users", "", nil, map[string]string{"Authorization": "Bearer " + key})
+				if denied.status != http.StatusForbidden {
+					t.Errorf("GET /users with songs scopes = %d, want 403", denied.status)
+				}
+			},
+		},
+		{
+			name: "create with unknown scope", method: http.MethodPost, path: "/api-keys", as: admin,
+			body:   map[string]any{"name": "bad", "scopes": []string{"everything"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create as editor", method: http.MethodPost, path: "/api-keys", as: editor,
+			body:   map[string]any{"name": "mine", "scopes": []string{"songs:read"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/api-keys", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"api_keys.0.name": "label feed"},
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/api-keys/1", as: admin,
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				if res.get(t, "api_key.last_used_at") == nil {
+					t.Error("last_used_at is not set after the key was used")
+				}
+			},
+		},
+		{
+			name: "get missing", method: http.MethodGet, path: "/api-keys/404", as: admin,
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "API key not found"},
+		},
+		{
+			name: "rotate", method: http.MethodPost, path: "/api-keys/1/rotate", as: admin,
+			status: http.StatusCreated,
+			want:   map[string]any{"api_key.ID": 2, "api_key.name": "label feed"},
+			check: func(t *testing.T, res *result) {
+				old := do(t, http.MethodGet, "/artists", "", nil, map[string]string{"X-API-Key": key})
+				if old.status != http.StatusUnauthorized {
+					t.Errorf("rotated key = %d, want 401", old.status)
+				}
+				key, _ = res.get(t, "key").(string)
+			},
+		},
+		{
+			name: "rotate missing", method: http.MethodPost, path: "/api-keys/404/rotate", as: admin,
+			status: http.StatusNotFound,
+		},
+		{
+			name: "rotate expired", method: http.MethodPost, path: "/api-keys/1/rotate", as: admin,
+			status: http.StatusConflict,
+			want:   map[string]any{"error": "API key has expired"},
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/api-keys/2", as: admin,
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				deleted := do(t, http.MethodGet, "/artists", "", nil, map[string]string{"X-API-Key": key})
+				if deleted.status != http.StatusUnauthorized {
+					t.Errorf("deleted key = %d, want 401", deleted.status)
+				}
+			},
+		},
+		{
+			name: "delete missing", method: http.MethodDelete, path: "/api-keys/2", as: admin,
+			status: http.StatusNotFound,
+		},
+	})
+}
@@ -0,0 +1,295 @@
+//go:build integration
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// catalogue - файл импорта, из которого строятся каталоги тестов импорта, выгрузки и поиска.
+const catalogue = `artist,is_group,song,release_date,text,link
+Muse,true,Uprising,07.09.2009,"Paranoia is in bloom
+The PR transmissions will resume
+
+They will not force us",https://example.com/uprising
+Muse,,Starlight,03.09.2006,"Far away
+This ship is taking me far away",
+Radiohead,true,Creep,21.09.1992,"When you were here before
+Couldn't look you in the eye",
+`
+
+// seedCatalogue импортирует catalogue: Muse (ID 1) с песнями Uprising (1) и Starlight (2)
+// и Radiohead (2) с песней Creep (3).
+func seedCatalogue(t *testing.T) {
+	t.Helper()
+
+	runSteps(t, []step{{
+		name: "seed catalogue", method: http.MethodPost, path: "/import?format=csv", as: editor,
+		body:   catalogue,
+		status: http.StatusOK,
+		want:   map[string]any{"created": 3, "failed": 0},
+	}})
+}
+
+func TestImport(t *testing.T) {
+	reset(t)
+
+	runSteps(t, []step{
+		{
+			name: "csv", method: http.MethodPost, path: "/import?format=csv", as: editor,
+			body:   catalogue,
+			status: http.StatusOK,
+			want: map[string]any{
+				"dry_run": false, "created": 3, "updated": 0, "failed": 0,
+				"rows.0.line": 2, "rows.0.artist": "created", "rows.0.detail": "created",
+				"rows.1.artist_id": 1, "rows.1.artist": "skipped",
+			},
+		},
+		{
+			name: "jsonl updates and fails per row", method: http.MethodPost, path: "/import", as: editor,
+			header: map[string]string{"Content-Type": "application/x-ndjson"},
+			body: `{"artist":"Muse","song":"Uprising","release_date":"07.09.2009","text":"Paranoia is in bloom","link":"https://example.com/uprising"}` + "\n" +
+				`{"artist":"Muse","song":"Starlight","release_date":"2006-13-01","text":"x"}` + "\n" +
+				`{"artist":"Blur","song":"Song 2"}` + "\n",
+			status: http.StatusOK,
+			want: map[string]any{
+				"updated": 1, "failed": 1, "created": 1,
+				"rows.0.detail": "updated", "rows.1.status": "failed", "rows.2.song_id": 4,
+			},
+		},
+		{
+			name: "imported text is a new revision", method: http.MethodGet, path: "/songs/1/lyrics/revisions",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 2},
+		},
+		{
+			// Пробный импорт идёт последним: откат транзакции не возвращает значения последовательностей ID
+			name: "dry run", method: http.MethodPost, path: "/import?dry_run=true", as: editor,
+			header: map[string]string{"Content-Type": "text/csv"},
+			body:   "artist,song\nMuse,Hysteria\nMuse,Uprising\n",
+			status: http.StatusOK,
+			want:   map[string]any{"dry_run": true, "created": 1, "skipped": 1},
+		},
+		{
+			name: "dry run saves nothing", method: http.MethodGet, path: "/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 4},
+		},
+		{
+			name: "unknown format", method: http.MethodPost, path: "/import", as: editor,
+			header: map[string]string{"Content-Type": "application/pdf"},
+			body:   "%PDF",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "malformed header", method: http.MethodPost, path: "/import?format=csv", as: editor,
+			body:   "name,song\nMuse,Uprising\n",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "as viewer", method: http.MethodPost, path: "/import?format=csv", as: viewer,
+			body:   catalogue,
+			status: http.StatusForbidden,
+		},
+	})
+}
+
+func TestExport(t *testing.T) {
+	reset(t)
+	seedCatalogue(t)
+
+	runSteps(t, []step{
+		{
+			name: "anonymous", method: http.MethodGet, path: "/export/songs",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "songs csv by default", method: http.MethodGet, path: "/export/songs?artist=muse&sort=name", as: viewer,
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				wantHeader("Content-Type", "text/csv; charset=utf-8")(t, res)
+				wantHeader("Content-Disposition", `attachment; filename="songs.csv"`)(t, res)
+
+				want := "id,name,artist_id,artist_name,album_id,album_title,disc_number,track_number,release_date,link,text\n" +
+					"2,Starlight,1,Muse,,,,,2006-09-03,,\"Far away\nThis ship is taking me far away\"\n" +
+					"1,Uprising,1,Muse,,,,,2009-09-07,https://example.com/uprising,\"Paranoia is in bloom\nThe PR transmissions will resume\n\nThey will not force us\"\n"
+				if got := string(res.body); got != want {
+					t.Errorf("body:\n%s\nwant:\n%s", got, want)
+				}
+			},
+		},
+		{
+			name: "songs jsonl by Accept", method: http.MethodGet, path: "/export/songs?release_to=1999-12-31", as: viewer,
+			header: map[string]string{"Accept": "application/x-ndjson"},
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				want := `{"id":3,"name":"Creep","artist_id":2,"artist_name":"Radiohead","album_id":null,"album_title":null,` +
+					`"disc_number":null,"track_number":null,"release_date":"1992-09-21","link":"",` +
+					`"text":"When you were here before\nCouldn't look you in the eye"}` + "\n"
+				if got := string(res.body); got != want {
+					t.Errorf("body:\n%s\nwant:\n%s", got, want)
+				}
+			},
+		},
+		{
+			name: "songs xlsx", method: http.MethodGet, path: "/export/songs?format=xlsx", as: viewer,
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				zr, err := zip.NewReader(bytes.NewReader(res.body), int64(len(res.body)))
+				if err != nil {
+					t.Fatalf("not a zip archive: %v", err)
+				}
+				if len(zr.File) != 5 || zr.File[4].Name != "xl/worksheets/sheet1.xml" {
+					t.Errorf("unexpected workbook parts: %v", zr.File)
+				}
+			},
+		},
+		{
+			name: "songs with invalid filter", method: http.MethodGet, path: "/export/songs?has_link=maybe", as: viewer,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "artists", method: http.MethodGet, path: "/export/artists?format=jsonl", as: viewer,
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				want := `{"id":1,"name":"Muse","is_group":true,"songs":2}` + "\n" +
+					`{"id":2,"name":"Radiohead","is_group":true,"songs":1}` + "\n"
+				if got := string(res.body); got != want {
+					t.Errorf("body:\n%s\nwant:\n%s", got, want)
+				}
+			},
+		},
+		{
+			name: "artists not acceptable", method: http.MethodGet, path: "/export/artists", as: viewer,
+			header: map[string]string{"Accept": "application/json"},
+			status: http.StatusNotAcceptable,
+		},
+		{
+			name: "artists unknown format", method: http.MethodGet, path: "/export/artists?format=pdf", as: viewer,
+			status: http.StatusBadRequest,
+		},
+	})
+}
+
+func TestSearch(t *testing.T) {
+	reset(t)
+	seedCatalogue(t)
+
+	runSteps(t, []step{
+		{
+			name: "fulltext", method: http.MethodGet, path: "/search?q=transmissions",
+			status: http.StatusOK,
+			want:   map[string]any{"mode": "fulltext", "total": 1, "results.0.song": "Uprising", "results.0.verse": 1},
+			check: func(t *testing.T, res *result) {
+				if snippet, _ := res.get(t, "results.0.snippet").(string); !strings.Contains(snippet, "<mark>") {
+					t.Errorf("snippet %q has no highlighted words", snippet)
+				}
+			},
+		},
+		{
+			name: "fulltext by artist", method: http.MethodGet, path: "/search?q=radiohead",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1, "results.0.song": "Creep"},
+		},
+		{
+			name: "fulltext with exclusion", method: http.MethodGet, path: "/search?q=far+-ship",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 0},
+		},
+		{
+			name: "fuzzy", method: http.MethodGet, path: "/search?q=uprisng&mode=fuzzy",
+			status: http.StatusOK,
+			want:   map[string]any{"mode": "fuzzy", "results.0.song": "Uprising"},
+		},
+		{
+			name: "without query", method: http.MethodGet, path: "/search",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "unknown mode", method: http.MethodGet, path: "/search?q=muse&mode=regex",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "suggest", method: http.MethodGet, path: "/suggest?q=mus",
+			status: http.StatusOK,
+			want:   map[string]any{"artists.0.name": "Muse"},
+		},
+		{
+			name: "suggest with invalid threshold", method: http.MethodGet, path: "/suggest?q=mus&threshold=2",
+			status: http.StatusBadRequest,
+		},
+	})
+}
+
+func TestTrashAndAudit(t *testing.T) {
+	reset(t)
+	seedCatalogue(t)
+
+	runSteps(t, []step{
+		{name: "delete song", method: http.MethodDelete, path: "/songs/3", as: editor, status: http.StatusOK},
+		{name: "delete artist", method: http.MethodDelete, path: "/artists/1", as: admin, status: http.StatusOK},
+		{
+			name: "deleted songs leave listings", method: http.MethodGet, path: "/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 0},
+		},
+		{
+			name: "trash", method: http.MethodGet, path: "/trash", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 4, "items.0.type": "artist", "items.0.name": "Muse"},
+		},
+		{
+			name: "trash songs", method: http.MethodGet, path: "/trash?type=song", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3},
+		},
+		{
+			name: "trash unknown type", method: http.MethodGet, path: "/trash?type=album", as: editor,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "trash as viewer", method: http.MethodGet, path: "/trash", as: viewer,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "restore song of deleted artist", method: http.MethodPost, path: "/songs/1/restore", as: editor,
+			status: http.StatusConflict,
+			want:   map[string]any{"error": "artist is deleted"},
+		},
+		{name: "restore artist", method: http.MethodPost, path: "/artists/1/restore", as: admin, status: http.StatusOK},
+		{
+			name: "artist songs are back", method: http.MethodGet, path: "/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 2},
+		},
+		{
+			name: "audit", method: http.MethodGet, path: "/audit?entity=artist&id=1", as: admin,
+			status: http.StatusOK,
+			want: map[string]any{
+				"total":             3,
+				"entries.0.action":  "restore",
+				"entries.1.action":  "delete",
+				"entries.1.user_id": 1,
+				"entries.2.action":  "create",
+				"entries.2.user_id": 2,
+			},
+		},
+		{
+			name: "audit of song details", method: http.MethodGet, path: "/audit?entity=song_detail", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3, "entries.0.action": "create"},
+		},
+		{
+			name: "audit id without entity", method: http.MethodGet, path: "/audit?id=1", as: admin,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "audit as editor", method: http.MethodGet, path: "/audit", as: editor,
+			status: http.StatusForbidden,
+		},
+	})
+}
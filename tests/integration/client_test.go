@@ -0,0 +1,215 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"music-lib/internal/http/openapi"
+	"music-lib/internal/lib/auth"
+	"music-lib/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Пользователи, которых reset создаёт в пустой базе. ID предсказуемы, потому что
+// reset сбрасывает последовательности: admin - 1, editor - 2, viewer - 3.
+const (
+	admin  = "admin"
+	editor = "editor"
+	viewer = "viewer"
+)
+
+// password - пароль всех пользователей из reset.
+const password = "password123"
+
+// accessTokens - access-токены пользователей из reset по ролям.
+var accessTokens map[string]string
+
+// covered - маршруты спецификации, на которые тесты получили успешный ответ.
+var covered = struct {
+	sync.Mutex
+	routes map[string]bool
+}{routes: make(map[string]bool)}
+
+// reset очищает базу и создаёт пользователей admin, editor и viewer.
+func reset(t *testing.T) {
+	t.Helper()
+
+	truncate(t)
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+
+	accessTokens = make(map[string]string)
+	for _, role := range []models.Role{models.RoleAdmin, models.RoleEditor, models.RoleViewer} {
+		user := models.User{Email: string(role) + "@example.com", PasswordHash: hash, Role: role}
+		if err := store.Users().Create(context.Background(), &user); err != nil {
+			t.Fatalf("create %s: %v", role, err)
+		}
+		pair, err := tokens.Issue(user)
+		if err != nil {
+			t.Fatalf("issue token for %s: %v", role, err)
+		}
+		accessTokens[string(role)] = pair.AccessToken
+	}
+}
+
+// step - один запрос сценария и ожидаемый ответ.
+type step struct {
+	name   string
+	method string
+	path   string
+	// as - роль пользователя из reset, от имени которого идёт запрос; пустая - без токена
+	as     string
+	header map[string]string
+	// body - тело запроса: строка отправляется как есть, остальное - в JSON
+	body   any
+	status int
+	// want - ожидаемые значения полей JSON-ответа по путям вида "songs.0.name"
+	want map[string]any
+	// check - дополнительная проверка ответа
+	check func(t *testing.T, res *result)
+}
+
+// result - ответ API.
+type result struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// runSteps выполняет шаги по порядку; шаги сценария зависят от данных, созданных предыдущими.
+func runSteps(t *testing.T, steps []step) {
+	t.Helper()
+
+	for _, s := range steps {
+		ok := t.Run(s.name, func(t *testing.T) {
+			res := do(t, s.method, s.path, s.as, s.body, s.header)
+			if res.status != s.status {
+				t.Fatalf("%s %s: status %d, want %d: %s", s.method, s.path, res.status, s.status, res.body)
+			}
+			for path, want := range s.want {
+				if got := res.get(t, path); fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("%s = %v, want %v", path, got, want)
+				}
+			}
+			if s.check != nil {
+				s.check(t, res)
+			}
+		})
+		if !ok {
+			// Следующие шаги опираются на результат упавшего
+			t.FailNow()
+		}
+	}
+}
+
+// do выполняет запрос к приложению и отмечает маршрут покрытым, если ответ успешный.
+func do(t *testing.T, method, path, as string, body any, header map[string]string) *result {
+	t.Helper()
+
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if as != "" {
+		req.Header.Set("Authorization", "Bearer "+accessTokens[as])
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	// Контекст маршрута создаётся заранее, чтобы после ответа узнать, какой шаблон сработал
+	rctx := chi.NewRouteContext()
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code < http.StatusBadRequest {
+		route := rctx.RoutePattern()
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		covered.Lock()
+		covered.routes[method+" "+route] = true
+		covered.Unlock()
+	}
+
+	return &result{status: rec.Code, header: rec.Header(), body: rec.Body.Bytes()}
+}
+
+// get возвращает поле JSON-ответа по пути из имён полей и индексов, разделённых точками.
+func (r *result) get(t *testing.T, path string) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal(r.body, &v); err != nil {
+		t.Fatalf("decode response: %v: %s", err, r.body)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+
+	return v
+}
+
+// checkCoverage сообщает о маршрутах спецификации, на которые ни один тест не получил успешный ответ.
+func checkCoverage() int {
+	var missing []string
+	for path, item := range openapi.Spec().Paths {
+		for method := range *item {
+			route := strings.ToUpper(method) + " " + path
+			if !covered.routes[route] {
+				missing = append(missing, route)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return 0
+	}
+
+	slices.Sort(missing)
+	fmt.Fprintf(os.Stderr, "routes without a successful integration test:\n  %s\n", strings.Join(missing, "\n  "))
+
+	return 1
+}
@@ -0,0 +1,245 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// coverMaxBytes - предел размера обложки в тестах.
+const coverMaxBytes = 256 << 10
+
+func TestCovers(t *testing.T) {
+	reset(t)
+
+	pngBody, pngType := coverForm(t, "file", encodeImage(t, 300, 200, func(b *bytes.Buffer, img image.Image) error {
+		return png.Encode(b, img)
+	}))
+	jpegBody, jpegType := coverForm(t, "file", encodeImage(t, 2048, 2048, func(b *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(b, img, nil)
+	}))
+	gifBody, gifType := coverForm(t, "file", encodeImage(t, 32, 32, func(b *bytes.Buffer, img image.Image) error {
+		return gif.Encode(b, img, nil)
+	}))
+	hugeBody, hugeType := coverForm(t, "file", bytes.Repeat([]byte{0x89}, coverMaxBytes+1))
+	noFileBody, noFileType := coverForm(t, "image", []byte("x"))
+
+	// Адреса файлов первой обложки: после замены они должны исчезнуть
+	var oldURLs []string
+
+	runSteps(t, []step{
+		{
+			name: "create artist", method: http.MethodPost, path: "/artists", as: editor,
+			body:   map[string]any{"name": "Muse", "is_group": true},
+			status: http.StatusCreated,
+		},
+		{
+			name: "create song", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Uprising", "artist_id": 1},
+			status: http.StatusCreated,
+		},
+		{
+			name: "upload artist cover", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body: pngBody, header: map[string]string{"Content-Type": pngType},
+			status: http.StatusOK,
+			want: map[string]any{
+				"artist.name":                        "Muse",
+				"artist.cover.original.width":        300,
+				"artist.cover.original.height":       200,
+				"artist.cover.original.content_type": "image/png",
+				"artist.cover.thumbnails.0.size":     64,
+				"artist.cover.thumbnails.0.width":    64,
+				"artist.cover.thumbnails.0.height":   42,
+				"artist.cover.thumbnails.2.size":     1024,
+				"artist.cover.thumbnails.2.width":    300,
+			},
+			check: func(t *testing.T, res *result) {
+				for _, path := range []string{"artist.cover.original.url", "artist.cover.thumbnails.1.url"} {
+					url, _ := res.get(t, path).(string)
+					file := do(t, http.MethodGet, url, "", nil, nil)
+					if file.status != http.StatusOK || file.header.Get("Content-Type") != "image/png" {
+						t.Errorf("GET %s = %d %s, want a PNG file", url, file.status, file.header.Get("Content-Type"))
+					}
+					oldURLs = append(oldURLs, url)
+				}
+			},
+		},
+		{
+			name: "get artist with cover", method: http.MethodGet, path: "/artists/1",
+			status: http.StatusOK,
+			want:   map[string]any{"artist.cover.thumbnails.1.width": 256, "artist.cover.thumbnails.1.height": 170},
+		},
+		{
+			name: "update keeps cover", method: http.MethodPut, path: "/artists/1", as: editor,
+			body:   map[string]any{"name": "Muse UK"},
+			status: http.StatusOK,
+			want:   map[string]any{"artist.cover.original.width": 300},
+		},
+		{
+			name: "replace artist cover", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body: jpegBody, header: map[string]string{"Content-Type": jpegType},
+			status: http.StatusOK,
+			want: map[string]any{
+				"artist.cover.original.content_type":     "image/jpeg",
+				"artist.cover.thumbnails.2.width":        1024,
+				"artist.cover.thumbnails.2.content_type": "image/jpeg",
+			},
+			check: func(t *testing.T, res *result) {
+				for _, url := range oldURLs {
+					if file := do(t, http.MethodGet, url, "", nil, nil); file.status != http.StatusNotFound {
+						t.Errorf("GET replaced %s = %d, want 404", url, file.status)
+					}
+				}
+			},
+		},
+		{
+			name: "upload unsupported type", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body: gifBody, header: map[string]string{"Content-Type": gifType},
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "upload too large", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body: hugeBody, header: map[string]string{"Content-Type": hugeType},
+			status: http.StatusRequestEntityTooLarge,
+			want:   map[string]any{"error": "file is too large"},
+		},
+		{
+			name: "upload without file field", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body: noFileBody, header: map[string]string{"Content-Type": noFileType},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "upload JSON instead of form", method: http.MethodPut, path: "/artists/1/cover", as: editor,
+			body:   map[string]any{"file": "cover.png"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "upload as viewer", method: http.MethodPut, path: "/artists/1/cover", as: viewer,
+			body: pngBody, header: map[string]string{"Content-Type": pngType},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "upload for missing artist", method: http.MethodPut, path: "/artists/404/cover", as: editor,
+			body: pngBody, header: map[string]string{"Content-Type": pngType},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete artist cover", method: http.MethodDelete, path: "/artists/1/cover", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"artist.name": "Muse UK", "artist.cover": nil},
+		},
+		{
+			name: "delete missing artist cover", method: http.MethodDelete, path: "/artists/1/cover", as: editor,
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "cover not found"},
+		},
+		{
+			name: "upload song cover", method: http.MethodPut, path: "/songs/1/cover", as: editor,
+			body: pngBody, header: map[string]string{"Content-Type": pngType},
+			status: http.StatusOK,
+			want:   map[string]any{"song.name": "Uprising", "song.cover.thumbnails.1.size": 256},
+		},
+		{
+			name: "song list shows cover", method: http.MethodGet, path: "/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"songs.0.cover.original.width": 300},
+		},
+		{
+			name: "delete song cover", method: http.MethodDelete, path: "/songs/1/cover", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"song.cover": nil},
+		},
+		{
+			name: "cover changes are audited", method: http.MethodGet, path: "/audit?entity=song&id=1", as: admin,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3, "entries.0.action": "update", "entries.1.action": "update"},
+		},
+	})
+}
+
+// TestCoversConcurrentReplace проверяет, что при одновременных заменах обложки каждая
+// удаляет файлы только той обложки, которую заменила: остаются файлы текущей обложки.
+func TestCoversConcurrentReplace(t *testing.T) {
+	reset(t)
+
+	body, contentType := coverForm(t, "file", encodeImage(t, 100, 100, func(b *bytes.Buffer, img image.Image) error {
+		return png.Encode(b, img)
+	}))
+	runSteps(t, []step{{
+		name: "create artist", method: http.MethodPost, path: "/artists", as: editor,
+		body:   map[string]any{"name": "Muse", "is_group": true},
+		status: http.StatusCreated,
+	}})
+
+	results := make([]*result, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = do(t, http.MethodPut, "/artists/1/cover", editor, body, map[string]string{"Content-Type": contentType})
+		}()
+	}
+	wg.Wait()
+
+	current, _ := do(t, http.MethodGet, "/artists/1", "", nil, nil).get(t, "artist.cover.original.url").(string)
+	for i, res := range results {
+		if res.status != http.StatusOK {
+			t.Fatalf("upload %d = %d %s, want 200", i, res.status, res.body)
+		}
+		url, _ := res.get(t, "artist.cover.original.url").(string)
+
+		want := http.StatusNotFound
+		if url == current {
+			want = http.StatusOK
+		}
+		if file := do(t, http.MethodGet, url, "", nil, nil); file.status != want {
+			t.Errorf("GET %s = %d, want %d (current cover %s)", url, file.status, want, current)
+		}
+	}
+}
+
+// coverForm возвращает тело multipart/form-data с файлом в поле field и его Content-Type.
+func coverForm(t *testing.T, field string, data []byte) (string, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile(field, "cover")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	return buf.String(), form.FormDataContentType()
+}
+
+// encodeImage рисует картинку w x h с диагональю и кодирует её.
+func encodeImage(t *testing.T, w, h int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		img.Set(x, x*h/w, color.RGBA{B: 200, A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("encode image: %v", err)
+	}
+	return buf.Bytes()
+}
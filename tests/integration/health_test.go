@@ -0,0 +1,12 @@
+//go:build integration
+
+package integration
+
+import "testing"
+
+func TestHealth(t *testing.T) {
+	reset(t)
+	runSteps(t, []step{
+		{name: "ok", method: "GET", path: "/health", status: 200},
+	})
+}
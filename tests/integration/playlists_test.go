@@ -0,0 +1,214 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+// ifMatch - заголовок If-Match с версией плейлиста.
+func ifMatch(version string) map[string]string {
+	return map[string]string{"If-Match": `"` + version + `"`}
+}
+
+func TestPlaylists(t *testing.T) {
+	reset(t)
+	seedArtists(t)
+
+	runSteps(t, []step{
+		{name: "seed first song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Uprising", "artist_id": 1}, status: http.StatusCreated},
+		{name: "seed second song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Creep", "artist_id": 2}, status: http.StatusCreated},
+		{
+			name: "create", method: http.MethodPost, path: "/playlists", as: viewer,
+			body:   map[string]any{"name": "Road trip"},
+			status: http.StatusCreated,
+			want:   map[string]any{"playlist.ID": 1, "playlist.owner_id": 3, "playlist.visibility": "private", "playlist.version": 1},
+			check:  wantHeader("ETag", `"1"`),
+		},
+		{
+			name: "create without token", method: http.MethodPost, path: "/playlists",
+			body:   map[string]any{"name": "Road trip"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "create with unknown visibility", method: http.MethodPost, path: "/playlists", as: viewer,
+			body:   map[string]any{"name": "Road trip", "visibility": "secret"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "list mine", method: http.MethodGet, path: "/playlists", as: viewer,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1, "playlists.0.name": "Road trip"},
+		},
+		{
+			name: "list mine without token", method: http.MethodGet, path: "/playlists",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "private is hidden from others", method: http.MethodGet, path: "/playlists/1", as: editor,
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "playlist not found"},
+		},
+		{
+			name: "add item", method: http.MethodPost, path: "/playlists/1/items", as: viewer,
+			header: ifMatch("1"),
+			body:   map[string]any{"song_id": 1},
+			status: http.StatusCreated,
+			want:   map[string]any{"item.ID": 1, "item.song_id": 1, "item.added_by": 3},
+			check:  wantHeader("ETag", `"2"`),
+		},
+		{
+			name: "add item before first", method: http.MethodPost, path: "/playlists/1/items", as: viewer,
+			header: ifMatch("2"),
+			body:   map[string]any{"song_id": 2, "before_item_id": 1},
+			status: http.StatusCreated,
+			want:   map[string]any{"item.ID": 2},
+		},
+		{
+			name: "add item without If-Match", method: http.MethodPost, path: "/playlists/1/items", as: viewer,
+			body:   map[string]any{"song_id": 1},
+			status: http.StatusPreconditionRequired,
+		},
+		{
+			name: "add item with stale version", method: http.MethodPost, path: "/playlists/1/items", as: viewer,
+			header: ifMatch("1"),
+			body:   map[string]any{"song_id": 1},
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name: "add missing song", method: http.MethodPost, path: "/playlists/1/items", as: viewer,
+			header: ifMatch("3"),
+			body:   map[string]any{"song_id": 404},
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "song not found"},
+		},
+		{
+			name: "items are ordered", method: http.MethodGet, path: "/playlists/1", as: viewer,
+			status: http.StatusOK,
+			want:   map[string]any{"playlist.items.0.song_id": 2, "playlist.items.1.song_id": 1},
+			check:  wantHeader("ETag", `"3"`),
+		},
+		{
+			name: "move item", method: http.MethodPost, path: "/playlists/1/items/2/move", as: viewer,
+			header: ifMatch("3"),
+			body:   map[string]any{"after_item_id": 1},
+			status: http.StatusOK,
+			check:  wantHeader("ETag", `"4"`),
+		},
+		{
+			name: "move item without target", method: http.MethodPost, path: "/playlists/1/items/2/move", as: viewer,
+			header: ifMatch("4"),
+			body:   map[string]any{},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "move missing item", method: http.MethodPost, path: "/playlists/1/items/404/move", as: viewer,
+			header: ifMatch("4"),
+			body:   map[string]any{"after_item_id": 1},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "editor cannot add items", method: http.MethodPost, path: "/playlists/1/items", as: editor,
+			header: ifMatch("4"),
+			body:   map[string]any{"song_id": 1},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "add collaborator", method: http.MethodPut, path: "/playlists/1/collaborators/2", as: viewer,
+			status: http.StatusOK,
+		},
+		{
+			name: "add unknown collaborator", method: http.MethodPut, path: "/playlists/1/collaborators/404", as: viewer,
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "user not found"},
+		},
+		{
+			name: "only owner adds collaborators", method: http.MethodPut, path: "/playlists/1/collaborators/1", as: editor,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "collaborator removes item", method: http.MethodDelete, path: "/playlists/1/items/1", as: editor,
+			header: ifMatch("4"),
+			status: http.StatusOK,
+			check:  wantHeader("ETag", `"5"`),
+		},
+		{
+			name: "remove missing item", method: http.MethodDelete, path: "/playlists/1/items/1", as: editor,
+			header: ifMatch("5"),
+			status: http.StatusNotFound,
+		},
+		{
+			name: "collaborator cannot change visibility", method: http.MethodPut, path: "/playlists/1", as: editor,
+			header: ifMatch("5"),
+			body:   map[string]any{"visibility": "public"},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "collaborator renames", method: http.MethodPut, path: "/playlists/1", as: editor,
+			header: map[string]string{"If-Match": `W/"5"`},
+			body:   map[string]any{"name": "Road trip 2026"},
+			status: http.StatusOK,
+			want:   map[string]any{"playlist.name": "Road trip 2026", "playlist.version": 6},
+		},
+		{
+			name: "collaborator lists shared", method: http.MethodGet, path: "/playlists?scope=mine", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1},
+		},
+		{
+			name: "remove collaborator", method: http.MethodDelete, path: "/playlists/1/collaborators/2", as: viewer,
+			status: http.StatusOK,
+		},
+		{
+			name: "remove collaborator again", method: http.MethodDelete, path: "/playlists/1/collaborators/2", as: viewer,
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "collaborator not found"},
+		},
+		{
+			name: "publish", method: http.MethodPut, path: "/playlists/1", as: viewer,
+			header: ifMatch("6"),
+			body:   map[string]any{"visibility": "public"},
+			status: http.StatusOK,
+			want:   map[string]any{"playlist.visibility": "public"},
+		},
+		{
+			name: "public is visible anonymously", method: http.MethodGet, path: "/playlists/1",
+			status: http.StatusOK,
+			want:   map[string]any{"playlist.items.0.song.name": "Creep"},
+		},
+		{
+			name: "list public", method: http.MethodGet, path: "/playlists?scope=public",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1},
+		},
+		{
+			name: "list with unknown scope", method: http.MethodGet, path: "/playlists?scope=all", as: viewer,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "others cannot delete", method: http.MethodDelete, path: "/playlists/1", as: editor,
+			header: ifMatch("7"),
+			status: http.StatusForbidden,
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/playlists/1", as: viewer,
+			header: ifMatch("7"),
+			status: http.StatusOK,
+		},
+		{
+			name: "get deleted", method: http.MethodGet, path: "/playlists/1", as: viewer,
+			status: http.StatusNotFound,
+		},
+	})
+}
+
+// wantHeader проверяет заголовок ответа.
+func wantHeader(name, want string) func(t *testing.T, res *result) {
+	return func(t *testing.T, res *result) {
+		t.Helper()
+		if got := res.header.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
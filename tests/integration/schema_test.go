@@ -0,0 +1,84 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"music-lib/internal/lib/migrator"
+	"music-lib/internal/models"
+	"testing"
+)
+
+// TestSchema сверяет схему после всех миграций с тем, что ожидают модели GORM.
+func TestSchema(t *testing.T) {
+	m, err := migrator.New(dbConfig.DatabaseURL(), migrationsPath)
+	if err != nil {
+		t.Fatalf("open migrator: %v", err)
+	}
+	defer m.Close()
+
+	checkSchema(t, m)
+}
+
+// TestMigrationsRoundTrip откатывает все миграции и применяет их заново. Это проверяет
+// down-файлы; чтобы не трогать схему остальных тестов, используется отдельная база.
+func TestMigrationsRoundTrip(t *testing.T) {
+	const database = "music_lib_round_trip"
+
+	if err := store.DB.Exec("CREATE DATABASE " + database).Error; err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := store.DB.Exec("DROP DATABASE IF EXISTS " + database).Error; err != nil {
+			t.Errorf("drop database: %v", err)
+		}
+	})
+
+	cfg := *dbConfig
+	cfg.DBName = database
+	m, err := migrator.New(cfg.DatabaseURL(), migrationsPath)
+	if err != nil {
+		t.Fatalf("open migrator: %v", err)
+	}
+	// Соединение закрывается раньше, чем база удаляется: Cleanup вызываются в обратном порядке
+	t.Cleanup(func() { m.Close() })
+
+	if err := m.Up(0); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	applied, err := m.Plan(0)
+	if err != nil {
+		t.Fatalf("plan down: %v", err)
+	}
+	if err := m.Down(len(applied)); err != nil {
+		t.Fatalf("down %d: %v", len(applied), err)
+	}
+
+	if version, _, err := m.Version(); err != nil || version != 0 {
+		t.Fatalf("version after down = %d (%v), want 0", version, err)
+	}
+	drifts, err := m.Check(context.Background(), models.Tables...)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if len(drifts) != len(models.Tables) {
+		t.Errorf("after down got %d drifts, want every table missing: %v", len(drifts), drifts)
+	}
+
+	if err := m.Up(0); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+	checkSchema(t, m)
+}
+
+func checkSchema(t *testing.T, m *migrator.Migrator) {
+	t.Helper()
+
+	drifts, err := m.Check(context.Background(), models.Tables...)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	for _, d := range drifts {
+		t.Errorf("schema drift: %s", d)
+	}
+}
@@ -0,0 +1,381 @@
+//go:build integration
+
+package integration
+
+import (
+	"music-lib/internal/lib/api/request"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// seedArtists создаёт артистов Muse (ID 1) и Radiohead (ID 2).
+func seedArtists(t *testing.T) {
+	t.Helper()
+
+	runSteps(t, []step{
+		{name: "seed Muse", method: http.MethodPost, path: "/artists", as: editor, body: map[string]any{"name": "Muse", "is_group": true}, status: http.StatusCreated},
+		{name: "seed Radiohead", method: http.MethodPost, path: "/artists", as: editor, body: map[string]any{"name": "Radiohead", "is_group": true}, status: http.StatusCreated},
+	})
+}
+
+func TestSongs(t *testing.T) {
+	reset(t)
+	seedArtists(t)
+
+	runSteps(t, []step{
+		{
+			name: "create", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Uprising", "artist_id": 1},
+			status: http.StatusCreated,
+			want:   map[string]any{"song.ID": 1, "song.name": "Uprising", "song.artist_id": 1},
+		},
+		{
+			name: "create second", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Starlight", "artist_id": 1},
+			status: http.StatusCreated,
+			want:   map[string]any{"song.ID": 2},
+		},
+		{
+			name: "create third", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Creep", "artist_id": 2},
+			status: http.StatusCreated,
+			want:   map[string]any{"song.ID": 3},
+		},
+		{
+			name: "create without fields", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create for missing artist", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Orphan", "artist_id": 404},
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "artist not found"},
+		},
+		{
+			name: "create with track but no album", method: http.MethodPost, path: "/songs", as: editor,
+			body:   map[string]any{"name": "Orphan", "artist_id": 1, "track_number": 1},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "create as viewer", method: http.MethodPost, path: "/songs", as: viewer,
+			body:   map[string]any{"name": "Orphan", "artist_id": 1},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3, "songs.0.name": "Uprising"},
+		},
+		{
+			name: "list by artist name", method: http.MethodGet, path: "/songs?artist=radio",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1, "songs.0.name": "Creep"},
+		},
+		{
+			name: "list sorted by name", method: http.MethodGet, path: "/songs?sort=-name&limit=2",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3, "limit": 2, "songs.0.name": "Uprising", "songs.1.name": "Starlight"},
+		},
+		{
+			name: "list by cursor", method: http.MethodGet, path: "/songs?limit=2&sort=id",
+			status: http.StatusOK,
+			check: func(t *testing.T, res *result) {
+				cursor, _ := res.get(t, "next_cursor").(string)
+				if cursor == "" {
+					t.Fatal("next_cursor is empty")
+				}
+				next := do(t, http.MethodGet, "/songs?limit=2&sort=id&cursor="+cursor, "", nil, nil)
+				if got := next.get(t, "songs.0.name"); got != "Creep" {
+					t.Errorf("next page starts with %v, want Creep", got)
+				}
+			},
+		},
+		{
+			name: "list with invalid cursor", method: http.MethodGet, path: "/songs?cursor=zzz",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "list with unknown sort", method: http.MethodGet, path: "/songs?sort=tempo",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "list with invalid release date", method: http.MethodGet, path: "/songs?release_from=yesterday",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/songs/1",
+			status: http.StatusOK,
+			want:   map[string]any{"song.name": "Uprising"},
+		},
+		{
+			name: "get missing", method: http.MethodGet, path: "/songs/404",
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "song not found"},
+		},
+		{
+			name: "get with unknown include", method: http.MethodGet, path: "/songs/1?include=tempo",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "update", method: http.MethodPut, path: "/songs/2", as: editor,
+			body:   map[string]any{"name": "Starlight (Live)"},
+			status: http.StatusOK,
+			want:   map[string]any{"song.name": "Starlight (Live)", "song.artist_id": 1},
+		},
+		{
+			name: "update to missing artist", method: http.MethodPut, path: "/songs/2", as: editor,
+			body:   map[string]any{"artist_id": 404},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "update missing", method: http.MethodPut, path: "/songs/404", as: editor,
+			body:   map[string]any{"name": "Nothing"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "artist discography", method: http.MethodGet, path: "/artists/1/songs",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 2, "artist.name": "Muse"},
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/songs/3", as: editor,
+			status: http.StatusOK,
+		},
+		{
+			name: "get deleted", method: http.MethodGet, path: "/songs/3",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete missing", method: http.MethodDelete, path: "/songs/3", as: editor,
+			status: http.StatusNotFound,
+		},
+		{
+			name: "restore", method: http.MethodPost, path: "/songs/3/restore", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"song.name": "Creep"},
+		},
+		{
+			name: "restore not deleted", method: http.MethodPost, path: "/songs/3/restore", as: editor,
+			status: http.StatusNotFound,
+		},
+	})
+}
+
+func TestSongDetails(t *testing.T) {
+	reset(t)
+	seedArtists(t)
+
+	runSteps(t, []step{
+		{name: "seed song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Uprising", "artist_id": 1}, status: http.StatusCreated},
+		{
+			name: "get before put", method: http.MethodGet, path: "/songs/1/details",
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "song detail not found"},
+		},
+		{
+			name: "patch before put", method: http.MethodPatch, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"text": "x"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "put", method: http.MethodPut, path: "/songs/1/details", as: editor,
+			body: map[string]any{
+				"text":         "Paranoia is in bloom\nThe PR transmissions will resume\n\nThey will not force us\nThey will stop degrading us",
+				"release_date": "07.09.2009",
+				"link":         "https://example.com/uprising",
+			},
+			status: http.StatusOK,
+			want:   map[string]any{"song_detail.song_id": 1, "song_detail.link": "https://example.com/uprising"},
+		},
+		{
+			name: "put without text", method: http.MethodPut, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"release_date": "2009-09-07"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "put with invalid date", method: http.MethodPut, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"text": "x", "release_date": "2009-13-45"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "put for missing song", method: http.MethodPut, path: "/songs/404/details", as: editor,
+			body:   map[string]any{"text": "x", "release_date": "2009-09-07"},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/songs/1/details",
+			status: http.StatusOK,
+			want:   map[string]any{"song_detail.release_date": "2009-09-07T00:00:00Z"},
+		},
+		{
+			name: "get song with details", method: http.MethodGet, path: "/songs/1?include=details",
+			status: http.StatusOK,
+			want:   map[string]any{"song.artist.name": "Muse", "song.song_detail.link": "https://example.com/uprising"},
+		},
+		{
+			name: "list by release date", method: http.MethodGet, path: "/songs?release_from=2009-01-01&release_to=31.12.2009&has_link=true",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1},
+		},
+		{
+			name: "list by text", method: http.MethodGet, path: "/songs?text=transmissions",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1},
+		},
+		{
+			name: "lyrics", method: http.MethodGet, path: "/songs/1/lyrics?per_page=1&page=2",
+			status: http.StatusOK,
+			want: map[string]any{
+				"song": "Uprising", "artist": "Muse", "total_verses": 2, "total_pages": 2,
+				"verses.0.number": 2, "verses.0.text": "They will not force us\nThey will stop degrading us",
+			},
+		},
+		{
+			name: "lyrics past last page", method: http.MethodGet, path: "/songs/1/lyrics?per_page=50&page=9223372036854775807",
+			status: http.StatusOK,
+			want:   map[string]any{"total_pages": 1, "verses": []any{}},
+		},
+		{
+			name: "lyrics with invalid page", method: http.MethodGet, path: "/songs/1/lyrics?page=0",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "lyrics of missing song", method: http.MethodGet, path: "/songs/404/lyrics",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "patch", method: http.MethodPatch, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"text": "Paranoia is in bloom\nThe PR transmissions will resume"},
+			status: http.StatusOK,
+			want:   map[string]any{"song_detail.link": "https://example.com/uprising"},
+		},
+		{
+			name: "patch with invalid link", method: http.MethodPatch, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"link": "not a link"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "patch with too long text", method: http.MethodPatch, path: "/songs/1/details", as: editor,
+			body:   map[string]any{"text": strings.Repeat("a\n", 10001)},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "patch with too large body", method: http.MethodPatch, path: "/songs/1/details", as: editor,
+			body:   `{"text": "` + strings.Repeat("a", request.MaxJSONBytes) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "revisions", method: http.MethodGet, path: "/songs/1/lyrics/revisions",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 2, "revisions.0.number": 2},
+		},
+		{
+			name: "revision", method: http.MethodGet, path: "/songs/1/lyrics/revisions/1",
+			status: http.StatusOK,
+			want:   map[string]any{"revision.number": 1, "revision.user_id": 2},
+		},
+		{
+			name: "missing revision", method: http.MethodGet, path: "/songs/1/lyrics/revisions/9",
+			status: http.StatusNotFound,
+			want:   map[string]any{"error": "lyrics revision not found"},
+		},
+		{
+			name: "diff", method: http.MethodGet, path: "/songs/1/lyrics/revisions/diff?from=1&to=2",
+			status: http.StatusOK,
+			want:   map[string]any{"from": 1, "to": 2, "added": 0, "removed": 3},
+		},
+		{
+			name: "diff without to", method: http.MethodGet, path: "/songs/1/lyrics/revisions/diff?from=1",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "restore revision", method: http.MethodPost, path: "/songs/1/lyrics/revisions/1/restore", as: editor,
+			status: http.StatusOK,
+			want:   map[string]any{"song_detail.link": "https://example.com/uprising"},
+		},
+		{
+			name: "restore creates revision", method: http.MethodGet, path: "/songs/1/lyrics/revisions",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 3},
+		},
+		{
+			name: "restore missing revision", method: http.MethodPost, path: "/songs/1/lyrics/revisions/9/restore", as: editor,
+			status: http.StatusNotFound,
+		},
+		{
+			name: "delete", method: http.MethodDelete, path: "/songs/1/details", as: editor,
+			status: http.StatusOK,
+		},
+		{
+			name: "delete again", method: http.MethodDelete, path: "/songs/1/details", as: editor,
+			status: http.StatusNotFound,
+		},
+	})
+}
+
+func TestSongCredits(t *testing.T) {
+	reset(t)
+	seedArtists(t)
+
+	runSteps(t, []step{
+		{name: "seed song", method: http.MethodPost, path: "/songs", as: editor, body: map[string]any{"name": "Uprising", "artist_id": 1}, status: http.StatusCreated},
+		{
+			name: "set", method: http.MethodPut, path: "/songs/1/credits", as: editor,
+			body: map[string]any{"credits": []map[string]any{
+				{"artist_id": 2, "role": "primary"},
+				{"artist_id": 1, "role": "producer"},
+			}},
+			status: http.StatusOK,
+			want:   map[string]any{"song_id": 1, "credits.0.artist_id": 2, "credits.1.role": "producer"},
+		},
+		{
+			name: "primary becomes song artist", method: http.MethodGet, path: "/songs/1",
+			status: http.StatusOK,
+			want:   map[string]any{"song.artist_id": 2},
+		},
+		{
+			name: "get", method: http.MethodGet, path: "/songs/1/credits",
+			status: http.StatusOK,
+			want:   map[string]any{"credits.0.role": "primary", "credits.0.artist.name": "Radiohead"},
+		},
+		{
+			name: "list by credit", method: http.MethodGet, path: "/songs?credit_artist_id=1&role=producer",
+			status: http.StatusOK,
+			want:   map[string]any{"total": 1},
+		},
+		{
+			name: "set twice the same credit", method: http.MethodPut, path: "/songs/1/credits", as: editor,
+			body: map[string]any{"credits": []map[string]any{
+				{"artist_id": 1, "role": "primary"},
+				{"artist_id": 1, "role": "primary"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "set unknown role", method: http.MethodPut, path: "/songs/1/credits", as: editor,
+			body:   map[string]any{"credits": []map[string]any{{"artist_id": 1, "role": "drummer"}}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "set missing artist", method: http.MethodPut, path: "/songs/1/credits", as: editor,
+			body:   map[string]any{"credits": []map[string]any{{"artist_id": 404, "role": "primary"}}},
+			status: http.StatusUnprocessableEntity,
+		},
+		{name: "seed trashed artist", method: http.MethodPost, path: "/artists", as: editor, body: map[string]any{"name": "Blur", "is_group": true}, status: http.StatusCreated},
+		{name: "trash artist", method: http.MethodDelete, path: "/artists/3", as: admin, status: http.StatusOK},
+		{
+			name: "set trashed artist", method: http.MethodPut, path: "/songs/1/credits", as: editor,
+			body: map[string]any{"credits": []map[string]any{
+				{"artist_id": 2, "role": "primary"},
+				{"artist_id": 3, "role": "featured"},
+			}},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "get for missing song", method: http.MethodGet, path: "/songs/404/credits",
+			status: http.StatusNotFound,
+		},
+	})
+}